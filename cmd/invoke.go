@@ -0,0 +1,372 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// invokeFormats are the output formats of the invoke command.
+var invokeFormats = []string{"json", "table", "csv"}
+
+// invokeOptions are the flags of the invoke command.
+type invokeOptions struct {
+	params     []string
+	paramsJSON string
+	headers    []string
+	format     string
+}
+
+// newInvokeCommand returns the command that invokes a tool once, without
+// starting the server.
+func newInvokeCommand(cmd *Command) *cobra.Command {
+	var opts invokeOptions
+	invokeCmd := &cobra.Command{
+		Use:   "invoke <tool>",
+		Short: "Invoke a tool once and print its result.",
+		Long: "Invoke a tool once and print its result. Only the source of the tool is initialized. " +
+			"Parameters are given with --params-json, and overridden by --param.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runInvoke(c, cmd, args[0], opts)
+			if err != nil {
+				// errors of the CLI are silenced, and logged by the commands
+				fmt.Fprintln(c.ErrOrStderr(), err)
+			}
+			return err
+		},
+	}
+	flags := invokeCmd.Flags()
+	flags.StringVar(&cmd.tools_file, "tools-file", "", "File path specifying the tool configuration. Cannot be used with --tools-files or --tools-folder.")
+	flags.StringSliceVar(&cmd.tools_files, "tools-files", []string{}, "Multiple file paths specifying tool configurations. Files will be merged. Cannot be used with --tools-file or --tools-folder.")
+	flags.StringVar(&cmd.tools_folder, "tools-folder", "", "Directory path containing YAML tool configuration files. Cannot be used with --tools-file or --tools-files.")
+	flags.StringSliceVar(&cmd.prebuiltConfigs, "prebuilt", []string{}, "Use prebuilt tool configurations by source type. Multiple values are merged with each other and with the tools file(s), in this order.")
+	flags.StringSliceVar(&cmd.override, "override", []string{}, "Resources that later tools files or prebuilt configurations may declare again, replacing the earlier declaration, in the form of '<section>/<name>'.")
+	flags.StringSliceVar(&cmd.exclude, "exclude", []string{}, "Resources that are dropped from every tools file and prebuilt configuration, in the form of '<section>/<name>'.")
+	flags.StringVar(&cmd.cfg.PageTokenKey, "page-token-key", "", "Key that the page tokens of paginated tools are signed with, so that the next page can be requested by another invocation. Defaults to a random key.")
+	flags.StringArrayVar(&opts.params, "param", []string{}, "Parameter of the tool, in the form of 'name=value'. Values of parameters that aren't strings are parsed as JSON. Can be repeated.")
+	flags.StringVar(&opts.paramsJSON, "params-json", "", "Parameters of the tool, as a JSON object.")
+	flags.StringArrayVar(&opts.headers, "header", []string{}, "Header of the invocation, in the form of 'name: value', such as the tokens of auth services ('my-auth_token: ...') or 'Authorization: Bearer ...'. Can be repeated.")
+	flags.StringVar(&opts.format, "format", "json", fmt.Sprintf("Output format of the result. Allowed: '%s'.", strings.Join(invokeFormats, "', '")))
+	return invokeCmd
+}
+
+func runInvoke(c *cobra.Command, cmd *Command, toolName string, opts invokeOptions) error {
+	if !slices.Contains(invokeFormats, opts.format) {
+		return fmt.Errorf("invalid --format %q (must be one of %q)", opts.format, invokeFormats)
+	}
+	header, err := parseHeaders(opts.headers)
+	if err != nil {
+		return err
+	}
+
+	// the result is written to the out stream, so logs are written to the err stream
+	logger, err := log.NewStdLogger(c.ErrOrStderr(), c.ErrOrStderr(), "WARN")
+	if err != nil {
+		return fmt.Errorf("unable to initialize logger: %w", err)
+	}
+	cmd.logger = logger
+	ctx := util.WithLogger(c.Context(), logger)
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		return fmt.Errorf("unable to create telemetry instrumentation: %w", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	loader, err := cmd.toolsFileLoader()
+	if err != nil {
+		return err
+	}
+	toolsFile, err := cmd.loadToolsFile(ctx, loader)
+	if err != nil {
+		return err
+	}
+	cfg, err := invokeConfig(toolsFile, toolName)
+	if err != nil {
+		return err
+	}
+	cfg.Version = cmd.cfg.Version
+
+	srcs, authServices, toolsMap, _, _, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("unable to initialize tool %q: %w", toolName, err)
+	}
+	defer func() {
+		for _, s := range srcs {
+			if closer, ok := s.(sources.Closer); ok {
+				_ = closer.Close(ctx)
+			}
+		}
+	}()
+	tool := toolsMap[toolName]
+
+	accessToken := tools.AccessToken(header.Get("Authorization"))
+	if tool.RequiresClientAuthorization() && accessToken == "" {
+		return fmt.Errorf("tool requires client authorization but the 'Authorization' header is missing")
+	}
+	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
+	claimsFromAuth := make(map[string]map[string]any)
+	for _, aS := range authServices {
+		claims, err := aS.GetClaimsFromHeader(ctx, header)
+		if err != nil {
+			logger.WarnContext(ctx, err.Error())
+			continue
+		}
+		if claims != nil {
+			claimsFromAuth[aS.GetName()] = claims
+		}
+	}
+	if !tool.Authorized(slices.Collect(maps.Keys(claimsFromAuth))) {
+		return fmt.Errorf("tool invocation not authorized. Please make sure you specify the correct auth headers")
+	}
+	if err := tools.AuthorizePolicies(toolName, tool, claimsFromAuth); err != nil {
+		return err
+	}
+
+	data, err := invokeParams(tool, opts)
+	if err != nil {
+		return err
+	}
+	params, err := tool.ParseParams(data, claimsFromAuth)
+	if err != nil {
+		return fmt.Errorf("provided parameters were invalid: %w", err)
+	}
+	// the rows are formatted by the --format flag instead of the result
+	// format of the tool
+	ctx = tools.WithResultFormat(ctx, tools.RowsJSON)
+	pageTokens, err := tools.NewPageTokenSigner([]byte(cmd.cfg.PageTokenKey), 0)
+	if err != nil {
+		return err
+	}
+	ctx = tools.WithPageTokenSigner(ctx, pageTokens)
+	ctx = tools.WithPageTokenCaller(ctx, claimsFromAuth)
+	res, err := tools.InvokeWithTimeout(ctx, tool, params, accessToken)
+	if err != nil {
+		return fmt.Errorf("error while invoking tool: %w", err)
+	}
+	return writeResult(c.OutOrStdout(), c.ErrOrStderr(), opts.format, res)
+}
+
+// invokeConfig returns the config to initialize a tool with. It only has the
+// source of the tool, and the toolsets that include the tool so that their
+// policies are applied.
+func invokeConfig(toolsFile ToolsFile, toolName string) (server.ServerConfig, error) {
+	tc, ok := toolsFile.Tools[toolName]
+	if !ok {
+		return server.ServerConfig{}, fmt.Errorf("tool %q is not declared", toolName)
+	}
+	cfg := server.ServerConfig{
+		SourceConfigs:      make(server.SourceConfigs),
+		AuthServiceConfigs: toolsFile.AuthServices,
+		ToolConfigs:        server.ToolConfigs{toolName: tc},
+		ToolsetConfigs:     make(server.ToolsetConfigs),
+	}
+	if toolsFile.AuthSources != nil {
+		cfg.AuthServiceConfigs = toolsFile.AuthSources
+	}
+	if source, ok := configField[string](tc, "Source"); ok {
+		if sc, ok := toolsFile.Sources[source]; ok {
+			cfg.SourceConfigs[source] = sc
+		}
+	}
+	for name, ts := range toolsFile.Toolsets {
+		if slices.Contains(ts.ToolNames, toolName) {
+			ts.ToolNames = []string{toolName}
+			cfg.ToolsetConfigs[name] = ts
+		}
+	}
+	return cfg, nil
+}
+
+// invokeParams returns the parameters given by the flags. Values of --param
+// are parsed as JSON, unless the tool declares the parameter as a string.
+func invokeParams(tool tools.Tool, opts invokeOptions) (map[string]any, error) {
+	data := make(map[string]any)
+	if opts.paramsJSON != "" {
+		if err := util.DecodeJSON(strings.NewReader(opts.paramsJSON), &data); err != nil {
+			return nil, fmt.Errorf("--params-json was invalid JSON: %w", err)
+		}
+	}
+	types := make(map[string]string)
+	for _, p := range tool.Manifest().Parameters {
+		types[p.Name] = p.Type
+	}
+	for _, p := range opts.params {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --param %q (must be in the form of 'name=value')", p)
+		}
+		if typ, ok := types[name]; !ok || typ == "string" {
+			data[name] = value
+			continue
+		}
+		var v any
+		if err := util.DecodeJSON(strings.NewReader(value), &v); err != nil {
+			return nil, fmt.Errorf("invalid value for parameter %q of type %q: %w", name, types[name], err)
+		}
+		data[name] = v
+	}
+	return data, nil
+}
+
+// parseHeaders parses headers in the form of 'name: value'.
+func parseHeaders(headers []string) (http.Header, error) {
+	header := make(http.Header)
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --header %q (must be in the form of 'name: value')", h)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header, nil
+}
+
+// writeResult writes the result of a tool in the given format. The rows of
+// a table or CSV do not have room for the markers of truncated rows or of a
+// next page, which are written to errW instead.
+func writeResult(w, errW io.Writer, format string, res any) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	res, marker := unwrapResult(res)
+	columns, rows, err := resultRows(res)
+	if err != nil {
+		return err
+	}
+	if marker != "" {
+		defer fmt.Fprintln(errW, marker)
+	}
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// unwrapResult returns the rows of a result with truncated rows or with a
+// next page, along with a message about them.
+func unwrapResult(res any) (any, string) {
+	switch r := res.(type) {
+	case tools.EncodedRows:
+		return unwrapResult(r.Result)
+	case tools.TruncatedRows:
+		return r.Rows, fmt.Sprintf("The result was truncated to %d rows by the result limits.", r.RowCount)
+	case tools.PagedRows:
+		if r.NextPageToken == "" {
+			return r.Rows, ""
+		}
+		return r.Rows, fmt.Sprintf("There are more rows. To get the next page, invoke the tool again with --param %s=%s", tools.PageTokenParameter, r.NextPageToken)
+	}
+	return res, ""
+}
+
+// resultRows returns the columns and rows of a result. Results that are a
+// list of objects have a column for every key of the objects, sorted by name.
+// Other results have a single "result" column, with a row for each item of a
+// list.
+func resultRows(res any) ([]string, [][]string, error) {
+	// normalize the result to JSON types
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to marshal result: %w", err)
+	}
+	var v any
+	if err := util.DecodeJSON(bytes.NewReader(b), &v); err != nil {
+		return nil, nil, fmt.Errorf("unable to unmarshal result: %w", err)
+	}
+
+	items, ok := v.([]any)
+	if !ok {
+		items = []any{v}
+	}
+	var objects []map[string]any
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			objects = nil
+			break
+		}
+		objects = append(objects, obj)
+	}
+
+	if len(objects) == 0 {
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			rows[i] = []string{formatValue(item)}
+		}
+		return []string{"result"}, rows, nil
+	}
+	keys := make(map[string]bool)
+	for _, obj := range objects {
+		for k := range obj {
+			keys[k] = true
+		}
+	}
+	columns := slices.Sorted(maps.Keys(keys))
+	rows := make([][]string, len(objects))
+	for i, obj := range objects {
+		rows[i] = make([]string, len(columns))
+		for j, col := range columns {
+			rows[i][j] = formatValue(obj[col])
+		}
+	}
+	return columns, rows, nil
+}
+
+// formatValue formats a value of a result as a table or csv cell.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	_ "modernc.org/sqlite"
+)
+
+// writeInvokeToolsFile creates a SQLite database with a few rows, and returns
+// the path of a tools file with tools that query it.
+func writeInvokeToolsFile(t *testing.T) string {
+	dbPath := filepath.Join(t.TempDir(), "invoke.db")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+	stmt := `
+		CREATE TABLE hotels (id INTEGER PRIMARY KEY, name TEXT, city TEXT);
+		INSERT INTO hotels (id, name, city) VALUES (1, 'Hilton Basel', 'Basel'), (2, 'Hyatt, Zurich', 'Zurich'), (3, 'Holiday Inn Basel', 'Basel');
+	`
+	if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("unable to create table: %s", err)
+	}
+
+	paths := writeToolsFiles(t, fmt.Sprintf(`
+	sources:
+		my-sqlite:
+			kind: sqlite
+			database: %s
+		unused-sqlite:
+			kind: sqlite
+			database: %s
+	authServices:
+		my-google-auth:
+			kind: google
+			clientId: my_client_id
+	tools:
+		search-hotels:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Search hotels by city.
+			statement: SELECT id, name FROM hotels WHERE city = ? AND id >= ? ORDER BY id
+			parameters:
+				- name: city
+				  type: string
+				  description: City of the hotels.
+				- name: min_id
+				  type: integer
+				  description: Minimum id of the hotels.
+		list-hotels:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: List hotels.
+			statement: SELECT id, name FROM hotels ORDER BY id
+			pageSize: 2
+		count-hotels:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Count hotels.
+			statement: SELECT COUNT(*) AS count FROM hotels
+			authRequired:
+				- my-google-auth
+	`, dbPath, filepath.Join(t.TempDir(), "missing", "unused.db")))
+	return paths[0]
+}
+
+func TestInvoke(t *testing.T) {
+	toolsFile := writeInvokeToolsFile(t)
+	tcs := []struct {
+		desc string
+		args []string
+		want string
+	}{
+		{
+			desc: "json",
+			args: []string{"--param", "city=Basel", "--param", "min_id=1"},
+			want: "[\n  {\n    \"id\": 1,\n    \"name\": \"Hilton Basel\"\n  },\n  {\n    \"id\": 3,\n    \"name\": \"Holiday Inn Basel\"\n  }\n]\n",
+		},
+		{
+			desc: "params json",
+			args: []string{"--params-json", `{"city": "Basel", "min_id": 2}`},
+			want: "[\n  {\n    \"id\": 3,\n    \"name\": \"Holiday Inn Basel\"\n  }\n]\n",
+		},
+		{
+			desc: "param overrides params json",
+			args: []string{"--params-json", `{"city": "Basel", "min_id": 2}`, "--param", "min_id=1", "--format", "table"},
+			want: "id  name\n1   Hilton Basel\n3   Holiday Inn Basel\n",
+		},
+		{
+			desc: "csv",
+			args: []string{"--param", "city=Zurich", "--param", "min_id=0", "--format", "csv"},
+			want: "id,name\n2,\"Hyatt, Zurich\"\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			args := append([]string{"invoke", "search-hotels", "--tools-file", toolsFile}, tc.args...)
+			_, output, err := invokeCommand(args)
+			if err != nil {
+				t.Fatalf("unexpected error: %s, output: %s", err, output)
+			}
+			if output != tc.want {
+				t.Fatalf("unexpected output: got %q, want %q", output, tc.want)
+			}
+		})
+	}
+}
+
+func TestFailInvoke(t *testing.T) {
+	toolsFile := writeInvokeToolsFile(t)
+	tcs := []struct {
+		desc string
+		args []string
+		want string
+	}{
+		{
+			desc: "missing tool",
+			args: []string{"missing-tool"},
+			want: `tool "missing-tool" is not declared`,
+		},
+		{
+			desc: "invalid param",
+			args: []string{"search-hotels", "--param", "city"},
+			want: `invalid --param "city" (must be in the form of 'name=value')`,
+		},
+		{
+			desc: "invalid param value",
+			args: []string{"search-hotels", "--param", "city=Basel", "--param", "min_id=one"},
+			want: `invalid value for parameter "min_id" of type "integer"`,
+		},
+		{
+			desc: "missing param",
+			args: []string{"search-hotels", "--param", "city=Basel"},
+			want: `provided parameters were invalid: parameter "min_id" is required`,
+		},
+		{
+			desc: "invalid header",
+			args: []string{"count-hotels", "--header", "my-google-auth_token"},
+			want: `invalid --header "my-google-auth_token" (must be in the form of 'name: value')`,
+		},
+		{
+			desc: "unauthorized",
+			args: []string{"count-hotels"},
+			want: "tool invocation not authorized",
+		},
+		{
+			desc: "invalid format",
+			args: []string{"search-hotels", "--format", "xml"},
+			want: `invalid --format "xml" (must be one of ["json" "table" "csv"])`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			args := append([]string{"invoke", "--tools-file", toolsFile}, tc.args...)
+			_, _, err := invokeCommand(args)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestInvokePagination(t *testing.T) {
+	toolsFile := writeInvokeToolsFile(t)
+	args := []string{"invoke", "list-hotels", "--tools-file", toolsFile, "--page-token-key", "secret", "--format", "csv"}
+	_, output, err := invokeCommand(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %s, output: %s", err, output)
+	}
+	rows, marker, ok := strings.Cut(output, "There are more rows.")
+	if !ok {
+		t.Fatalf("expected the token of the next page, got %q", output)
+	}
+	if want := "id,name\n1,Hilton Basel\n2,\"Hyatt, Zurich\"\n"; rows != want {
+		t.Fatalf("unexpected first page: got %q, want %q", rows, want)
+	}
+	_, param, _ := strings.Cut(strings.TrimSpace(marker), "--param ")
+
+	_, output, err = invokeCommand(append(args, "--param", param))
+	if err != nil {
+		t.Fatalf("unexpected error: %s, output: %s", err, output)
+	}
+	if want := "id,name\n3,Holiday Inn Basel\n"; output != want {
+		t.Fatalf("unexpected last page: got %q, want %q", output, want)
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	rows := []any{map[string]any{"id": 1, "name": "Hilton Basel"}}
+	tcs := []struct {
+		desc    string
+		format  string
+		res     any
+		want    string
+		wantErr string
+	}{
+		{
+			desc:    "truncated rows",
+			format:  "table",
+			res:     tools.TruncatedRows{Rows: rows, Truncated: true, RowCount: 1},
+			want:    "id  name\n1   Hilton Basel\n",
+			wantErr: "The result was truncated to 1 rows by the result limits.\n",
+		},
+		{
+			desc:    "page",
+			format:  "csv",
+			res:     tools.PagedRows{Rows: rows, NextPageToken: "next"},
+			want:    "id,name\n1,Hilton Basel\n",
+			wantErr: "There are more rows. To get the next page, invoke the tool again with --param pageToken=next\n",
+		},
+		{
+			desc:   "last page",
+			format: "csv",
+			res:    tools.PagedRows{Rows: rows},
+			want:   "id,name\n1,Hilton Basel\n",
+		},
+		{
+			desc:   "json",
+			format: "json",
+			res:    tools.PagedRows{Rows: rows, NextPageToken: "next"},
+			want:   "{\n  \"rows\": [\n    {\n      \"id\": 1,\n      \"name\": \"Hilton Basel\"\n    }\n  ],\n  \"nextPageToken\": \"next\"\n}\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if err := writeResult(&out, &errOut, tc.format, tc.res); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if out.String() != tc.want {
+				t.Fatalf("unexpected output: got %q, want %q", out.String(), tc.want)
+			}
+			if errOut.String() != tc.wantErr {
+				t.Fatalf("unexpected error output: got %q, want %q", errOut.String(), tc.wantErr)
+			}
+		})
+	}
+}
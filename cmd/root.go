@@ -17,12 +17,13 @@ package cmd
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
-	"maps"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
 	"slices"
@@ -32,11 +33,15 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	yaml "github.com/goccy/go-yaml"
-	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+	_ "github.com/googleapis/genai-toolbox/internal/secrets/file"
+	_ "github.com/googleapis/genai-toolbox/internal/secrets/gcpsm"
+	_ "github.com/googleapis/genai-toolbox/internal/secrets/vault"
 	"github.com/googleapis/genai-toolbox/internal/server"
-	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -236,15 +241,17 @@ func Execute() {
 type Command struct {
 	*cobra.Command
 
-	cfg            server.ServerConfig
-	logger         log.Logger
-	tools_file     string
-	tools_files    []string
-	tools_folder   string
-	prebuiltConfig string
-	inStream       io.Reader
-	outStream      io.Writer
-	errStream      io.Writer
+	cfg             server.ServerConfig
+	logger          log.Logger
+	tools_file      string
+	tools_files     []string
+	tools_folder    string
+	prebuiltConfigs []string
+	override        []string
+	exclude         []string
+	inStream        io.Reader
+	outStream       io.Writer
+	errStream       io.Writer
 }
 
 // NewCommand returns a Command object representing an invocation of the CLI.
@@ -284,12 +291,12 @@ func NewCommand(opts ...Option) *Command {
 	flags.StringVarP(&cmd.cfg.Address, "address", "a", "127.0.0.1", "Address of the interface the server will listen on.")
 	flags.IntVarP(&cmd.cfg.Port, "port", "p", 5000, "Port the server will listen on.")
 
-	flags.StringVar(&cmd.tools_file, "tools_file", "", "File path specifying the tool configuration. Cannot be used with --prebuilt.")
+	flags.StringVar(&cmd.tools_file, "tools_file", "", "File path specifying the tool configuration.")
 	// deprecate tools_file
 	_ = flags.MarkDeprecated("tools_file", "please use --tools-file instead")
-	flags.StringVar(&cmd.tools_file, "tools-file", "", "File path specifying the tool configuration. Cannot be used with --prebuilt, --tools-files, or --tools-folder.")
-	flags.StringSliceVar(&cmd.tools_files, "tools-files", []string{}, "Multiple file paths specifying tool configurations. Files will be merged. Cannot be used with --prebuilt, --tools-file, or --tools-folder.")
-	flags.StringVar(&cmd.tools_folder, "tools-folder", "", "Directory path containing YAML tool configuration files. All .yaml and .yml files in the directory will be loaded and merged. Cannot be used with --prebuilt, --tools-file, or --tools-files.")
+	flags.StringVar(&cmd.tools_file, "tools-file", "", "File path specifying the tool configuration. Cannot be used with --tools-files or --tools-folder.")
+	flags.StringSliceVar(&cmd.tools_files, "tools-files", []string{}, "Multiple file paths specifying tool configurations. Files will be merged. Cannot be used with --tools-file or --tools-folder.")
+	flags.StringVar(&cmd.tools_folder, "tools-folder", "", "Directory path containing YAML tool configuration files. All .yaml and .yml files in the directory will be loaded and merged. Cannot be used with --tools-file or --tools-files.")
 	flags.Var(&cmd.cfg.LogLevel, "log-level", "Specify the minimum level logged. Allowed: 'DEBUG', 'INFO', 'WARN', 'ERROR'.")
 	flags.Var(&cmd.cfg.LoggingFormat, "logging-format", "Specify logging format to use. Allowed: 'standard' or 'JSON'.")
 	flags.BoolVar(&cmd.cfg.TelemetryGCP, "telemetry-gcp", false, "Enable exporting directly to Google Cloud Monitoring.")
@@ -298,17 +305,28 @@ func NewCommand(opts ...Option) *Command {
 
 	// Fetch prebuilt tools sources to customize the help description
 	prebuiltHelp := fmt.Sprintf(
-		"Use a prebuilt tool configuration by source type. Cannot be used with --tools-file. Allowed: '%s'.",
+		"Use prebuilt tool configurations by source type. Multiple values are merged with each other and with the tools file(s), in this order. Allowed: '%s'.",
 		strings.Join(prebuiltconfigs.GetPrebuiltSources(), "', '"),
 	)
-	flags.StringVar(&cmd.prebuiltConfig, "prebuilt", "", prebuiltHelp)
+	flags.StringSliceVar(&cmd.prebuiltConfigs, "prebuilt", []string{}, prebuiltHelp)
+	flags.StringSliceVar(&cmd.override, "override", []string{}, "Resources that later tools files or prebuilt configurations may declare again, replacing the earlier declaration, in the form of '<section>/<name>' (e.g. 'tools/execute_sql').")
+	flags.StringSliceVar(&cmd.exclude, "exclude", []string{}, "Resources that are dropped from every tools file and prebuilt configuration, in the form of '<section>/<name>' (e.g. 'tools/execute_sql').")
 	flags.BoolVar(&cmd.cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
 	flags.BoolVar(&cmd.cfg.DisableReload, "disable-reload", false, "Disables dynamic reloading of tools file.")
 	flags.BoolVar(&cmd.cfg.UI, "ui", false, "Launches the Toolbox UI web server.")
+	flags.BoolVar(&cmd.cfg.FilterToolsByAuth, "filter-tools-by-auth", false, "Omits tools that the caller isn't authorized to invoke from MCP tools/list and the toolset manifest endpoint, based on the auth headers of the request.")
+	flags.IntVar(&cmd.cfg.PageSize, "page-size", 0, "Maximum number of tools returned per page by MCP tools/list and the toolset manifest endpoint. Set to 0 to disable pagination.")
+	flags.StringVar(&cmd.cfg.PageTokenKey, "page-token-key", "", "Key that the page tokens of paginated tools are signed with, so that the tokens stay valid across restarts and server instances. Defaults to a random key.")
+	flags.DurationVar(&cmd.cfg.PageTokenTTL, "page-token-ttl", tools.DefaultPageTokenTTL, "How long the page tokens of paginated tools are valid for.")
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
 
+	baseCmd.AddCommand(newValidateCommand())
+	baseCmd.AddCommand(newInvokeCommand(cmd))
+	// Subcommands would otherwise add a default `completion` command
+	baseCmd.CompletionOptions.DisableDefaultCmd = true
+
 	return cmd
 }
 
@@ -318,19 +336,30 @@ type ToolsFile struct {
 	AuthServices server.AuthServiceConfigs `yaml:"authServices"`
 	Tools        server.ToolConfigs        `yaml:"tools"`
 	Toolsets     server.ToolsetConfigs     `yaml:"toolsets"`
+	Prompts      server.PromptConfigs      `yaml:"prompts"`
+	// Include has the paths of the tools files merged with this one.
+	Include []string `yaml:"include"`
+	// ToolTemplates are the templates that tools may extend.
+	ToolTemplates server.ToolTemplates `yaml:"toolTemplates"`
 }
 
+// envVarRegexp matches the environment variables ${ENV_NAME} and
+// ${ENV_NAME:default_value} of tools files.
+var envVarRegexp = regexp.MustCompile(`\$\{(\w+)(:(\w*))?\}`)
+
 // parseEnv replaces environment variables ${ENV_NAME} with their values.
 // also support ${ENV_NAME:default_value}.
 func parseEnv(input string) (string, error) {
-	re := regexp.MustCompile(`\$\{(\w+)(:(\w*))?\}`)
-
 	var err error
-	output := re.ReplaceAllStringFunc(input, func(match string) string {
-		parts := re.FindStringSubmatch(match)
+	output := envVarRegexp.ReplaceAllStringFunc(input, func(match string) string {
+		parts := envVarRegexp.FindStringSubmatch(match)
 
 		// extract the variable name
 		variableName := parts[1]
+		// secret references such as ${file:secret} are resolved by parseSecrets
+		if parts[2] != "" && secrets.Registered(variableName) {
+			return match
+		}
 		if value, found := os.LookupEnv(variableName); found {
 			return value
 		}
@@ -343,103 +372,312 @@ func parseEnv(input string) (string, error) {
 	return output, err
 }
 
+// secretRefRegexp matches the secret references ${scheme:reference} of tools
+// files, such as ${file:/run/secrets/pg}.
+var secretRefRegexp = regexp.MustCompile(`\$\{([a-z][a-z0-9]*):([^}\s]+)\}`)
+
+// parseSecrets replaces the secret references of registered schemes in a
+// string with the secrets. References of other schemes are left as is.
+func parseSecrets(ctx context.Context, session *secrets.Session, input string) (string, error) {
+	var err error
+	output := secretRefRegexp.ReplaceAllStringFunc(input, func(match string) string {
+		parts := secretRefRegexp.FindStringSubmatch(match)
+		if err != nil || !secrets.Registered(parts[1]) {
+			return match
+		}
+		value, resolveErr := session.Resolve(ctx, parts[1], parts[2])
+		if resolveErr != nil {
+			err = resolveErr
+			return ""
+		}
+		return value
+	})
+	return output, err
+}
+
+// secretResolver replaces the secret references of the string scalars of a
+// YAML document with the secrets. The secrets are never parsed as YAML, so
+// that they are always strings, whatever characters they contain.
+type secretResolver struct {
+	ctx     context.Context
+	session *secrets.Session
+	err     error
+}
+
+func (r *secretResolver) Visit(node ast.Node) ast.Visitor {
+	if r.err != nil {
+		return nil
+	}
+	// block scalars are visited through their string node
+	if n, ok := node.(*ast.StringNode); ok {
+		n.Value, r.err = parseSecrets(r.ctx, r.session, n.Value)
+	}
+	return r
+}
+
+// decodeNode decodes a YAML node into v. A nil node, such as the body of an
+// empty document, leaves v unchanged.
+func decodeNode(ctx context.Context, node ast.Node, v any, opts ...yaml.DecodeOption) error {
+	if node == nil {
+		return nil
+	}
+	return yaml.NewDecoder(strings.NewReader(""), opts...).DecodeFromNodeContext(ctx, node, v)
+}
+
 // parseToolsFile parses the provided yaml into appropriate configs.
 func parseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
+	return parseToolsFileAt(ctx, raw, "", nil)
+}
+
+// parseToolsFileAt parses the yaml of the tools file at path, merged with the
+// tools files it includes. including has the files that include this one, to
+// detect cycles. Tools files without a path, such as prebuilt configurations,
+// can't include files.
+func parseToolsFileAt(ctx context.Context, raw []byte, path string, including []string) (ToolsFile, error) {
 	var toolsFile ToolsFile
 	// Replace environment variables if found
 	output, err := parseEnv(string(raw))
 	if err != nil {
 		return toolsFile, fmt.Errorf("error parsing environment variables: %s", err)
 	}
-	raw = []byte(output)
+	file, err := parser.ParseBytes([]byte(output), 0)
+	if err != nil {
+		return toolsFile, err
+	}
+	var body ast.Node
+	if len(file.Docs) > 0 {
+		body = file.Docs[0].Body
+	}
+	// Replace secret references in the parsed string scalars, after
+	// environment variables, so that secrets are never substituted again nor
+	// parsed as YAML. They are resolved again on each reload.
+	session := secrets.NewSession()
+	resolver := &secretResolver{ctx: ctx, session: session}
+	if body != nil {
+		ast.Walk(resolver, body)
+	}
+	if resolver.err != nil {
+		return toolsFile, fmt.Errorf("error resolving secrets: %w", resolver.err)
+	}
+
+	// The templates, including the ones of the included files, are needed to
+	// decode the tools extending them, so they are parsed first
+	var header struct {
+		Include       []string             `yaml:"include"`
+		ToolTemplates server.ToolTemplates `yaml:"toolTemplates"`
+	}
+	if err := decodeNode(ctx, body, &header); err != nil {
+		return toolsFile, session.RedactError(err)
+	}
+	var included []ToolsFile
+	templates := make(server.ToolTemplates)
+	for _, include := range header.Include {
+		includedFile, err := parseIncludedFile(ctx, include, path, including)
+		if err != nil {
+			return toolsFile, err
+		}
+		included = append(included, includedFile)
+		if err := mergeToolTemplates(templates, includedFile.ToolTemplates); err != nil {
+			return toolsFile, err
+		}
+	}
+	if err := mergeToolTemplates(templates, header.ToolTemplates); err != nil {
+		return toolsFile, err
+	}
+	ctx = server.WithToolTemplates(ctx, templates)
 
 	// Parse contents
-	err = yaml.UnmarshalContext(ctx, raw, &toolsFile, yaml.Strict())
+	err = decodeNode(ctx, body, &toolsFile, yaml.Strict())
 	if err != nil {
-		return toolsFile, err
+		// errors may quote the lines of the secrets
+		return toolsFile, session.RedactError(err)
+	}
+	if len(included) > 0 {
+		toolsFile, err = mergeToolsFiles(mergePolicy{}, append(included, toolsFile)...)
+		if err != nil {
+			return ToolsFile{}, fmt.Errorf("unable to merge included tools files: %w", err)
+		}
+		toolsFile.Include = header.Include
+	}
+	// the templates of included files are also available to the including files
+	if len(templates) > 0 {
+		toolsFile.ToolTemplates = templates
 	}
 	return toolsFile, nil
 }
 
-// mergeToolsFiles merges multiple ToolsFile structs into one.
-// Detects and raises errors for resource conflicts in sources, authServices, tools, and toolsets.
-// All resource names (sources, authServices, tools, toolsets) must be unique across all files.
-func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
-	merged := ToolsFile{
-		Sources:      make(server.SourceConfigs),
-		AuthServices: make(server.AuthServiceConfigs),
-		Tools:        make(server.ToolConfigs),
-		Toolsets:     make(server.ToolsetConfigs),
+// parseIncludedFile parses a tools file included by the tools file at path.
+// Relative paths are relative to the directory of the including file.
+func parseIncludedFile(ctx context.Context, include, path string, including []string) (ToolsFile, error) {
+	if path == "" {
+		return ToolsFile{}, fmt.Errorf("unable to include %q: only tools files can include other files", include)
+	}
+	if !filepath.IsAbs(include) {
+		include = filepath.Join(filepath.Dir(path), include)
+	}
+	include = filepath.Clean(include)
+	chain := append(slices.Clone(including), filepath.Clean(path))
+	if slices.Contains(chain, include) {
+		return ToolsFile{}, fmt.Errorf("tool file at %q is included in a cycle: %s", include, strings.Join(append(chain, include), " -> "))
 	}
 
-	var conflicts []string
+	buf, err := os.ReadFile(include)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to read included tool file at %q: %w", include, err)
+	}
+	toolsFile, err := parseToolsFileAt(ctx, buf, include, chain)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to parse included tool file at %q: %w", include, err)
+	}
+	return toolsFile, nil
+}
 
-	for fileIndex, file := range files {
-		// Check for conflicts and merge sources
-		for name, source := range file.Sources {
-			if _, exists := merged.Sources[name]; exists {
-				conflicts = append(conflicts, fmt.Sprintf("source '%s' (file #%d)", name, fileIndex+1))
-			} else {
-				merged.Sources[name] = source
-			}
+// mergeToolTemplates adds the templates of src to dst. Templates may be
+// declared more than once only with the same config, such as when two
+// included files include the same file.
+func mergeToolTemplates(dst, src server.ToolTemplates) error {
+	for name, t := range src {
+		if prev, ok := dst[name]; ok && !reflect.DeepEqual(prev, t) {
+			return fmt.Errorf("tool template %q is declared more than once", name)
 		}
+		dst[name] = t
+	}
+	return nil
+}
 
-		// Check for conflicts and merge authSources (deprecated, but still support)
-		for name, authSource := range file.AuthSources {
-			if _, exists := merged.AuthSources[name]; exists {
-				conflicts = append(conflicts, fmt.Sprintf("authSource '%s' (file #%d)", name, fileIndex+1))
-			} else {
-				merged.AuthSources[name] = authSource
-			}
-		}
+// mergePolicy resolves the conflicts between the resources of merged tools
+// files. Resources are identified as "<section>/<name>", such as
+// "tools/execute_sql".
+type mergePolicy struct {
+	// override has the resources that later files may declare again, replacing
+	// the earlier declaration.
+	override map[string]bool
+	// exclude has the resources that are dropped from every file.
+	exclude map[string]bool
+}
 
-		// Check for conflicts and merge authServices
-		for name, authService := range file.AuthServices {
-			if _, exists := merged.AuthServices[name]; exists {
-				conflicts = append(conflicts, fmt.Sprintf("authService '%s' (file #%d)", name, fileIndex+1))
+// newMergePolicy returns the policy of the --override and --exclude flags.
+func newMergePolicy(override, exclude []string) (mergePolicy, error) {
+	p := mergePolicy{override: make(map[string]bool), exclude: make(map[string]bool)}
+	for flag, resources := range map[string][]string{"override": override, "exclude": exclude} {
+		for _, r := range resources {
+			section, name, ok := strings.Cut(r, "/")
+			if !ok || name == "" || !slices.Contains(resourceSections, section) {
+				return mergePolicy{}, fmt.Errorf("invalid --%s %q (must be in the form of '<section>/<name>', where section is one of %q)", flag, r, resourceSections)
+			}
+			if flag == "override" {
+				p.override[r] = true
 			} else {
-				merged.AuthServices[name] = authService
+				p.exclude[r] = true
 			}
 		}
+	}
+	return p, nil
+}
 
-		// Check for conflicts and merge tools
-		for name, tool := range file.Tools {
-			if _, exists := merged.Tools[name]; exists {
-				conflicts = append(conflicts, fmt.Sprintf("tool '%s' (file #%d)", name, fileIndex+1))
-			} else {
-				merged.Tools[name] = tool
-			}
+// mergeResources merges the resources of a section of a tools file into
+// merged, and returns the conflicts that the policy doesn't resolve.
+func mergeResources[T any](p mergePolicy, section, kind string, fileIndex int, merged, resources map[string]T) []string {
+	var conflicts []string
+	for name, r := range resources {
+		id := section + "/" + name
+		if p.exclude[id] {
+			continue
+		}
+		if _, exists := merged[name]; exists && !p.override[id] {
+			conflicts = append(conflicts, fmt.Sprintf("%s '%s' (file #%d)", kind, name, fileIndex+1))
+			continue
 		}
+		merged[name] = r
+	}
+	return conflicts
+}
 
-		// Check for conflicts and merge toolsets
-		for name, toolset := range file.Toolsets {
-			if _, exists := merged.Toolsets[name]; exists {
-				conflicts = append(conflicts, fmt.Sprintf("toolset '%s' (file #%d)", name, fileIndex+1))
-			} else {
-				merged.Toolsets[name] = toolset
-			}
+// mergeToolsFiles merges multiple ToolsFile structs into one.
+// Detects and raises errors for resource conflicts in sources, authServices, tools, toolsets, and prompts.
+// All resource names (sources, authServices, tools, toolsets, prompts) must be unique across all files,
+// unless the policy overrides or excludes them.
+func mergeToolsFiles(p mergePolicy, files ...ToolsFile) (ToolsFile, error) {
+	merged := ToolsFile{
+		Sources:      make(server.SourceConfigs),
+		AuthServices: make(server.AuthServiceConfigs),
+		Tools:        make(server.ToolConfigs),
+		Toolsets:     make(server.ToolsetConfigs),
+		Prompts:      make(server.PromptConfigs),
+	}
+
+	var conflicts []string
+
+	for fileIndex, file := range files {
+		conflicts = append(conflicts, mergeResources(p, "sources", "source", fileIndex, merged.Sources, file.Sources)...)
+		// authSources are deprecated, but still supported
+		if file.AuthSources != nil && merged.AuthSources == nil {
+			merged.AuthSources = make(server.AuthServiceConfigs)
 		}
+		conflicts = append(conflicts, mergeResources(p, "authSources", "authSource", fileIndex, merged.AuthSources, file.AuthSources)...)
+		conflicts = append(conflicts, mergeResources(p, "authServices", "authService", fileIndex, merged.AuthServices, file.AuthServices)...)
+		conflicts = append(conflicts, mergeResources(p, "tools", "tool", fileIndex, merged.Tools, file.Tools)...)
+		conflicts = append(conflicts, mergeResources(p, "toolsets", "toolset", fileIndex, merged.Toolsets, file.Toolsets)...)
+		conflicts = append(conflicts, mergeResources(p, "prompts", "prompt", fileIndex, merged.Prompts, file.Prompts)...)
 	}
 
 	// If conflicts were detected, return an error
 	if len(conflicts) > 0 {
-		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, and toolset has a unique name across all files", strings.Join(conflicts, "\n  - "))
+		slices.Sort(conflicts)
+		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, toolset, and prompt has a unique name across all files, or use --override or --exclude", strings.Join(conflicts, "\n  - "))
+	}
+
+	// excluded tools are also removed from the toolsets
+	for name, ts := range merged.Toolsets {
+		ts.ToolNames = slices.DeleteFunc(slices.Clone(ts.ToolNames), func(tool string) bool {
+			return p.exclude["tools/"+tool]
+		})
+		merged.Toolsets[name] = ts
 	}
 
 	return merged, nil
 }
 
-// loadAndMergeToolsFiles loads multiple YAML files and merges them
-func loadAndMergeToolsFiles(ctx context.Context, filePaths []string) (ToolsFile, error) {
+// toolsFileLoader loads the prebuilt configurations and the tools files given
+// by the flags, and merges them in this order.
+type toolsFileLoader struct {
+	prebuilt []string
+	files    []string
+	folder   string
+	policy   mergePolicy
+}
+
+// load loads and merges the prebuilt configurations and the tools files. The
+// files of the folder are listed again on each call.
+func (l toolsFileLoader) load(ctx context.Context) (ToolsFile, error) {
 	var toolsFiles []ToolsFile
+	for _, name := range l.prebuilt {
+		buf, err := prebuiltconfigs.Get(name)
+		if err != nil {
+			return ToolsFile{}, err
+		}
+		toolsFile, err := parseToolsFile(ctx, buf)
+		if err != nil {
+			return ToolsFile{}, fmt.Errorf("unable to parse prebuilt tool configuration %q: %w", name, err)
+		}
+		toolsFiles = append(toolsFiles, toolsFile)
+	}
 
+	filePaths := l.files
+	if l.folder != "" {
+		var err error
+		filePaths, err = toolsFolderFiles(l.folder)
+		if err != nil {
+			return ToolsFile{}, err
+		}
+	}
 	for _, filePath := range filePaths {
 		buf, err := os.ReadFile(filePath)
 		if err != nil {
 			return ToolsFile{}, fmt.Errorf("unable to read tool file at %q: %w", filePath, err)
 		}
 
-		toolsFile, err := parseToolsFile(ctx, buf)
+		toolsFile, err := parseToolsFileAt(ctx, buf, filePath, nil)
 		if err != nil {
 			return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", filePath, err)
 		}
@@ -447,7 +685,7 @@ func loadAndMergeToolsFiles(ctx context.Context, filePaths []string) (ToolsFile,
 		toolsFiles = append(toolsFiles, toolsFile)
 	}
 
-	mergedFile, err := mergeToolsFiles(toolsFiles...)
+	mergedFile, err := mergeToolsFiles(l.policy, toolsFiles...)
 	if err != nil {
 		return ToolsFile{}, fmt.Errorf("unable to merge tools files: %w", err)
 	}
@@ -455,40 +693,38 @@ func loadAndMergeToolsFiles(ctx context.Context, filePaths []string) (ToolsFile,
 	return mergedFile, nil
 }
 
-// loadAndMergeToolsFolder loads all YAML files from a directory and merges them
-func loadAndMergeToolsFolder(ctx context.Context, folderPath string) (ToolsFile, error) {
+// toolsFolderFiles returns the paths of the YAML files of a directory.
+func toolsFolderFiles(folderPath string) ([]string, error) {
 	// Check if directory exists
 	info, err := os.Stat(folderPath)
 	if err != nil {
-		return ToolsFile{}, fmt.Errorf("unable to access tools folder at %q: %w", folderPath, err)
+		return nil, fmt.Errorf("unable to access tools folder at %q: %w", folderPath, err)
 	}
 	if !info.IsDir() {
-		return ToolsFile{}, fmt.Errorf("path %q is not a directory", folderPath)
+		return nil, fmt.Errorf("path %q is not a directory", folderPath)
 	}
 
 	// Find all YAML files in the directory
 	pattern := filepath.Join(folderPath, "*.yaml")
 	yamlFiles, err := filepath.Glob(pattern)
 	if err != nil {
-		return ToolsFile{}, fmt.Errorf("error finding YAML files in %q: %w", folderPath, err)
+		return nil, fmt.Errorf("error finding YAML files in %q: %w", folderPath, err)
 	}
 
 	// Also find .yml files
 	ymlPattern := filepath.Join(folderPath, "*.yml")
 	ymlFiles, err := filepath.Glob(ymlPattern)
 	if err != nil {
-		return ToolsFile{}, fmt.Errorf("error finding YML files in %q: %w", folderPath, err)
+		return nil, fmt.Errorf("error finding YML files in %q: %w", folderPath, err)
 	}
 
 	// Combine both file lists
 	allFiles := append(yamlFiles, ymlFiles...)
 
 	if len(allFiles) == 0 {
-		return ToolsFile{}, fmt.Errorf("no YAML files found in directory %q", folderPath)
+		return nil, fmt.Errorf("no YAML files found in directory %q", folderPath)
 	}
-
-	// Use existing loadAndMergeToolsFiles function
-	return loadAndMergeToolsFiles(ctx, allFiles)
+	return allFiles, nil
 }
 
 func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Server) error {
@@ -497,58 +733,29 @@ func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Ser
 		panic(err)
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile)
-	if err != nil {
-		errMsg := fmt.Errorf("unable to validate reloaded edits: %w", err)
-		logger.WarnContext(ctx, errMsg.Error())
-		return err
-	}
-
-	s.ResourceMgr.SetResources(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
-
-	return nil
-}
-
-// validateReloadEdits checks that the reloaded tools file configs can initialized without failing
-func validateReloadEdits(
-	ctx context.Context, toolsFile ToolsFile,
-) (map[string]sources.Source, map[string]auth.AuthService, map[string]tools.Tool, map[string]tools.Toolset, error,
-) {
-	logger, err := util.LoggerFromContext(ctx)
-	if err != nil {
-		panic(err)
-	}
-
-	instrumentation, err := util.InstrumentationFromContext(ctx)
-	if err != nil {
-		panic(err)
-	}
-
 	logger.DebugContext(ctx, "Attempting to parse and validate reloaded tools file.")
 
-	ctx, span := instrumentation.Tracer.Start(ctx, "toolbox/server/reload")
-	defer span.End()
-
 	reloadedConfig := server.ServerConfig{
 		Version:            versionString,
 		SourceConfigs:      toolsFile.Sources,
 		AuthServiceConfigs: toolsFile.AuthServices,
 		ToolConfigs:        toolsFile.Tools,
 		ToolsetConfigs:     toolsFile.Toolsets,
+		PromptConfigs:      toolsFile.Prompts,
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := server.InitializeConfigs(ctx, reloadedConfig)
-	if err != nil {
-		errMsg := fmt.Errorf("unable to initialize reloaded configs: %w", err)
+	// only the resources that changed are initialized again
+	if _, err := s.Reload(ctx, reloadedConfig); err != nil {
+		errMsg := fmt.Errorf("unable to validate reloaded edits: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
-		return nil, nil, nil, nil, err
+		return err
 	}
 
-	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
+	return nil
 }
 
 // watchChanges checks for changes in the provided yaml tools file(s) or folder.
-func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, s *server.Server) {
+func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, loader toolsFileLoader, s *server.Server) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		panic(err)
@@ -563,7 +770,6 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 	defer w.Close()
 
 	watchingFolder := false
-	var folderToWatch string
 
 	// if watchedFiles is empty, indicates that user passed entire folder instead
 	if len(watchedFiles) == 0 {
@@ -574,11 +780,6 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 			logger.WarnContext(ctx, "error setting watcher, expected single tools folder if no file(s) are defined.")
 			return
 		}
-
-		for onlyKey := range watchDirs {
-			folderToWatch = onlyKey
-			break
-		}
 	}
 
 	for dir := range watchDirs {
@@ -639,18 +840,14 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 
 			if watchingFolder {
 				logger.DebugContext(ctx, "Reloading tools folder.")
-				reloadedToolsFile, err = loadAndMergeToolsFolder(ctx, folderToWatch)
-				if err != nil {
-					logger.WarnContext(ctx, "error loading tools folder %s", err)
-					continue
-				}
 			} else {
 				logger.DebugContext(ctx, "Reloading tools file(s).")
-				reloadedToolsFile, err = loadAndMergeToolsFiles(ctx, slices.Collect(maps.Keys(watchedFiles)))
-				if err != nil {
-					logger.WarnContext(ctx, "error loading tools files %s", err)
-					continue
-				}
+			}
+			// the prebuilt configurations are merged again with the reloaded files
+			reloadedToolsFile, err = loader.load(ctx)
+			if err != nil {
+				logger.WarnContext(ctx, "error loading tools files %s", err)
+				continue
 			}
 
 			err = handleDynamicReload(ctx, reloadedToolsFile, s)
@@ -690,6 +887,53 @@ func resolveWatcherInputs(toolsFile string, toolsFiles []string, toolsFolder str
 	return watchDirs, watchedFiles
 }
 
+// toolsFileLoader returns the loader of the prebuilt configurations and the
+// tools file(s) or tools folder given by the flags of the command.
+func (cmd *Command) toolsFileLoader() (toolsFileLoader, error) {
+	// Make sure --tools-file, --tools-files, and --tools-folder flags are mutually exclusive
+	if (cmd.tools_file != "" && (len(cmd.tools_files) > 0 || cmd.tools_folder != "")) || (len(cmd.tools_files) > 0 && cmd.tools_folder != "") {
+		return toolsFileLoader{}, fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
+	}
+	policy, err := newMergePolicy(cmd.override, cmd.exclude)
+	if err != nil {
+		return toolsFileLoader{}, err
+	}
+
+	l := toolsFileLoader{
+		prebuilt: cmd.prebuiltConfigs,
+		files:    cmd.tools_files,
+		folder:   cmd.tools_folder,
+		policy:   policy,
+	}
+	// Set default value of tools-file flag to tools.yaml, unless only
+	// prebuilt configurations are used
+	if cmd.tools_file == "" && len(l.prebuilt) == 0 && len(l.files) == 0 && l.folder == "" {
+		cmd.tools_file = "tools.yaml"
+	}
+	if cmd.tools_file != "" {
+		l.files = []string{cmd.tools_file}
+	}
+	return l, nil
+}
+
+// loadToolsFile loads and merges the prebuilt configurations and the tools
+// files of the loader.
+func (cmd *Command) loadToolsFile(ctx context.Context, l toolsFileLoader) (ToolsFile, error) {
+	for _, name := range l.prebuilt {
+		logMsg := fmt.Sprint("Using prebuilt tool configuration for ", name)
+		cmd.logger.InfoContext(ctx, logMsg)
+		// Append prebuilt.source to Version string for the User Agent
+		cmd.cfg.Version += "+prebuilt." + name
+	}
+	if len(cmd.tools_files) > 0 {
+		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging %d tool configuration files", len(cmd.tools_files)))
+	}
+	if cmd.tools_folder != "" {
+		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging all YAML files from directory: %s", cmd.tools_folder))
+	}
+	return l.load(ctx)
+}
+
 func run(cmd *Command) error {
 	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
@@ -755,87 +999,18 @@ func run(cmd *Command) error {
 		}
 	}()
 
-	var toolsFile ToolsFile
-
-	if cmd.prebuiltConfig != "" {
-		// Make sure --prebuilt and --tools-file/--tools-files/--tools-folder flags are mutually exclusive
-		if cmd.tools_file != "" || len(cmd.tools_files) > 0 || cmd.tools_folder != "" {
-			errMsg := fmt.Errorf("--prebuilt and --tools-file/--tools-files/--tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-		// Use prebuilt tools
-		buf, err := prebuiltconfigs.Get(cmd.prebuiltConfig)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-		logMsg := fmt.Sprint("Using prebuilt tool configuration for ", cmd.prebuiltConfig)
-		cmd.logger.InfoContext(ctx, logMsg)
-		// Append prebuilt.source to Version string for the User Agent
-		cmd.cfg.Version += "+prebuilt." + cmd.prebuiltConfig
-
-		toolsFile, err = parseToolsFile(ctx, buf)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to parse prebuilt tool configuration: %w", err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-	} else if len(cmd.tools_files) > 0 {
-		// Make sure --tools-file, --tools-files, and --tools-folder flags are mutually exclusive
-		if cmd.tools_file != "" || cmd.tools_folder != "" {
-			errMsg := fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		// Use multiple tools files
-		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging %d tool configuration files", len(cmd.tools_files)))
-		var err error
-		toolsFile, err = loadAndMergeToolsFiles(ctx, cmd.tools_files)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-	} else if cmd.tools_folder != "" {
-		// Make sure --tools-folder and other flags are mutually exclusive
-		if cmd.tools_file != "" || len(cmd.tools_files) > 0 {
-			errMsg := fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		// Use tools folder
-		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging all YAML files from directory: %s", cmd.tools_folder))
-		var err error
-		toolsFile, err = loadAndMergeToolsFolder(ctx, cmd.tools_folder)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-	} else {
-		// Set default value of tools-file flag to tools.yaml
-		if cmd.tools_file == "" {
-			cmd.tools_file = "tools.yaml"
-		}
-
-		// Read single tool file contents
-		buf, err := os.ReadFile(cmd.tools_file)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		toolsFile, err = parseToolsFile(ctx, buf)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
+	loader, err := cmd.toolsFileLoader()
+	if err != nil {
+		cmd.logger.ErrorContext(ctx, err.Error())
+		return err
+	}
+	toolsFile, err := cmd.loadToolsFile(ctx, loader)
+	if err != nil {
+		cmd.logger.ErrorContext(ctx, err.Error())
+		return err
 	}
 
-	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
+	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs, cmd.cfg.PromptConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets, toolsFile.Prompts
 	authSourceConfigs := toolsFile.AuthSources
 	if authSourceConfigs != nil {
 		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
@@ -892,9 +1067,10 @@ func run(cmd *Command) error {
 
 	watchDirs, watchedFiles := resolveWatcherInputs(cmd.tools_file, cmd.tools_files, cmd.tools_folder)
 
-	if !cmd.cfg.DisableReload {
+	// prebuilt configurations don't change, so there is nothing to watch without tools files
+	if !cmd.cfg.DisableReload && (len(loader.files) > 0 || loader.folder != "") {
 		// start watching the file(s) or folder for changes to trigger dynamic reloading
-		go watchChanges(ctx, watchDirs, watchedFiles, s)
+		go watchChanges(ctx, watchDirs, watchedFiles, loader, s)
 	}
 
 	// wait for either the server to error out or the command's context to be canceled
@@ -910,7 +1086,7 @@ func run(cmd *Command) error {
 		defer cancel()
 		cmd.logger.WarnContext(shutdownContext, "Shutting down gracefully...")
 		err := s.Shutdown(shutdownContext)
-		if err == context.DeadlineExceeded {
+		if errors.Is(err, context.DeadlineExceeded) {
 			return fmt.Errorf("graceful shutdown timed out... forcing exit")
 		}
 	}
@@ -0,0 +1,709 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	alloydbadminsrc "github.com/googleapis/genai-toolbox/internal/sources/alloydbadmin"
+	alloydbpgsrc "github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	bigquerysrc "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	bigtablesrc "github.com/googleapis/genai-toolbox/internal/sources/bigtable"
+	cassandrasrc "github.com/googleapis/genai-toolbox/internal/sources/cassandra"
+	clickhousesrc "github.com/googleapis/genai-toolbox/internal/sources/clickhouse"
+	cloudmonitoringsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudmonitoring"
+	cloudsqladminsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqladmin"
+	cloudsqlmssqlsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmssql"
+	cloudsqlmysqlsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmysql"
+	cloudsqlpgsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	couchbasesrc "github.com/googleapis/genai-toolbox/internal/sources/couchbase"
+	dataplexsrc "github.com/googleapis/genai-toolbox/internal/sources/dataplex"
+	dgraphsrc "github.com/googleapis/genai-toolbox/internal/sources/dgraph"
+	firebirdsrc "github.com/googleapis/genai-toolbox/internal/sources/firebird"
+	firestoresrc "github.com/googleapis/genai-toolbox/internal/sources/firestore"
+	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
+	lookersrc "github.com/googleapis/genai-toolbox/internal/sources/looker"
+	mongodbsrc "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
+	mssqlsrc "github.com/googleapis/genai-toolbox/internal/sources/mssql"
+	mysqlsrc "github.com/googleapis/genai-toolbox/internal/sources/mysql"
+	neo4jsrc "github.com/googleapis/genai-toolbox/internal/sources/neo4j"
+	oceanbasesrc "github.com/googleapis/genai-toolbox/internal/sources/oceanbase"
+	oraclesrc "github.com/googleapis/genai-toolbox/internal/sources/oracle"
+	postgressrc "github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	redissrc "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	spannersrc "github.com/googleapis/genai-toolbox/internal/sources/spanner"
+	sqlitesrc "github.com/googleapis/genai-toolbox/internal/sources/sqlite"
+	tidbsrc "github.com/googleapis/genai-toolbox/internal/sources/tidb"
+	trinosrc "github.com/googleapis/genai-toolbox/internal/sources/trino"
+	valkeysrc "github.com/googleapis/genai-toolbox/internal/sources/valkey"
+	yugabytedbsrc "github.com/googleapis/genai-toolbox/internal/sources/yugabytedb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// unconnectedSources are sources of every kind that aren't connected, which
+// tools are initialized with to check that they support the kind of their
+// source.
+var unconnectedSources = map[string]sources.Source{
+	alloydbadminsrc.SourceKind:    &alloydbadminsrc.Source{},
+	alloydbpgsrc.SourceKind:       &alloydbpgsrc.Source{},
+	bigquerysrc.SourceKind:        &bigquerysrc.Source{},
+	bigtablesrc.SourceKind:        &bigtablesrc.Source{},
+	cassandrasrc.SourceKind:       &cassandrasrc.Source{},
+	clickhousesrc.SourceKind:      &clickhousesrc.Source{},
+	cloudmonitoringsrc.SourceKind: &cloudmonitoringsrc.Source{},
+	cloudsqladminsrc.SourceKind:   &cloudsqladminsrc.Source{},
+	cloudsqlmssqlsrc.SourceKind:   &cloudsqlmssqlsrc.Source{},
+	cloudsqlmysqlsrc.SourceKind:   &cloudsqlmysqlsrc.Source{},
+	cloudsqlpgsrc.SourceKind:      &cloudsqlpgsrc.Source{},
+	couchbasesrc.SourceKind:       &couchbasesrc.Source{},
+	dataplexsrc.SourceKind:        &dataplexsrc.Source{},
+	dgraphsrc.SourceKind:          &dgraphsrc.Source{},
+	firebirdsrc.SourceKind:        &firebirdsrc.Source{},
+	firestoresrc.SourceKind:       &firestoresrc.Source{},
+	httpsrc.SourceKind:            &httpsrc.Source{},
+	lookersrc.SourceKind:          &lookersrc.Source{},
+	mongodbsrc.SourceKind:         &mongodbsrc.Source{},
+	mssqlsrc.SourceKind:           &mssqlsrc.Source{},
+	mysqlsrc.SourceKind:           &mysqlsrc.Source{},
+	neo4jsrc.SourceKind:           &neo4jsrc.Source{},
+	oceanbasesrc.SourceKind:       &oceanbasesrc.Source{},
+	oraclesrc.SourceKind:          &oraclesrc.Source{},
+	postgressrc.SourceKind:        &postgressrc.Source{},
+	redissrc.SourceKind:           &redissrc.Source{},
+	spannersrc.SourceKind:         &spannersrc.Source{},
+	sqlitesrc.SourceKind:          &sqlitesrc.Source{},
+	tidbsrc.SourceKind:            &tidbsrc.Source{},
+	trinosrc.SourceKind:           &trinosrc.Source{},
+	valkeysrc.SourceKind:          &valkeysrc.Source{},
+	yugabytedbsrc.SourceKind:      &yugabytedbsrc.Source{},
+}
+
+// validateFormats are the output formats of the validate command.
+var validateFormats = []string{"text", "json"}
+
+// resourceSections are the top-level fields of a tools file that declare
+// resources.
+var resourceSections = []string{"sources", "authSources", "authServices", "tools", "toolsets", "prompts"}
+
+// toolsFileSections are the top-level fields of a tools file.
+var toolsFileSections = append(slices.Clone(resourceSections), "include", "toolTemplates")
+
+var unknownFieldRegexp = regexp.MustCompile(`unknown field "([^"]+)"`)
+
+// problem is an issue found in a tools file by the validate command. Line
+// and column are 0 if the issue isn't found at a position of the file.
+type problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+}
+
+// newValidateCommand returns the command that validates tools files without
+// connecting to their sources.
+func newValidateCommand() *cobra.Command {
+	var toolsFile, toolsFolder, format string
+	var toolsFiles []string
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate tools files without connecting to sources.",
+		Long: "Validate tools files without connecting to sources. Every problem found is reported with its position in the files, " +
+			"and the command fails if there is any.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			err := runValidate(c, toolsFile, toolsFiles, toolsFolder, format)
+			if err != nil && !errors.Is(err, errProblemsFound) {
+				// errors of the CLI are silenced, and logged by the commands
+				fmt.Fprintln(c.ErrOrStderr(), err)
+			}
+			return err
+		},
+	}
+	flags := validateCmd.Flags()
+	flags.StringVar(&toolsFile, "tools-file", "", "File path specifying the tool configuration. Cannot be used with --tools-files, or --tools-folder.")
+	flags.StringSliceVar(&toolsFiles, "tools-files", []string{}, "Multiple file paths specifying tool configurations. Files are validated together. Cannot be used with --tools-file, or --tools-folder.")
+	flags.StringVar(&toolsFolder, "tools-folder", "", "Directory path containing YAML tool configuration files. Cannot be used with --tools-file, or --tools-files.")
+	flags.StringVar(&format, "format", "text", fmt.Sprintf("Output format of the problems. Allowed: '%s'.", strings.Join(validateFormats, "', '")))
+	return validateCmd
+}
+
+// errProblemsFound is returned by the validate command if the tools files
+// have problems, once they are written.
+var errProblemsFound = errors.New("problems found in tools files")
+
+func runValidate(c *cobra.Command, toolsFile string, toolsFiles []string, toolsFolder, format string) error {
+	if !slices.Contains(validateFormats, format) {
+		return fmt.Errorf("invalid --format %q (must be one of %q)", format, validateFormats)
+	}
+	var files []string
+	switch {
+	case (toolsFile != "" && len(toolsFiles) > 0) || (toolsFile != "" && toolsFolder != "") || (len(toolsFiles) > 0 && toolsFolder != ""):
+		return fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
+	case len(toolsFiles) > 0:
+		files = toolsFiles
+	case toolsFolder != "":
+		var err error
+		if files, err = toolsFolderFiles(toolsFolder); err != nil {
+			return err
+		}
+	case toolsFile != "":
+		files = []string{toolsFile}
+	default:
+		files = []string{"tools.yaml"}
+	}
+
+	logger, err := log.NewStdLogger(c.ErrOrStderr(), c.ErrOrStderr(), "WARN")
+	if err != nil {
+		return fmt.Errorf("unable to initialize logger: %w", err)
+	}
+	ctx := util.WithLogger(c.Context(), logger)
+	problems := validateToolsFiles(ctx, files)
+	if err := writeProblems(c.OutOrStdout(), format, problems); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return errProblemsFound
+	}
+	return nil
+}
+
+// writeProblems writes the problems found in the tools files in the given
+// format.
+func writeProblems(w io.Writer, format string, problems []problem) error {
+	if format == "json" {
+		if problems == nil {
+			problems = []problem{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"problems": problems})
+	}
+	if len(problems) == 0 {
+		_, err := fmt.Fprintln(w, "No problems found.")
+		return err
+	}
+	for _, p := range problems {
+		if _, err := fmt.Fprintln(w, p); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "Found %d problem(s).\n", len(problems))
+	return err
+}
+
+// yamlFile is a tools file parsed as a YAML document.
+type yamlFile struct {
+	path string
+	root ast.Node
+}
+
+// validator collects the problems of tools files, and the configs that were
+// decoded from them.
+type validator struct {
+	ctx      context.Context
+	problems []problem
+	merged   ToolsFile
+	// origins are the files where resources are declared, by section and
+	// name, including the resources that failed to decode
+	origins map[string]map[string]*yamlFile
+}
+
+// validateToolsFiles parses and type-checks tools files, and returns every
+// problem found in them, sorted by position.
+func validateToolsFiles(ctx context.Context, paths []string) []problem {
+	v := &validator{
+		ctx: ctx,
+		merged: ToolsFile{
+			Sources:      make(server.SourceConfigs),
+			AuthServices: make(server.AuthServiceConfigs),
+			Tools:        make(server.ToolConfigs),
+			Toolsets:     make(server.ToolsetConfigs),
+			Prompts:      make(server.PromptConfigs),
+		},
+		origins: make(map[string]map[string]*yamlFile),
+	}
+	for _, path := range paths {
+		v.decodeFile(path, nil)
+	}
+	for _, name := range slices.Sorted(maps.Keys(v.merged.Tools)) {
+		v.checkTool(name, v.merged.Tools[name])
+	}
+	for _, name := range slices.Sorted(maps.Keys(v.merged.Toolsets)) {
+		v.checkToolset(name, v.merged.Toolsets[name])
+	}
+	slices.SortStableFunc(v.problems, func(a, b problem) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	// files included more than once report the same problems
+	return slices.Compact(v.problems)
+}
+
+// addf records a problem at the node of path in f.
+func (v *validator) addf(f *yamlFile, path []any, format string, args ...any) {
+	p := problem{File: f.path, Message: fmt.Sprintf(format, args...)}
+	p.Line, p.Column, _ = nodePosition(f.root, path...)
+	v.problems = append(v.problems, p)
+}
+
+// decodeFile decodes the resources of a tools file one by one, so that every
+// resource that fails to decode is reported. The files it includes are
+// decoded first, and including has the files that include this one. It
+// returns the templates that are available to the files including it.
+func (v *validator) decodeFile(path string, including []string) server.ToolTemplates {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		v.problems = append(v.problems, problem{File: path, Message: fmt.Sprintf("unable to read tool file: %s", err)})
+		return nil
+	}
+
+	// Replace environment variables, reporting the ones that aren't set
+	var envErrs []problem
+	lineStarts := []int{0}
+	for i, c := range raw {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	for _, m := range envVarRegexp.FindAllSubmatchIndex(raw, -1) {
+		name := string(raw[m[2]:m[3]])
+		if _, found := os.LookupEnv(name); found || m[4] >= 0 {
+			continue
+		}
+		line, _ := slices.BinarySearch(lineStarts, m[0]+1)
+		envErrs = append(envErrs, problem{File: path, Line: line, Column: m[0] - lineStarts[line-1] + 1, Message: fmt.Sprintf("environment variable not found: %q", name)})
+	}
+	v.problems = append(v.problems, envErrs...)
+	raw = envVarRegexp.ReplaceAllFunc(raw, func(match []byte) []byte {
+		parts := envVarRegexp.FindSubmatch(match)
+		// secrets aren't resolved, so that validation stays offline
+		if parts[2] != nil && secrets.Registered(string(parts[1])) {
+			return match
+		}
+		if value, found := os.LookupEnv(string(parts[1])); found {
+			return []byte(value)
+		}
+		if parts[2] != nil {
+			return parts[3]
+		}
+		// keep the variable so that it isn't also reported as a missing field
+		return match
+	})
+
+	parsed, err := parser.ParseBytes(raw, 0)
+	if err != nil {
+		v.problems = append(v.problems, yamlProblem(path, err))
+		return nil
+	}
+	f := &yamlFile{path: path}
+	if len(parsed.Docs) > 0 {
+		f.root = parsed.Docs[0].Body
+	}
+
+	var content map[string]any
+	if err := yaml.Unmarshal(raw, &content); err != nil {
+		v.problems = append(v.problems, yamlProblem(path, err))
+		return nil
+	}
+
+	// the templates are needed to decode the tools extending them
+	templates := make(server.ToolTemplates)
+	if content["include"] != nil {
+		v.decodeIncludes(f, content["include"], including, templates)
+	}
+	if content["toolTemplates"] != nil {
+		v.decodeToolTemplates(f, content["toolTemplates"], templates)
+	}
+	ctx := server.WithToolTemplates(v.ctx, templates)
+
+	for _, section := range slices.Sorted(maps.Keys(content)) {
+		if !slices.Contains(toolsFileSections, section) {
+			v.addf(f, []any{section}, "unknown field %q", section)
+			continue
+		}
+		if content[section] == nil || !slices.Contains(resourceSections, section) {
+			continue
+		}
+		resources, ok := content[section].(map[string]any)
+		if !ok {
+			v.addf(f, []any{section}, "%q must be a mapping of names to resources", section)
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(resources)) {
+			v.decodeResource(ctx, f, section, name, resources[name])
+		}
+	}
+	return templates
+}
+
+// decodeIncludes decodes the files included by f, and adds their templates
+// to templates.
+func (v *validator) decodeIncludes(f *yamlFile, includes any, including []string, templates server.ToolTemplates) {
+	paths, ok := includes.([]any)
+	if !ok {
+		v.addf(f, []any{"include"}, "%q must be a sequence of file paths", "include")
+		return
+	}
+	chain := append(slices.Clone(including), filepath.Clean(f.path))
+	for i, p := range paths {
+		include, ok := p.(string)
+		if !ok || include == "" {
+			v.addf(f, []any{"include", i}, "included files must be file paths")
+			continue
+		}
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(f.path), include)
+		}
+		include = filepath.Clean(include)
+		if slices.Contains(chain, include) {
+			v.addf(f, []any{"include", i}, "tool file at %q is included in a cycle: %s", include, strings.Join(append(chain, include), " -> "))
+			continue
+		}
+		for name, t := range v.decodeFile(include, chain) {
+			if prev, ok := templates[name]; ok && !reflect.DeepEqual(prev, t) {
+				v.addf(f, []any{"include", i}, "tool template %q is declared more than once", name)
+			}
+			templates[name] = t
+		}
+	}
+}
+
+// decodeToolTemplates adds the templates declared by f to templates.
+func (v *validator) decodeToolTemplates(f *yamlFile, section any, templates server.ToolTemplates) {
+	declared, ok := section.(map[string]any)
+	if !ok {
+		v.addf(f, []any{"toolTemplates"}, "%q must be a mapping of names to templates", "toolTemplates")
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(declared)) {
+		t, ok := declared[name].(map[string]any)
+		if !ok {
+			v.addf(f, []any{"toolTemplates", name}, "tool template %q must be a mapping", name)
+			continue
+		}
+		if prev, ok := templates[name]; ok && !reflect.DeepEqual(prev, t) {
+			v.addf(f, []any{"toolTemplates", name}, "tool template %q is declared more than once", name)
+		}
+		templates[name] = t
+	}
+}
+
+// decodeResource decodes a resource and merges it with the resources of the
+// previous files.
+func (v *validator) decodeResource(ctx context.Context, f *yamlFile, section, name string, body any) {
+	// `authSources` is the deprecated name of `authServices`
+	origin := section
+	if section == "authSources" {
+		origin = "authServices"
+	}
+	if prev, ok := v.origins[origin][name]; ok {
+		v.addf(f, []any{section, name}, "duplicate resource %q in %q, already declared in %s", name, origin, prev.path)
+		return
+	}
+
+	if v.origins[origin] == nil {
+		v.origins[origin] = make(map[string]*yamlFile)
+	}
+	v.origins[origin][name] = f
+
+	var err error
+	switch section {
+	case "sources":
+		err = decodeResource(ctx, name, body, v.merged.Sources)
+	case "authSources", "authServices":
+		err = decodeResource(ctx, name, body, v.merged.AuthServices)
+	case "tools":
+		err = decodeResource(ctx, name, body, v.merged.Tools)
+	case "toolsets":
+		err = decodeResource(ctx, name, body, v.merged.Toolsets)
+	case "prompts":
+		err = decodeResource(ctx, name, body, v.merged.Prompts)
+	}
+	if err != nil {
+		// positions of decoding errors are relative to the resource, so the
+		// resource or its unknown field is reported instead
+		path := []any{section, name}
+		msg := err.Error()
+		var yamlErr yaml.Error
+		if errors.As(err, &yamlErr) {
+			msg = strings.Replace(msg, yamlErr.Error(), yamlErr.GetMessage(), 1)
+		}
+		if m := unknownFieldRegexp.FindStringSubmatch(msg); m != nil {
+			path = append(path, m[1])
+		}
+		v.addf(f, path, "%s", msg)
+	}
+}
+
+// declared reports whether a resource is declared, even if it failed to
+// decode, so that references to it aren't reported as well.
+func (v *validator) declared(section, name string) bool {
+	_, ok := v.origins[section][name]
+	return ok
+}
+
+// decodeResource decodes a single resource with the config type of its
+// section, and adds it to dst.
+func decodeResource[M ~map[string]V, V any](ctx context.Context, name string, body any, dst M) error {
+	raw, err := yaml.Marshal(map[string]any{name: body})
+	if err != nil {
+		return err
+	}
+	var decoded M
+	if err := yaml.UnmarshalContext(ctx, raw, &decoded, yaml.Strict()); err != nil {
+		return err
+	}
+	dst[name] = decoded[name]
+	return nil
+}
+
+// yamlProblem returns the problem of a YAML error, at its position if any.
+func yamlProblem(path string, err error) problem {
+	p := problem{File: path, Message: err.Error()}
+	var yamlErr yaml.Error
+	if errors.As(err, &yamlErr) {
+		p.Message = yamlErr.GetMessage()
+		if tk := yamlErr.GetToken(); tk != nil && tk.Position != nil {
+			p.Line, p.Column = tk.Position.Line, tk.Position.Column
+		}
+	}
+	return p
+}
+
+// checkTool reports the references of a tool to resources that aren't
+// declared, and the issues with its parameters.
+func (v *validator) checkTool(name string, tc tools.ToolConfig) {
+	f := v.origins["tools"][name]
+	at := func(path ...any) []any { return append([]any{"tools", name}, path...) }
+
+	if source, ok := configField[string](tc, "Source"); ok {
+		if sc, found := v.merged.Sources[source]; !found {
+			if !v.declared("sources", source) {
+				v.addf(f, at("source"), "tool %q uses source %q, which isn't declared", name, source)
+			}
+		} else if err := checkSourceKind(tc, source, sc.SourceConfigKind()); err != nil {
+			v.addf(f, at("source"), "tool %q can't use source %q of kind %q: %s", name, source, sc.SourceConfigKind(), err)
+		}
+	}
+
+	authRequired, _ := configField[[]string](tc, "AuthRequired")
+	for i, as := range authRequired {
+		if !v.declared("authServices", as) {
+			v.addf(f, at("authRequired", i), "tool %q requires auth service %q, which isn't declared", name, as)
+		}
+	}
+
+	params, _ := configField[tools.Parameters](tc, "Parameters")
+	templateParams, hasTemplateParams := configField[tools.Parameters](tc, "TemplateParameters")
+	seen := make(map[string]bool)
+	for _, group := range []struct {
+		field  string
+		params tools.Parameters
+	}{{"parameters", params}, {"templateParameters", templateParams}} {
+		for i, p := range group.params {
+			if seen[p.GetName()] {
+				v.addf(f, at(group.field, i), "tool %q declares parameter %q more than once", name, p.GetName())
+			}
+			seen[p.GetName()] = true
+			for j, as := range p.GetAuthServices() {
+				if !v.declared("authServices", as.Name) {
+					v.addf(f, at(group.field, i, "authServices", j, "name"), "parameter %q of tool %q uses auth service %q, which isn't declared", p.GetName(), name, as.Name)
+				}
+			}
+		}
+	}
+
+	statement, ok := configField[string](tc, "Statement")
+	if !ok || !hasTemplateParams {
+		return
+	}
+	refs, err := tools.TemplateParamReferences(statement)
+	if err != nil {
+		v.addf(f, at("statement"), "invalid statement of tool %q: %s", name, err)
+		return
+	}
+	declared := make(map[string]bool)
+	for _, p := range templateParams {
+		declared[p.GetName()] = true
+	}
+	for _, ref := range refs {
+		if !declared[ref] {
+			v.addf(f, at("statement"), "statement of tool %q references template parameter %q, which isn't declared in templateParameters", name, ref)
+		}
+	}
+	for i, p := range templateParams {
+		if !slices.Contains(refs, p.GetName()) {
+			v.addf(f, at("templateParameters", i), "template parameter %q of tool %q isn't used by its statement", p.GetName(), name)
+		}
+	}
+}
+
+// checkToolset reports the tools of a toolset that aren't declared.
+func (v *validator) checkToolset(name string, tc tools.ToolsetConfig) {
+	f := v.origins["toolsets"][name]
+	for i, toolName := range tc.ToolNames {
+		if v.declared("tools", toolName) {
+			continue
+		}
+		// toolsets are either a list of tools, or a mapping with a list of tools
+		path := []any{"toolsets", name, i}
+		if _, _, found := nodePosition(f.root, path...); !found {
+			path = []any{"toolsets", name, "tools", i}
+		}
+		v.addf(f, path, "toolset %q uses tool %q, which isn't declared", name, toolName)
+	}
+}
+
+// configField returns the value of a field of a tool config, if the config
+// has a field of that name and type.
+func configField[T any](tc tools.ToolConfig, name string) (T, bool) {
+	var zero T
+	if pc, ok := tc.(tools.PolicyConfig); ok {
+		tc = pc.ToolConfig
+	}
+	v := reflect.ValueOf(tc)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return zero, false
+	}
+	f := v.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return zero, false
+	}
+	val, ok := f.Interface().(T)
+	return val, ok
+}
+
+// incompatibleSource is a source of a kind that no tool supports.
+type incompatibleSource struct{}
+
+func (incompatibleSource) SourceKind() string {
+	return "incompatible"
+}
+
+// checkSourceKind returns an error if a tool doesn't support sources of the
+// given kind. Tools check the kind of their source before anything else when
+// they are initialized, so the tool is initialized with an unconnected source
+// of that kind, and its error is compared to the error it returns for a source
+// that no tool supports.
+func checkSourceKind(tc tools.ToolConfig, source, kind string) error {
+	src, ok := unconnectedSources[kind]
+	if !ok {
+		return nil
+	}
+	errIncompatible := initializeTool(tc, source, incompatibleSource{})
+	if errIncompatible == nil {
+		// the tool doesn't check its source
+		return nil
+	}
+	if err := initializeTool(tc, source, src); err != nil && err.Error() == errIncompatible.Error() {
+		return err
+	}
+	return nil
+}
+
+// initializeTool initializes a tool with the given source. Tools that panic
+// because the source isn't connected are reported as initialized.
+func initializeTool(tc tools.ToolConfig, name string, src sources.Source) (err error) {
+	defer func() {
+		if recover() != nil {
+			err = nil
+		}
+	}()
+	_, err = tc.Initialize(map[string]sources.Source{name: src})
+	return err
+}
+
+// nodePosition returns the line and column of the node at path in a YAML
+// document, where path elements are either keys of mappings or indexes of
+// sequences. If the node doesn't exist, the position of its closest ancestor
+// is returned and found is false.
+func nodePosition(root ast.Node, path ...any) (line, column int, found bool) {
+	node := root
+	for _, elem := range path {
+		var next, at ast.Node
+		switch elem := elem.(type) {
+		case string:
+			for _, mv := range mappingValues(node) {
+				if mv.Key.GetToken().Value == elem {
+					next, at = mv.Value, mv.Key
+					break
+				}
+			}
+		case int:
+			if seq, ok := node.(*ast.SequenceNode); ok && elem < len(seq.Values) {
+				next, at = seq.Values[elem], seq.Values[elem]
+			}
+		}
+		if at == nil {
+			return line, column, false
+		}
+		// mappings are reported at their first key rather than at their first
+		// ':' delimiter
+		tk := at.GetToken()
+		if values := mappingValues(at); len(values) > 0 {
+			tk = values[0].Key.GetToken()
+		}
+		if tk != nil && tk.Position != nil {
+			line, column = tk.Position.Line, tk.Position.Column
+		}
+		node = next
+	}
+	return line, column, true
+}
+
+// mappingValues returns the key-value pairs of a mapping node.
+func mappingValues(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	case *ast.AnchorNode:
+		return mappingValues(n.Value)
+	case *ast.TagNode:
+		return mappingValues(n.Value)
+	}
+	return nil
+}
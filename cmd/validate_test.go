@@ -0,0 +1,277 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+// writeToolsFiles writes the tools files to a temporary directory, and
+// returns their paths.
+func writeToolsFiles(t *testing.T, contents ...string) []string {
+	dir := t.TempDir()
+	var paths []string
+	for i, c := range contents {
+		path := filepath.Join(dir, string(rune('a'+i))+".yaml")
+		if err := os.WriteFile(path, testutils.FormatYaml(c), 0o600); err != nil {
+			t.Fatalf("unable to write tools file: %s", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+var validToolsFile = `
+	sources:
+		my-pg-instance:
+			kind: postgres
+			host: 127.0.0.1
+			port: 5432
+			database: my_db
+			user: my_user
+			password: my_pass
+	authServices:
+		my-google-auth:
+			kind: google
+			clientId: my_client_id
+	tools:
+		search_table:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: Search a table.
+			statement: SELECT * FROM {{.tableName}} WHERE name = $1
+			authRequired:
+				- my-google-auth
+			parameters:
+				- name: name
+				  type: string
+				  description: Name to search for.
+			templateParameters:
+				- name: tableName
+				  type: string
+				  description: Table to search.
+	toolsets:
+		my-toolset:
+			- search_table
+	`
+
+func TestValidate(t *testing.T) {
+	paths := writeToolsFiles(t, validToolsFile)
+	_, output, err := invokeCommand([]string{"validate", "--tools-file", paths[0]})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if output != "No problems found.\n" {
+		t.Fatalf("unexpected output: %q", output)
+	}
+}
+
+func TestValidateProblems(t *testing.T) {
+	paths := writeToolsFiles(t, `
+	sources:
+		my-pg-instance:
+			kind: postgres
+			host: 127.0.0.1
+			port: 5432
+			database: my_db
+			user: ${VALIDATE_TEST_MISSING_USER}
+			password: my_pass
+			hots: typo
+		my-mongo:
+			kind: mongodb
+			uri: mongodb://localhost
+	tools:
+		search_table:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: Search a table.
+			statement: SELECT * FROM {{.tableName}} WHERE {{.column}} = $1
+			authRequired:
+				- missing-auth
+			parameters:
+				- name: name
+				  type: string
+				  description: Name to search for.
+				- name: name
+				  type: string
+				  description: Name to search for.
+			templateParameters:
+				- name: tableName
+				  type: string
+				  description: Table to search.
+				- name: unused
+				  type: string
+				  description: Unused.
+		wrong_source_kind:
+			kind: postgres-sql
+			source: my-mongo
+			description: Uses a source of another kind.
+			statement: SELECT 1
+		missing_source:
+			kind: postgres-sql
+			source: missing-source
+			description: Uses a missing source.
+			statement: SELECT 1
+	toolsets:
+		my-toolset:
+			- search_table
+			- missing_tool
+	`)
+	_, output, err := invokeCommand([]string{"validate", "--tools-file", paths[0]})
+	if !errors.Is(err, errProblemsFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`:8:12: environment variable not found: "VALIDATE_TEST_MISSING_USER"`,
+		`:10:6: unable to parse source "my-pg-instance" as "postgres": unknown field "hots"`,
+		`:19:6: statement of tool "search_table" references template parameter "column", which isn't declared in templateParameters`,
+		`:21:10: tool "search_table" requires auth service "missing-auth", which isn't declared`,
+		`:26:10: tool "search_table" declares parameter "name" more than once`,
+		`:33:10: template parameter "unused" of tool "search_table" isn't used by its statement`,
+		`:38:6: tool "wrong_source_kind" can't use source "my-mongo" of kind "mongodb": invalid source for "postgres-sql" tool: source kind must be one of ["alloydb-postgres" "cloud-sql-postgres" "postgres"]`,
+		`:43:6: tool "missing_source" uses source "missing-source", which isn't declared`,
+		`:49:8: toolset "my-toolset" uses tool "missing_tool", which isn't declared`,
+		`Found 9 problem(s).`,
+	}
+	got := strings.Split(strings.TrimSpace(strings.ReplaceAll(output, paths[0], "")), "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected problems (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	paths := writeToolsFiles(t, `
+	tools:
+		my-tool:
+			kind: unknown-kind
+	`)
+	_, output, err := invokeCommand([]string{"validate", "--tools-file", paths[0], "--format", "json"})
+	if !errors.Is(err, errProblemsFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got struct {
+		Problems []problem `json:"problems"`
+	}
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("unable to parse output %q: %s", output, err)
+	}
+	want := []problem{{File: paths[0], Line: 3, Column: 4, Message: `unknown tool kind: "unknown-kind"`}}
+	if diff := cmp.Diff(want, got.Problems); diff != "" {
+		t.Fatalf("unexpected problems (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateToolsFiles(t *testing.T) {
+	paths := writeToolsFiles(t, validToolsFile, `
+	tools:
+		other_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: Uses a source of another file.
+			statement: SELECT 1
+	toolsets:
+		my-toolset:
+			- other_tool
+	`)
+	_, output, err := invokeCommand([]string{"validate", "--tools-files", strings.Join(paths, ",")})
+	if !errors.Is(err, errProblemsFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := paths[1] + `:9:4: duplicate resource "my-toolset" in "toolsets", already declared in ` + paths[0]
+	if !strings.HasPrefix(output, want+"\n") {
+		t.Fatalf("unexpected output: got %q, want %q", output, want)
+	}
+}
+
+func TestValidateIncludes(t *testing.T) {
+	paths := writeToolsFiles(t, `
+	include:
+		- b.yaml
+		- c.yaml
+	tools:
+		search_hotels:
+			extends: pg-search
+			description: Search for hotels.
+			statement: SELECT * FROM hotels WHERE name = $1
+		other_tool:
+			extends: missing-template
+	toolTemplates:
+		pg-other:
+			kind: postgres-sql
+	`, `
+	include:
+		- a.yaml
+	sources:
+		my-pg-instance:
+			kind: postgres
+			host: 127.0.0.1
+			port: 5432
+			database: my_db
+			user: my_user
+			password: my_pass
+	toolTemplates:
+		pg-search:
+			kind: postgres-sql
+			source: my-pg-instance
+			authRequired:
+				- missing-auth
+			parameters:
+				- name: name
+				  type: string
+				  description: Name to search for.
+	`, `
+	toolTemplates:
+		pg-other:
+			kind: mysql-sql
+	`)
+	_, output, err := invokeCommand([]string{"validate", "--tools-file", paths[0]})
+	if !errors.Is(err, errProblemsFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		paths[0] + `:6:4: tool "search_hotels" requires auth service "missing-auth", which isn't declared`,
+		paths[0] + `:10:4: tool "other_tool" extends template "missing-template", which isn't declared`,
+		paths[0] + `:13:4: tool template "pg-other" is declared more than once`,
+		paths[1] + `:3:6: tool file at "` + paths[0] + `" is included in a cycle: ` + paths[0] + ` -> ` + paths[1] + ` -> ` + paths[0],
+		`Found 4 problem(s).`,
+	}
+	got := strings.Split(strings.TrimSpace(output), "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected problems (-want +got):\n%s", diff)
+	}
+}
+
+func TestFailValidateFlags(t *testing.T) {
+	_, _, err := invokeCommand([]string{"validate", "--format", "xml"})
+	if err == nil || err.Error() != `invalid --format "xml" (must be one of ["text" "json"])` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUnconnectedSources(t *testing.T) {
+	for kind, src := range unconnectedSources {
+		if src.SourceKind() != kind {
+			t.Errorf("unconnected source of kind %q has kind %q", kind, src.SourceKind())
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+// maxResponseBytes limits the size of discovery documents and key sets.
+const maxResponseBytes = 1 << 20
+
+// keySet caches the verification keys of an auth service. Keys fetched from a
+// JWKS URL are refreshed once refreshInterval has passed, or earlier when a
+// token references a key ID that is not in the cache, which allows providers
+// to rotate their keys.
+type keySet struct {
+	client          *http.Client
+	static          *jose.JSONWebKeySet
+	issuer          string
+	refreshInterval time.Duration
+	now             func() time.Time
+	// group shares a fetch of the keys between concurrent refreshes.
+	group singleflight.Group
+
+	mu         sync.Mutex
+	jwksURL    string
+	cached     *jose.JSONWebKeySet
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// lookup returns the keys that can be used to verify a token signed with kid.
+// If kid is empty, all signing keys are returned.
+func (k *keySet) lookup(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	if k.static != nil {
+		if keys := signingKeys(k.static, kid); len(keys) > 0 {
+			return keys, nil
+		}
+		return nil, fmt.Errorf("no key found for key ID %q", kid)
+	}
+
+	cached, fetchedAt, refreshing := k.snapshot()
+	now := k.now()
+	// stale keys are still served to the requests that arrive while they are
+	// being refreshed
+	if cached == nil || (now.Sub(fetchedAt) >= k.refreshInterval && !refreshing) {
+		err := k.refresh(ctx)
+		cached, fetchedAt, _ = k.snapshot()
+		if cached == nil {
+			return nil, err
+		}
+	}
+	if keys := signingKeys(cached, kid); len(keys) > 0 {
+		return keys, nil
+	}
+	// the key may have been rotated since it was last fetched
+	if now.Sub(fetchedAt) >= minRefreshInterval {
+		if err := k.refresh(ctx); err != nil {
+			return nil, err
+		}
+		cached, _, _ = k.snapshot()
+		if keys := signingKeys(cached, kid); len(keys) > 0 {
+			return keys, nil
+		}
+	}
+	return nil, fmt.Errorf("no key found for key ID %q", kid)
+}
+
+// snapshot returns the cached keys, when they were fetched, and whether they
+// are being refreshed.
+func (k *keySet) snapshot() (*jose.JSONWebKeySet, time.Time, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.cached, k.fetchedAt, k.refreshing
+}
+
+// refresh fetches the key set and caches it. Concurrent refreshes share a
+// single fetch, which is made without holding k.mu and is not cancelled with
+// ctx, so that the requests waiting for it can still be served.
+func (k *keySet) refresh(ctx context.Context) error {
+	ch := k.group.DoChan("refresh", func() (any, error) {
+		k.mu.Lock()
+		// record the attempt so that failing endpoints aren't retried on every request
+		k.fetchedAt = k.now()
+		k.refreshing = true
+		jwksURL := k.jwksURL
+		k.mu.Unlock()
+
+		jwksURL, set, err := k.fetch(context.WithoutCancel(ctx), jwksURL)
+
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		k.refreshing = false
+		if err != nil {
+			return nil, err
+		}
+		k.jwksURL = jwksURL
+		k.cached = set
+		return nil, nil
+	})
+	select {
+	case res := <-ch:
+		return res.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetch fetches the key set from jwksURL, discovering the JWKS URL from the
+// issuer if it is empty.
+func (k *keySet) fetch(ctx context.Context, jwksURL string) (string, *jose.JSONWebKeySet, error) {
+	if jwksURL == "" {
+		var err error
+		jwksURL, err = k.discover(ctx)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	var set jose.JSONWebKeySet
+	if err := k.getJSON(ctx, jwksURL, &set); err != nil {
+		return "", nil, fmt.Errorf("unable to fetch JWKS: %w", err)
+	}
+	return jwksURL, &set, nil
+}
+
+// discover returns the JWKS URL advertised in the issuer's OpenID configuration.
+func (k *keySet) discover(ctx context.Context) (string, error) {
+	var doc struct {
+		Issuer  string `json:"issuer"`
+		JwksURI string `json:"jwks_uri"`
+	}
+	u := strings.TrimSuffix(k.issuer, "/") + "/.well-known/openid-configuration"
+	if err := k.getJSON(ctx, u, &doc); err != nil {
+		return "", fmt.Errorf("unable to fetch OpenID configuration: %w", err)
+	}
+	if doc.Issuer != k.issuer {
+		return "", fmt.Errorf("OpenID configuration issuer %q does not match %q", doc.Issuer, k.issuer)
+	}
+	if doc.JwksURI == "" {
+		return "", fmt.Errorf("OpenID configuration is missing 'jwks_uri'")
+	}
+	return doc.JwksURI, nil
+}
+
+func (k *keySet) getJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, u)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// signingKeys returns the public signing keys in set that match kid.
+func signingKeys(set *jose.JSONWebKeySet, kid string) []jose.JSONWebKey {
+	var keys []jose.JSONWebKey
+	for _, key := range set.Keys {
+		if key.Use == "enc" || (kid != "" && key.KeyID != kid) {
+			continue
+		}
+		if !key.IsPublic() {
+			key = key.Public()
+		}
+		if key.Valid() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+)
+
+const (
+	// AuthServiceKind validates tokens issued by an OpenID Connect provider.
+	// Keys are discovered from the issuer unless a JWKS URL or static keys are configured.
+	AuthServiceKind string = "generic-oidc"
+	// JWTAuthServiceKind validates JWTs against a JWKS URL or static keys.
+	JWTAuthServiceKind string = "jwt"
+)
+
+const (
+	defaultAlgorithm       = string(jose.RS256)
+	defaultClockSkew       = time.Minute
+	defaultRefreshInterval = time.Hour
+	// minRefreshInterval limits how often keys are re-fetched when a token is
+	// signed by an unknown key.
+	minRefreshInterval = time.Minute
+	// fetchTimeout limits the time taken to fetch a discovery document or a
+	// key set.
+	fetchTimeout = 30 * time.Second
+)
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name            string   `yaml:"name" validate:"required"`
+	Kind            string   `yaml:"kind" validate:"required"`
+	Issuer          string   `yaml:"issuer"`
+	Audience        string   `yaml:"audience" validate:"required"`
+	JwksURL         string   `yaml:"jwksUrl"`
+	Jwks            string   `yaml:"jwks"`
+	Algorithms      []string `yaml:"algorithms"`
+	ClockSkew       string   `yaml:"clockSkew"`
+	RefreshInterval string   `yaml:"refreshInterval"`
+}
+
+// Returns the auth service kind
+func (cfg Config) AuthServiceConfigKind() string {
+	return cfg.Kind
+}
+
+// Initialize a generic OIDC or JWT auth service
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	if cfg.Jwks != "" && cfg.JwksURL != "" {
+		return nil, fmt.Errorf("only one of 'jwks' or 'jwksUrl' can be set")
+	}
+	switch cfg.Kind {
+	case AuthServiceKind:
+		if cfg.Issuer == "" {
+			return nil, fmt.Errorf("'issuer' is required for %q auth services", AuthServiceKind)
+		}
+	case JWTAuthServiceKind:
+		if cfg.Jwks == "" && cfg.JwksURL == "" {
+			return nil, fmt.Errorf("one of 'jwks' or 'jwksUrl' is required for %q auth services", JWTAuthServiceKind)
+		}
+	default:
+		return nil, fmt.Errorf("%q is not a valid kind of oidc auth service", cfg.Kind)
+	}
+
+	algorithms := cfg.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = []string{defaultAlgorithm}
+	}
+	algs := make([]jose.SignatureAlgorithm, 0, len(algorithms))
+	for _, a := range algorithms {
+		alg := jose.SignatureAlgorithm(a)
+		if !isAsymmetric(alg) {
+			return nil, fmt.Errorf("unsupported algorithm %q", a)
+		}
+		algs = append(algs, alg)
+	}
+
+	clockSkew, err := parseDuration("clockSkew", cfg.ClockSkew, defaultClockSkew)
+	if err != nil {
+		return nil, err
+	}
+	refreshInterval, err := parseDuration("refreshInterval", cfg.RefreshInterval, defaultRefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &keySet{
+		client:          &http.Client{Timeout: fetchTimeout},
+		jwksURL:         cfg.JwksURL,
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+	}
+	if cfg.Jwks != "" {
+		var static jose.JSONWebKeySet
+		if err := json.Unmarshal([]byte(cfg.Jwks), &static); err != nil {
+			return nil, fmt.Errorf("unable to parse 'jwks': %w", err)
+		}
+		if len(static.Keys) == 0 {
+			return nil, fmt.Errorf("'jwks' does not contain any keys")
+		}
+		keys.static = &static
+	} else if cfg.JwksURL == "" {
+		keys.issuer = cfg.Issuer
+	}
+
+	a := &AuthService{
+		Name:       cfg.Name,
+		Kind:       cfg.Kind,
+		Issuer:     cfg.Issuer,
+		Audience:   cfg.Audience,
+		algorithms: algs,
+		clockSkew:  clockSkew,
+		keys:       keys,
+	}
+	return a, nil
+}
+
+// isAsymmetric reports whether alg is a supported public key signature algorithm.
+// Symmetric algorithms are rejected since their keys cannot be published in a JWKS.
+func isAsymmetric(alg jose.SignatureAlgorithm) bool {
+	switch alg {
+	case jose.RS256, jose.RS384, jose.RS512,
+		jose.PS256, jose.PS384, jose.PS512,
+		jose.ES256, jose.ES384, jose.ES512,
+		jose.EdDSA:
+		return true
+	}
+	return false
+}
+
+// parseDuration parses an optional duration field, returning defaultV if it is unset.
+func parseDuration(field, v string, defaultV time.Duration) (time.Duration, error) {
+	if v == "" {
+		return defaultV, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %q: %w", field, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%q must not be negative", field)
+	}
+	return d, nil
+}
+
+var _ auth.AuthService = &AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Name     string `yaml:"name"`
+	Kind     string `yaml:"kind"`
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+
+	algorithms []jose.SignatureAlgorithm
+	clockSkew  time.Duration
+	keys       *keySet
+}
+
+// Returns the auth service kind
+func (a *AuthService) AuthServiceKind() string {
+	return a.Kind
+}
+
+// Returns the name of the auth service
+func (a *AuthService) GetName() string {
+	return a.Name
+}
+
+// Verifies the JWT from the "<name>_token" header and return claims
+func (a *AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	token := h.Get(a.Name + "_token")
+	if token == "" {
+		return nil, nil
+	}
+	// tolerate clients that send the token with a bearer prefix
+	if len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+		token = token[7:]
+	}
+	claims, err := a.validate(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("JWT verification failure: %w", err)
+	}
+	return claims, nil
+}
+
+// validate verifies the signature and registered claims of a token.
+func (a *AuthService) validate(ctx context.Context, token string) (map[string]any, error) {
+	tok, err := jwt.ParseSigned(token, a.algorithms)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse token: %w", err)
+	}
+	if len(tok.Headers) != 1 {
+		return nil, fmt.Errorf("token must have exactly one signature")
+	}
+	kid := tok.Headers[0].KeyID
+
+	candidates, err := a.keys.lookup(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	var registered jwt.Claims
+	var claims map[string]any
+	verified := false
+	for _, k := range candidates {
+		if err := tok.Claims(k, &registered, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	if registered.Expiry == nil {
+		return nil, fmt.Errorf("token is missing the 'exp' claim")
+	}
+	expected := jwt.Expected{
+		AnyAudience: jwt.Audience{a.Audience},
+		Time:        a.keys.now(),
+	}
+	if a.Issuer != "" {
+		expected.Issuer = a.Issuer
+	}
+	if err := registered.ValidateWithLeeway(expected, a.clockSkew); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
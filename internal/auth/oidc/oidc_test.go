@@ -0,0 +1,401 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+const (
+	testAudience = "toolbox"
+	testName     = "my-oidc-service"
+)
+
+// jwksServer is a local stand-in for an OpenID provider serving a discovery
+// document and a JWKS.
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     jose.JSONWebKeySet
+	requests atomic.Int32
+	// gate, if set, blocks the key set responses until it is closed.
+	gate chan struct{}
+}
+
+func newJwksServer(t *testing.T, keys ...jose.JSONWebKey) *jwksServer {
+	s := &jwksServer{}
+	s.setKeys(keys...)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   s.URL,
+			"jwks_uri": s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		gate, keys := s.gate, s.keys
+		s.mu.Unlock()
+		if gate != nil {
+			<-gate
+		}
+		_ = json.NewEncoder(w).Encode(keys)
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...jose.JSONWebKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = jose.JSONWebKeySet{}
+	for _, k := range keys {
+		s.keys.Keys = append(s.keys.Keys, k.Public())
+	}
+}
+
+func newRSAKey(t *testing.T, kid string) jose.JSONWebKey {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	return jose.JSONWebKey{Key: k, KeyID: kid, Algorithm: string(jose.RS256), Use: "sig"}
+}
+
+func newECKey(t *testing.T, kid string) jose.JSONWebKey {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	return jose.JSONWebKey{Key: k, KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"}
+}
+
+func sign(t *testing.T, key jose.JSONWebKey, claims map[string]any) string {
+	alg := jose.SignatureAlgorithm(key.Algorithm)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		t.Fatalf("unable to create signer: %s", err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatalf("unable to sign token: %s", err)
+	}
+	return token
+}
+
+func initialize(t *testing.T, cfg Config) *AuthService {
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	return a.(*AuthService)
+}
+
+func header(token string) http.Header {
+	h := http.Header{}
+	h.Set(testName+"_token", token)
+	return h
+}
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	server := newJwksServer(t, key)
+	now := time.Now()
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"iss":   server.URL,
+			"aud":   testAudience,
+			"sub":   "user-1",
+			"email": "user@example.com",
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		}
+	}
+	with := func(k string, v any) map[string]any {
+		c := validClaims()
+		if v == nil {
+			delete(c, k)
+		} else {
+			c[k] = v
+		}
+		return c
+	}
+	otherKey := newRSAKey(t, "key-1")
+
+	tcs := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{
+			name:  "valid token",
+			token: sign(t, key, validClaims()),
+		},
+		{
+			name:  "valid token with bearer prefix",
+			token: "Bearer " + sign(t, key, validClaims()),
+		},
+		{
+			name:  "expired within clock skew",
+			token: sign(t, key, with("exp", now.Add(-30*time.Second).Unix())),
+		},
+		{
+			name:    "expired",
+			token:   sign(t, key, with("exp", now.Add(-time.Hour).Unix())),
+			wantErr: "token is expired",
+		},
+		{
+			name:    "missing expiry",
+			token:   sign(t, key, with("exp", nil)),
+			wantErr: "token is missing the 'exp' claim",
+		},
+		{
+			name:    "not valid yet",
+			token:   sign(t, key, with("nbf", now.Add(time.Hour).Unix())),
+			wantErr: "token not valid yet",
+		},
+		{
+			name:    "wrong audience",
+			token:   sign(t, key, with("aud", "other")),
+			wantErr: "invalid audience claim",
+		},
+		{
+			name:    "wrong issuer",
+			token:   sign(t, key, with("iss", "https://attacker.example.com")),
+			wantErr: "invalid issuer claim",
+		},
+		{
+			name:    "wrong signature",
+			token:   sign(t, otherKey, validClaims()),
+			wantErr: "invalid token signature",
+		},
+		{
+			name:    "disallowed algorithm",
+			token:   sign(t, newECKey(t, "key-1"), validClaims()),
+			wantErr: "unable to parse token",
+		},
+		{
+			name:    "malformed token",
+			token:   "not-a-token",
+			wantErr: "unable to parse token",
+		},
+	}
+	a := initialize(t, Config{Name: testName, Kind: AuthServiceKind, Issuer: server.URL, Audience: testAudience})
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := a.GetClaimsFromHeader(t.Context(), header(tc.token))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if claims["email"] != "user@example.com" || claims["sub"] != "user-1" {
+				t.Fatalf("unexpected claims: %v", claims)
+			}
+		})
+	}
+
+	t.Run("missing header", func(t *testing.T) {
+		claims, err := a.GetClaimsFromHeader(t.Context(), http.Header{})
+		if err != nil || claims != nil {
+			t.Fatalf("unexpected result: got %v, %v", claims, err)
+		}
+	})
+	if got := server.requests.Load(); got != 1 {
+		t.Fatalf("expected keys to be fetched once, got %d", got)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := newRSAKey(t, "old")
+	newKey := newRSAKey(t, "new")
+	server := newJwksServer(t, oldKey)
+	a := initialize(t, Config{Name: testName, Kind: JWTAuthServiceKind, JwksURL: server.URL + "/jwks", Audience: testAudience})
+	now := time.Now()
+	a.keys.now = func() time.Time { return now }
+	claims := map[string]any{"aud": testAudience, "exp": now.Add(time.Hour).Unix()}
+
+	if _, err := a.GetClaimsFromHeader(t.Context(), header(sign(t, oldKey, claims))); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	server.setKeys(oldKey, newKey)
+	// unknown keys aren't re-fetched more often than minRefreshInterval
+	if _, err := a.GetClaimsFromHeader(t.Context(), header(sign(t, newKey, claims))); err == nil {
+		t.Fatalf("expected unknown key to be rejected before the refresh interval")
+	}
+	now = now.Add(minRefreshInterval)
+	if _, err := a.GetClaimsFromHeader(t.Context(), header(sign(t, newKey, claims))); err != nil {
+		t.Fatalf("unexpected error after key rotation: %s", err)
+	}
+
+	// keys are refreshed after the refresh interval
+	server.setKeys(newKey)
+	now = now.Add(defaultRefreshInterval)
+	if _, err := a.GetClaimsFromHeader(t.Context(), header(sign(t, oldKey, claims))); err == nil {
+		t.Fatalf("expected removed key to be rejected")
+	}
+	if got := server.requests.Load(); got != 3 {
+		t.Fatalf("unexpected number of key fetches: got %d, want 3", got)
+	}
+}
+
+func TestServeCachedKeysDuringRefresh(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	server := newJwksServer(t, key)
+	a := initialize(t, Config{Name: testName, Kind: JWTAuthServiceKind, JwksURL: server.URL + "/jwks", Audience: testAudience})
+	now := time.Now()
+	var nowMu sync.Mutex
+	a.keys.now = func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		return now
+	}
+	token := sign(t, key, map[string]any{"aud": testAudience, "exp": now.Add(2 * defaultRefreshInterval).Unix()})
+
+	if _, err := a.GetClaimsFromHeader(t.Context(), header(token)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// hold the refresh of the stale keys in flight
+	gate := make(chan struct{})
+	server.mu.Lock()
+	server.gate = gate
+	server.mu.Unlock()
+	nowMu.Lock()
+	now = now.Add(defaultRefreshInterval)
+	nowMu.Unlock()
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := a.GetClaimsFromHeader(t.Context(), header(token))
+		refreshed <- err
+	}()
+	for server.requests.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the cached keys are served without waiting for the refresh
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	if _, err := a.GetClaimsFromHeader(ctx, header(token)); err != nil {
+		t.Fatalf("unexpected error during refresh: %s", err)
+	}
+
+	close(gate)
+	if err := <-refreshed; err != nil {
+		t.Fatalf("unexpected error after refresh: %s", err)
+	}
+	if got := server.requests.Load(); got != 2 {
+		t.Fatalf("unexpected number of key fetches: got %d, want 2", got)
+	}
+}
+
+func TestStaticKeys(t *testing.T) {
+	key := newECKey(t, "static")
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key.Public()}})
+	if err != nil {
+		t.Fatalf("unable to marshal jwks: %s", err)
+	}
+	a := initialize(t, Config{
+		Name:       testName,
+		Kind:       JWTAuthServiceKind,
+		Issuer:     "https://issuer.example.com",
+		Audience:   testAudience,
+		Jwks:       string(jwks),
+		Algorithms: []string{"ES256"},
+		ClockSkew:  "0s",
+	})
+	claims := map[string]any{
+		"iss":  "https://issuer.example.com",
+		"aud":  []string{"other", testAudience},
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"role": "admin",
+	}
+	got, err := a.GetClaimsFromHeader(t.Context(), header(sign(t, key, claims)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got["role"] != "admin" {
+		t.Fatalf("unexpected claims: %v", got)
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{
+			name: "generic-oidc without issuer",
+			cfg:  Config{Name: testName, Kind: AuthServiceKind, Audience: testAudience},
+			err:  "'issuer' is required for \"generic-oidc\" auth services",
+		},
+		{
+			name: "jwt without keys",
+			cfg:  Config{Name: testName, Kind: JWTAuthServiceKind, Audience: testAudience},
+			err:  "one of 'jwks' or 'jwksUrl' is required for \"jwt\" auth services",
+		},
+		{
+			name: "both jwks and jwksUrl",
+			cfg:  Config{Name: testName, Kind: JWTAuthServiceKind, Audience: testAudience, Jwks: `{"keys":[]}`, JwksURL: "http://localhost/jwks"},
+			err:  "only one of 'jwks' or 'jwksUrl' can be set",
+		},
+		{
+			name: "symmetric algorithm",
+			cfg:  Config{Name: testName, Kind: JWTAuthServiceKind, Audience: testAudience, JwksURL: "http://localhost/jwks", Algorithms: []string{"HS256"}},
+			err:  "unsupported algorithm \"HS256\"",
+		},
+		{
+			name: "invalid clock skew",
+			cfg:  Config{Name: testName, Kind: JWTAuthServiceKind, Audience: testAudience, JwksURL: "http://localhost/jwks", ClockSkew: "soon"},
+			err:  "unable to parse \"clockSkew\"",
+		},
+		{
+			name: "empty static keys",
+			cfg:  Config{Name: testName, Kind: JWTAuthServiceKind, Audience: testAudience, Jwks: `{"keys":[]}`},
+			err:  "'jwks' does not contain any keys",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.cfg.Initialize()
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want to contain %q", err, tc.err)
+			}
+		})
+	}
+}
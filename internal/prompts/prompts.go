@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// MessageConfig is a single templated message of a prompt.
+type MessageConfig struct {
+	Role    string `yaml:"role"`
+	Content string `yaml:"content" validate:"required"`
+}
+
+// Config is the configuration of a reusable prompt template.
+type Config struct {
+	Name        string           `yaml:"name" validate:"required"`
+	Description string           `yaml:"description"`
+	Arguments   tools.Parameters `yaml:"arguments"`
+	Messages    []MessageConfig  `yaml:"messages" validate:"required"`
+}
+
+// Initialize validates the prompt config and returns a Prompt.
+func (cfg Config) Initialize() (Prompt, error) {
+	if !tools.IsValidName(cfg.Name) {
+		return Prompt{}, fmt.Errorf("invalid prompt name: %s", cfg.Name)
+	}
+	if len(cfg.Messages) == 0 {
+		return Prompt{}, fmt.Errorf("prompt %q must have at least one message", cfg.Name)
+	}
+
+	arguments := cfg.Arguments
+	if arguments == nil {
+		arguments = make(tools.Parameters, 0)
+	}
+	if err := tools.CheckDuplicateParameters(arguments); err != nil {
+		return Prompt{}, err
+	}
+	for _, a := range arguments {
+		if len(a.GetAuthServices()) > 0 {
+			return Prompt{}, fmt.Errorf("prompt argument %q cannot use authServices", a.GetName())
+		}
+	}
+
+	messages := make([]MessageConfig, 0, len(cfg.Messages))
+	for i, m := range cfg.Messages {
+		if m.Role == "" {
+			m.Role = RoleUser
+		}
+		if m.Role != RoleUser && m.Role != RoleAssistant {
+			return Prompt{}, fmt.Errorf("invalid role %q for message #%d: must be one of %q or %q", m.Role, i, RoleUser, RoleAssistant)
+		}
+		// verify the template can be parsed before serving it
+		if _, err := template.New(cfg.Name).Funcs(template.FuncMap{"json": json.Marshal}).Parse(m.Content); err != nil {
+			return Prompt{}, fmt.Errorf("invalid content for message #%d: %w", i, err)
+		}
+		messages = append(messages, m)
+	}
+
+	return Prompt{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		Arguments:   arguments,
+		Messages:    messages,
+		mcpManifest: getMcpManifest(cfg.Name, cfg.Description, arguments),
+	}, nil
+}
+
+// Prompt is a named template that is rendered into messages for the client.
+type Prompt struct {
+	Name        string
+	Description string
+	Arguments   tools.Parameters
+	Messages    []MessageConfig
+	mcpManifest McpManifest
+}
+
+// Message is a rendered prompt message.
+type Message struct {
+	Role string
+	Text string
+}
+
+// ParseArgs parses the arguments sent by the client. MCP clients always send
+// prompt arguments as strings, so non-string arguments are decoded as JSON
+// before being validated against their declared type.
+func (p Prompt) ParseArgs(data map[string]string) (tools.ParamValues, error) {
+	values := make(map[string]any, len(data))
+	for _, a := range p.Arguments {
+		name := a.GetName()
+		raw, ok := data[name]
+		if !ok {
+			continue
+		}
+		if a.GetType() == "string" {
+			values[name] = raw
+			continue
+		}
+		var v any
+		if err := util.DecodeJSON(strings.NewReader(raw), &v); err != nil {
+			return nil, fmt.Errorf("unable to parse value for %q: %q not type %q", name, raw, a.GetType())
+		}
+		values[name] = v
+	}
+	return tools.ParseParams(p.Arguments, values, nil)
+}
+
+// Render populates the message templates with the given arguments.
+func (p Prompt) Render(args tools.ParamValues) ([]Message, error) {
+	data := args.AsMap()
+	messages := make([]Message, 0, len(p.Messages))
+	for _, m := range p.Messages {
+		text, err := tools.PopulateTemplateWithJSON(p.Name, m.Content, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render prompt %q: %w", p.Name, err)
+		}
+		messages = append(messages, Message{Role: m.Role, Text: text})
+	}
+	return messages, nil
+}
+
+func (p Prompt) McpManifest() McpManifest {
+	return p.mcpManifest
+}
+
+// McpManifest is the definition of a prompt sent to MCP clients.
+type McpManifest struct {
+	// The name of the prompt.
+	Name string `json:"name"`
+	// A human-readable description of the prompt.
+	Description string `json:"description,omitempty"`
+	// A list of arguments to use for templating the prompt.
+	Arguments []McpArgument `json:"arguments,omitempty"`
+}
+
+// McpArgument describes an argument that a prompt can accept.
+type McpArgument struct {
+	// The name of the argument.
+	Name string `json:"name"`
+	// A human-readable description of the argument.
+	Description string `json:"description,omitempty"`
+	// Whether this argument must be provided.
+	Required bool `json:"required,omitempty"`
+}
+
+func getMcpManifest(name, desc string, arguments tools.Parameters) McpManifest {
+	mcpArgs := make([]McpArgument, 0, len(arguments))
+	for _, a := range arguments {
+		m := a.Manifest()
+		mcpArgs = append(mcpArgs, McpArgument{
+			Name:        m.Name,
+			Description: m.Description,
+			Required:    m.Required,
+		})
+	}
+	return McpManifest{
+		Name:        name,
+		Description: desc,
+		Arguments:   mcpArgs,
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestInitialize(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		cfg     prompts.Config
+		wantErr string
+	}{
+		{
+			desc: "valid",
+			cfg: prompts.Config{
+				Name:     "my_prompt",
+				Messages: []prompts.MessageConfig{{Content: "hello {{.name}}"}},
+			},
+		},
+		{
+			desc: "invalid name",
+			cfg: prompts.Config{
+				Name:     "my prompt",
+				Messages: []prompts.MessageConfig{{Content: "hello"}},
+			},
+			wantErr: "invalid prompt name",
+		},
+		{
+			desc:    "no messages",
+			cfg:     prompts.Config{Name: "my_prompt"},
+			wantErr: "must have at least one message",
+		},
+		{
+			desc: "invalid role",
+			cfg: prompts.Config{
+				Name:     "my_prompt",
+				Messages: []prompts.MessageConfig{{Role: "system", Content: "hello"}},
+			},
+			wantErr: `invalid role "system"`,
+		},
+		{
+			desc: "invalid template",
+			cfg: prompts.Config{
+				Name:     "my_prompt",
+				Messages: []prompts.MessageConfig{{Content: "hello {{.name"}},
+			},
+			wantErr: "invalid content for message #0",
+		},
+		{
+			desc: "duplicate arguments",
+			cfg: prompts.Config{
+				Name: "my_prompt",
+				Arguments: tools.Parameters{
+					tools.NewStringParameter("name", "a name"),
+					tools.NewStringParameter("name", "another name"),
+				},
+				Messages: []prompts.MessageConfig{{Content: "hello"}},
+			},
+			wantErr: "parameter name must be unique",
+		},
+		{
+			desc: "authenticated argument",
+			cfg: prompts.Config{
+				Name: "my_prompt",
+				Arguments: tools.Parameters{
+					tools.NewStringParameterWithAuth("email", "user email", []tools.ParamAuthService{{Name: "my-google-auth", Field: "email"}}),
+				},
+				Messages: []prompts.MessageConfig{{Content: "hello"}},
+			},
+			wantErr: `prompt argument "email" cannot use authServices`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseArgsAndRender(t *testing.T) {
+	p, err := prompts.Config{
+		Name: "my_prompt",
+		Arguments: tools.Parameters{
+			tools.NewStringParameter("name", "a name"),
+			tools.NewIntParameterWithDefault("count", 3, "a count"),
+			tools.NewArrayParameterWithRequired("tags", "some tags", false, tools.NewStringParameter("tag", "a tag")),
+		},
+		Messages: []prompts.MessageConfig{
+			{Content: "Hi {{.name}}, count is {{.count}}, tags are {{json .tags}}."},
+			{Role: prompts.RoleAssistant, Content: "Hello!"},
+		},
+	}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+
+	tcs := []struct {
+		desc    string
+		args    map[string]string
+		want    []prompts.Message
+		wantErr string
+	}{
+		{
+			desc: "all arguments",
+			args: map[string]string{"name": "Alice", "count": "5", "tags": `["a","b"]`},
+			want: []prompts.Message{
+				{Role: prompts.RoleUser, Text: `Hi Alice, count is 5, tags are ["a","b"].`},
+				{Role: prompts.RoleAssistant, Text: "Hello!"},
+			},
+		},
+		{
+			desc: "default argument",
+			args: map[string]string{"name": "Bob"},
+			want: []prompts.Message{
+				{Role: prompts.RoleUser, Text: "Hi Bob, count is 3, tags are null."},
+				{Role: prompts.RoleAssistant, Text: "Hello!"},
+			},
+		},
+		{
+			desc:    "missing required argument",
+			args:    map[string]string{},
+			wantErr: `parameter "name" is required`,
+		},
+		{
+			desc:    "wrong argument type",
+			args:    map[string]string{"name": "Bob", "count": "many"},
+			wantErr: `unable to parse value for "count"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			args, err := p.ParseArgs(tc.args)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want error containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := p.Render(args)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect render: diff %v", diff)
+			}
+		})
+	}
+}
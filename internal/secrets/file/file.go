@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package file resolves ${file:<path>} secret references to the content of a
+// file, such as a Docker or Kubernetes secret mounted at /run/secrets/pg.
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+)
+
+const Scheme string = "file"
+
+// validate interface
+var _ secrets.Resolver = Resolver{}
+
+func init() {
+	if !secrets.Register(Scheme, newResolver) {
+		panic(fmt.Sprintf("secret scheme %q already registered", Scheme))
+	}
+}
+
+func newResolver(context.Context) (secrets.Resolver, error) {
+	return Resolver{}, nil
+}
+
+// Resolver reads secrets from files. Trailing newlines are removed.
+type Resolver struct{}
+
+func (Resolver) Resolve(_ context.Context, ref string) (string, error) {
+	b, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/secrets/file"
+)
+
+func TestResolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pg")
+	if err := os.WriteFile(path, []byte("my-password\n"), 0o600); err != nil {
+		t.Fatalf("unable to write secret: %s", err)
+	}
+	got, err := file.Resolver{}.Resolve(context.Background(), path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != "my-password" {
+		t.Fatalf("unexpected secret: %q", got)
+	}
+
+	_, err = file.Resolver{}.Resolve(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	if err == nil || !strings.HasPrefix(err.Error(), "unable to read secret file: ") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
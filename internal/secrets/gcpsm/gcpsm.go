@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcpsm resolves ${gcpsm:projects/<project>/secrets/<secret>} secret
+// references to the latest version of a Google Cloud Secret Manager secret,
+// or to the version given by a /versions/<version> suffix. Application
+// Default Credentials are used.
+package gcpsm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"google.golang.org/api/option"
+	secretmanagerapi "google.golang.org/api/secretmanager/v1"
+)
+
+const Scheme string = "gcpsm"
+
+// validate interface
+var _ secrets.Resolver = &Resolver{}
+
+func init() {
+	if !secrets.Register(Scheme, newResolver) {
+		panic(fmt.Sprintf("secret scheme %q already registered", Scheme))
+	}
+}
+
+func newResolver(ctx context.Context) (secrets.Resolver, error) {
+	var opts []option.ClientOption
+	if userAgent, err := util.UserAgentFromContext(ctx); err == nil {
+		opts = append(opts, option.WithUserAgent(userAgent))
+	}
+	return NewResolver(ctx, opts...)
+}
+
+// NewResolver returns a Resolver using a Secret Manager client created with
+// the options.
+func NewResolver(ctx context.Context, opts ...option.ClientOption) (*Resolver, error) {
+	service, err := secretmanagerapi.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Secret Manager client: %w", err)
+	}
+	return &Resolver{service: service}, nil
+}
+
+// Resolver accesses the versions of Secret Manager secrets.
+type Resolver struct {
+	service *secretmanagerapi.Service
+}
+
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	parts := strings.Split(ref, "/")
+	valid := (len(parts) == 4 || len(parts) == 6 && parts[4] == "versions") && parts[0] == "projects" && parts[2] == "secrets"
+	if !valid || slices.Contains(parts, "") {
+		return "", fmt.Errorf("invalid reference %q (must be in the form of 'projects/<project>/secrets/<secret>[/versions/<version>]')", ref)
+	}
+	name := ref
+	if len(parts) == 4 {
+		name += "/versions/latest"
+	}
+
+	resp, err := r.service.Projects.Secrets.Versions.Access(name).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to access secret version: %w", err)
+	}
+	if resp.Payload == nil {
+		return "", fmt.Errorf("secret version %q has no payload", name)
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode payload: %w", err)
+	}
+	return string(data), nil
+}
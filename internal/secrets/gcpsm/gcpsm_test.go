@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpsm_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/secrets/gcpsm"
+	"google.golang.org/api/option"
+)
+
+func TestResolve(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/pg/versions/latest:access":
+			// base64 of "latest-password"
+			_, _ = w.Write([]byte(`{"name": "projects/my-project/secrets/pg/versions/2", "payload": {"data": "bGF0ZXN0LXBhc3N3b3Jk"}}`))
+		case "/v1/projects/my-project/secrets/pg/versions/1:access":
+			// base64 of "first-password"
+			_, _ = w.Write([]byte(`{"name": "projects/my-project/secrets/pg/versions/1", "payload": {"data": "Zmlyc3QtcGFzc3dvcmQ="}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "Secret not found"}}`))
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	r, err := gcpsm.NewResolver(ctx, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unable to create resolver: %s", err)
+	}
+
+	tcs := []struct {
+		desc    string
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{desc: "latest version", ref: "projects/my-project/secrets/pg", want: "latest-password"},
+		{desc: "version", ref: "projects/my-project/secrets/pg/versions/1", want: "first-password"},
+		{desc: "missing secret", ref: "projects/my-project/secrets/missing", wantErr: "unable to access secret version: googleapi: Error 404: Secret not found"},
+		{desc: "invalid reference", ref: "my-project/pg", wantErr: `invalid reference "my-project/pg" (must be in the form of 'projects/<project>/secrets/<secret>[/versions/<version>]')`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := r.Resolve(ctx, tc.ref)
+			if tc.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected secret: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
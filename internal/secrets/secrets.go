@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves the secret references of tools files, such as
+// ${file:/run/secrets/pg}, with the resolver registered for their scheme.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Resolver resolves the secret references of a scheme.
+type Resolver interface {
+	// Resolve returns the secret of the reference, which is the part after
+	// the scheme, such as "/run/secrets/pg" for ${file:/run/secrets/pg}.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// ResolverFactory defines the function signature for creating a Resolver.
+type ResolverFactory func(ctx context.Context) (Resolver, error)
+
+var resolverRegistry = make(map[string]ResolverFactory)
+
+// Register registers a new secret scheme with its factory.
+// It returns false if the scheme is already registered.
+func Register(scheme string, factory ResolverFactory) bool {
+	if _, exists := resolverRegistry[scheme]; exists {
+		// Resolver with this scheme already exists, do not overwrite.
+		return false
+	}
+	resolverRegistry[scheme] = factory
+	return true
+}
+
+// Registered reports whether a resolver is registered for the scheme.
+func Registered(scheme string) bool {
+	_, ok := resolverRegistry[scheme]
+	return ok
+}
+
+// redacted replaces the secrets in redacted errors.
+const redacted = "[REDACTED]"
+
+// Session resolves the secrets of a tools file. Resolvers are created on first
+// use, and each reference is resolved once.
+type Session struct {
+	resolvers map[string]Resolver
+	values    map[string]string
+}
+
+// NewSession returns a new Session.
+func NewSession() *Session {
+	return &Session{
+		resolvers: make(map[string]Resolver),
+		values:    make(map[string]string),
+	}
+}
+
+// Resolve returns the secret of a reference. Errors never include the secret.
+func (s *Session) Resolve(ctx context.Context, scheme, ref string) (string, error) {
+	key := scheme + ":" + ref
+	if v, ok := s.values[key]; ok {
+		return v, nil
+	}
+	r, ok := s.resolvers[scheme]
+	if !ok {
+		factory, found := resolverRegistry[scheme]
+		if !found {
+			return "", fmt.Errorf("unknown secret scheme: %q", scheme)
+		}
+		var err error
+		r, err = factory(ctx)
+		if err != nil {
+			return "", fmt.Errorf("unable to initialize %q secret resolver: %w", scheme, err)
+		}
+		s.resolvers[scheme] = r
+	}
+	v, err := r.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve secret %q: %w", key, err)
+	}
+	s.values[key] = v
+	return v, nil
+}
+
+// RedactError returns err with the secrets resolved so far replaced, such as
+// for errors quoting the lines of a tools file. err is returned as is if it
+// doesn't include any secret.
+func (s *Session) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	out := msg
+	for _, v := range s.values {
+		out = redact(out, v)
+		// multi-line secrets are also quoted line by line
+		for line := range strings.Lines(v) {
+			out = redact(out, strings.TrimRight(line, "\r\n"))
+		}
+	}
+	if out == msg {
+		return err
+	}
+	return errors.New(out)
+}
+
+func redact(msg, secret string) string {
+	if strings.TrimSpace(secret) == "" {
+		return msg
+	}
+	return strings.ReplaceAll(msg, secret, redacted)
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+)
+
+// countingResolver resolves references to a prefixed secret, and
+// counts the resolutions.
+type countingResolver struct {
+	count *int
+}
+
+func (r countingResolver) Resolve(_ context.Context, ref string) (string, error) {
+	*r.count++
+	if ref == "fail" {
+		return "", fmt.Errorf("access denied")
+	}
+	return "s3cr3t-" + ref, nil
+}
+
+var resolutions int
+
+func init() {
+	secrets.Register("counting", func(context.Context) (secrets.Resolver, error) {
+		return countingResolver{count: &resolutions}, nil
+	})
+	secrets.Register("broken", func(context.Context) (secrets.Resolver, error) {
+		return nil, fmt.Errorf("missing credentials")
+	})
+}
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+	if secrets.Register("counting", nil) {
+		t.Fatalf("registering a scheme twice should fail")
+	}
+	if !secrets.Registered("counting") || secrets.Registered("unknown") {
+		t.Fatalf("unexpected registered schemes")
+	}
+
+	resolutions = 0
+	s := secrets.NewSession()
+	for range 2 {
+		got, err := s.Resolve(ctx, "counting", "a")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != "s3cr3t-a" {
+			t.Fatalf("unexpected secret: %q", got)
+		}
+	}
+	if resolutions != 1 {
+		t.Fatalf("each reference should be resolved once, got %d resolutions", resolutions)
+	}
+
+	tcs := []struct {
+		scheme string
+		ref    string
+		want   string
+	}{
+		{scheme: "unknown", ref: "a", want: `unknown secret scheme: "unknown"`},
+		{scheme: "broken", ref: "a", want: `unable to initialize "broken" secret resolver: missing credentials`},
+		{scheme: "counting", ref: "fail", want: `unable to resolve secret "counting:fail": access denied`},
+	}
+	for _, tc := range tcs {
+		if _, err := s.Resolve(ctx, tc.scheme, tc.ref); err == nil || err.Error() != tc.want {
+			t.Fatalf("unexpected error: got %v, want %q", err, tc.want)
+		}
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	s := secrets.NewSession()
+	if _, err := s.Resolve(context.Background(), "counting", "b"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err := errors.New("[2:11] unknown field\n   1 | password: s3cr3t-b\n>  2 | pasword: s3cr3t-b")
+	want := "[2:11] unknown field\n   1 | password: [REDACTED]\n>  2 | pasword: [REDACTED]"
+	if got := s.RedactError(err); got.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", got, want)
+	}
+	other := errors.New("unrelated")
+	if got := s.RedactError(other); got != other {
+		t.Fatalf("errors without secrets should be returned as is, got %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vault resolves ${vault:<path>#<field>} secret references to a field
+// of a HashiCorp Vault secret, such as ${vault:kv/data/db#password}. The
+// server and token are given by the VAULT_ADDR, VAULT_TOKEN and
+// VAULT_NAMESPACE environment variables, like for the Vault CLI.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+)
+
+const Scheme string = "vault"
+
+// defaultAddress is the address of Vault when VAULT_ADDR isn't set.
+const defaultAddress = "https://127.0.0.1:8200"
+
+// validate interface
+var _ secrets.Resolver = &Resolver{}
+
+func init() {
+	if !secrets.Register(Scheme, newResolver) {
+		panic(fmt.Sprintf("secret scheme %q already registered", Scheme))
+	}
+}
+
+func newResolver(context.Context) (secrets.Resolver, error) {
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("environment variable VAULT_TOKEN is not set")
+	}
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		addr = defaultAddress
+	}
+	return &Resolver{
+		Address:   strings.TrimSuffix(addr, "/"),
+		Token:     token,
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+		Client:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Resolver reads the secrets from the HTTP API of Vault. Both versions of the
+// KV secrets engine are supported.
+type Resolver struct {
+	Address   string
+	Token     string
+	Namespace string
+	Client    *http.Client
+}
+
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("invalid reference %q (must be in the form of '<path>#<field>')", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.Address+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", r.Token)
+	if r.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.Namespace)
+	}
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &errResp)
+		if len(errResp.Errors) == 0 {
+			return "", fmt.Errorf("unexpected status %q from vault", resp.Status)
+		}
+		return "", fmt.Errorf("unexpected status %q from vault: %s", resp.Status, strings.Join(errResp.Errors, "; "))
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("unable to parse response: %w", err)
+	}
+	data := secret.Data
+	// version 2 of the KV secrets engine nests the fields with their metadata
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	v, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", field)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal field %q: %w", field, err)
+	}
+	return string(b), nil
+}
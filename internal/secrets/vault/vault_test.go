@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+	"github.com/googleapis/genai-toolbox/internal/secrets/vault"
+)
+
+// newVaultServer returns a stand-in for the HTTP API of Vault, with a KV
+// version 1 secret at secret/db and a KV version 2 secret at kv/data/db.
+func newVaultServer(t *testing.T) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+		if r.Header.Get("X-Vault-Namespace") != "my-namespace" {
+			t.Errorf("unexpected namespace: %q", r.Header.Get("X-Vault-Namespace"))
+		}
+		switch r.URL.Path {
+		case "/v1/secret/db":
+			_, _ = w.Write([]byte(`{"data": {"password": "v1-password", "port": 5432}}`))
+		case "/v1/kv/data/db":
+			_, _ = w.Write([]byte(`{"data": {"data": {"password": "v2-password"}, "metadata": {"version": 3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": []}`))
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestResolve(t *testing.T) {
+	ts := newVaultServer(t)
+	t.Setenv("VAULT_ADDR", ts.URL+"/")
+	t.Setenv("VAULT_TOKEN", "my-token")
+	t.Setenv("VAULT_NAMESPACE", "my-namespace")
+
+	tcs := []struct {
+		desc    string
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{desc: "kv version 1", ref: "secret/db#password", want: "v1-password"},
+		{desc: "kv version 2", ref: "kv/data/db#password", want: "v2-password"},
+		{desc: "number", ref: "secret/db#port", want: "5432"},
+		{desc: "missing field", ref: "secret/db#user", wantErr: `unable to resolve secret "vault:secret/db#user": secret has no field "user"`},
+		{desc: "missing secret", ref: "secret/missing#password", wantErr: `unable to resolve secret "vault:secret/missing#password": unexpected status "404 Not Found" from vault`},
+		{desc: "invalid reference", ref: "secret/db", wantErr: `unable to resolve secret "vault:secret/db": invalid reference "secret/db" (must be in the form of '<path>#<field>')`},
+	}
+	s := secrets.NewSession()
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := s.Resolve(context.Background(), vault.Scheme, tc.ref)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected secret: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFailResolve(t *testing.T) {
+	ts := newVaultServer(t)
+	r := &vault.Resolver{Address: ts.URL, Token: "wrong-token", Namespace: "my-namespace", Client: ts.Client()}
+	_, err := r.Resolve(context.Background(), "secret/db#password")
+	want := `unexpected status "403 Forbidden" from vault: permission denied`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+
+	t.Setenv("VAULT_TOKEN", "")
+	_, err = secrets.NewSession().Resolve(context.Background(), vault.Scheme, "secret/db#password")
+	want = `unable to initialize "vault" secret resolver: environment variable VAULT_TOKEN is not set`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
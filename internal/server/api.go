@@ -47,6 +47,8 @@ func apiRouter(s *Server) (chi.Router, error) {
 		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
 	})
 
+	r.Get("/sources", func(w http.ResponseWriter, r *http.Request) { sourcesHandler(s, w, r) })
+
 	return r, nil
 }
 
@@ -84,7 +86,14 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
-	render.JSON(w, r, toolset.Manifest)
+	toolset = s.authorizedToolset(ctx, toolset, r.Header)
+	page, _, err := toolset.Paginate(r.URL.Query().Get("cursor"), s.pageSize)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	render.JSON(w, r, page.Manifest)
 }
 
 // toolGetHandler handles requests for a single Tool.
@@ -159,6 +168,8 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
+	// keep the sources of the tool open until the invocation is done
+	defer s.ResourceMgr.Track()()
 	tool, ok := s.ResourceMgr.GetTool(toolName)
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
@@ -213,6 +224,12 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
 		return
 	}
+	// Check the claims against the authorization policies of the tool
+	if err = tools.AuthorizePolicies(toolName, tool, claimsFromAuth); err != nil {
+		s.recordPolicyDenial(ctx, err)
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	}
 	s.logger.DebugContext(ctx, "tool invocation authorized")
 
 	var data map[string]any
@@ -232,6 +249,12 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
 			return
 		}
+		// If the source of the tool failed to initialize, return 503
+		if errors.Is(err, tools.ErrUnavailable) {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusServiceUnavailable))
+			return
+		}
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
@@ -239,7 +262,17 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-	res, err := tool.Invoke(ctx, params, accessToken)
+	format, err := tools.ParseResultFormat(r.URL.Query().Get("resultFormat"))
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	ctx = tools.WithResultFormat(ctx, format)
+	ctx = tools.WithPageTokenSigner(ctx, s.pageTokens)
+	ctx = tools.WithPageTokenCaller(ctx, claimsFromAuth)
+
+	res, err := tools.InvokeWithTimeout(ctx, tool, params, accessToken)
 
 	// Determine what error to return to the users.
 	if err != nil {
@@ -269,10 +302,32 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		}
 		err = fmt.Errorf("error while invoking tool: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
+		// If the query exceeded the timeout of the tool, return 504
+		if errors.Is(err, tools.ErrTimeout) {
+			_ = render.Render(w, r, newErrResponse(err, http.StatusGatewayTimeout))
+			return
+		}
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
 	}
 
+	// tools that do not encode their rows are encoded with sorted columns
+	if e, ok := tools.EncodeResult(res, format).(tools.EncodedRows); ok {
+		var text string
+		text, err = e.Text()
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+			return
+		}
+		rr := &resultResponse{Result: text}
+		if e.Format != tools.ColumnarJSON {
+			_, rr.Truncated, rr.NextPageToken = e.Rows()
+		}
+		_ = render.Render(w, r, rr)
+		return
+	}
+
 	resMarshal, err := json.Marshal(res)
 	if err != nil {
 		err = fmt.Errorf("unable to marshal result: %w", err)
@@ -289,6 +344,10 @@ var _ render.Renderer = &resultResponse{} // Renderer interface for managing res
 // resultResponse is the response sent back when the tool was invocated successfully.
 type resultResponse struct {
 	Result string `json:"result"` // result of tool invocation
+	// Truncated and NextPageToken are the markers of rows encoded as CSV or
+	// Markdown, which cannot hold them.
+	Truncated     bool   `json:"truncated,omitempty"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
 }
 
 // Render renders a single payload and respond to the client request.
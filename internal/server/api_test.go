@@ -16,13 +16,25 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -123,6 +135,80 @@ func TestToolsetEndpoint(t *testing.T) {
 	}
 }
 
+func TestToolsetEndpointPagination(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		pageSize:        2,
+		ResourceMgr:     resources.NewResourceManager(nil, nil, toolsMap, toolsets, nil),
+	}
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	var gotNames []string
+	path := "/toolset/"
+	for pages := 1; ; pages++ {
+		resp, body, err := runRequest(ts, http.MethodGet, path, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var m tools.ToolsetManifest
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("unable to parse ToolsetManifest: %s", err)
+		}
+		if len(m.ToolsManifest) > server.pageSize {
+			t.Fatalf("page has %d tools, want at most %d", len(m.ToolsManifest), server.pageSize)
+		}
+		for name := range m.ToolsManifest {
+			gotNames = append(gotNames, name)
+		}
+		if m.NextCursor == "" {
+			if pages != 2 {
+				t.Fatalf("unexpected number of pages: want 2, got %d", pages)
+			}
+			break
+		}
+		path = "/toolset/?cursor=" + url.QueryEscape(m.NextCursor)
+	}
+	slices.Sort(gotNames)
+	wantNames := []string{tool3.Name, tool1.Name, tool2.Name}
+	slices.Sort(wantNames)
+	if !slices.Equal(gotNames, wantNames) {
+		t.Fatalf("unexpected tools: want %v, got %v", wantNames, gotNames)
+	}
+
+	resp, _, err := runRequest(ts, http.MethodGet, "/toolset/?cursor=invalid", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
 func TestToolGetEndpoint(t *testing.T) {
 	mockTools := []MockTool{tool1, tool2}
 	toolsMap, toolsets := setUpResources(t, mockTools)
@@ -295,3 +381,343 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+// timeoutTool is used to mock a tool whose invocations exceed its timeout
+type timeoutTool struct {
+	MockTool
+}
+
+func (t timeoutTool) Invoke(ctx context.Context, _ tools.ParamValues, _ tools.AccessToken) (any, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (t timeoutTool) ToolTimeout() time.Duration {
+	return 10 * time.Millisecond
+}
+
+func TestToolInvokeTimeout(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	tool := timeoutTool{MockTool{Name: "slow_tool", Params: []tools.Parameter{}}}
+	toolsMap[tool.Name] = tool
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("response status code is not 504, got %d, %s", resp.StatusCode, string(body))
+	}
+	if !strings.Contains(string(body), "query timed out after 10ms") {
+		t.Fatalf("unexpected error: %s", string(body))
+	}
+}
+
+func TestToolInvokeResultFormat(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	tool := rowsTool{MockTool: MockTool{Name: "rows_tool", Params: []tools.Parameter{}}, truncated: true}
+	toolsMap[tool.Name] = tool
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		desc       string
+		query      string
+		wantStatus int
+		want       map[string]any
+	}{
+		{
+			desc:       "default format",
+			wantStatus: http.StatusOK,
+			want:       map[string]any{"result": `{"rows":[{"id":1,"name":"Hilton"},{"id":2,"name":null}],"truncated":true,"rowCount":2}`},
+		},
+		{
+			desc:       "csv",
+			query:      "?resultFormat=csv",
+			wantStatus: http.StatusOK,
+			want:       map[string]any{"result": "id,name\n1,Hilton\n2,\n", "truncated": true},
+		},
+		{
+			desc:       "columnar json",
+			query:      "?resultFormat=columnar-json",
+			wantStatus: http.StatusOK,
+			want:       map[string]any{"result": `{"columns":["id","name"],"rows":[[1,"Hilton"],[2,null]],"truncated":true,"rowCount":2}`},
+		},
+		{
+			desc:       "invalid format",
+			query:      "?resultFormat=xml",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke%s", tool.Name, tc.query), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, tc.wantStatus, string(body))
+			}
+			if tc.want == nil {
+				return
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("error parsing response body: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect response (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestToolPolicies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	toolPolicy, err := tools.ParsePolicy(fmt.Sprintf("tool %q", tool1.Name), `claims.email endsWith "@corp.com"`)
+	if err != nil {
+		t.Fatalf("unable to parse policy: %s", err)
+	}
+	toolsetPolicy, err := tools.ParsePolicy(`toolset "analyst_tools"`, `claims.groups contains "analysts"`)
+	if err != nil {
+		t.Fatalf("unable to parse policy: %s", err)
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	toolsMap[tool1.Name] = tools.WithPolicies(toolsMap[tool1.Name], toolPolicy, toolsetPolicy)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	authServices := map[string]auth.AuthService{"my-auth": MockAuthService{Name: "my-auth"}}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(nil, authServices, toolsMap, toolsets, nil),
+	}
+	apiR, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	apiTs := runServer(apiR, false)
+	defer apiTs.Close()
+	mcpR, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	mcpTs := runServer(mcpR, false)
+	defer mcpTs.Close()
+
+	testCases := []struct {
+		name       string
+		toolName   string
+		claims     string
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name:       "allowed",
+			toolName:   tool1.Name,
+			claims:     `{"email": "jane@corp.com", "groups": ["analysts"]}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "denied by tool policy",
+			toolName:   tool1.Name,
+			claims:     `{"email": "jane@other.com", "groups": ["analysts"]}`,
+			wantStatus: http.StatusForbidden,
+			wantErr:    `tool \"no_params\" invocation denied by tool \"no_params\" policy`,
+		},
+		{
+			name:       "denied by toolset policy",
+			toolName:   tool1.Name,
+			claims:     `{"email": "jane@corp.com", "groups": ["eng"]}`,
+			wantStatus: http.StatusForbidden,
+			wantErr:    `tool \"no_params\" invocation denied by toolset \"analyst_tools\" policy`,
+		},
+		{
+			name:       "denied without claims",
+			toolName:   tool1.Name,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "no verified auth service claims",
+		},
+		{
+			name:       "tool without policies",
+			toolName:   tool2.Name,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range testCases {
+		header := map[string]string{}
+		if tc.claims != "" {
+			header["my-auth_token"] = tc.claims
+		}
+		t.Run("api "+tc.name, func(t *testing.T) {
+			body := bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`))
+			resp, respBody, err := runRequest(apiTs, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), body, header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, respBody)
+			}
+			if !strings.Contains(string(respBody), tc.wantErr) {
+				t.Fatalf("unexpected response: got %s, want to contain %s", respBody, tc.wantErr)
+			}
+		})
+		t.Run("mcp "+tc.name, func(t *testing.T) {
+			reqBody := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": %q, "arguments": {"param1": 1, "param2": 2}}}`, tc.toolName)
+			resp, respBody, err := runRequest(mcpTs, http.MethodPost, "/", bytes.NewBuffer([]byte(reqBody)), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, respBody)
+			}
+			if !strings.Contains(string(respBody), tc.wantErr) {
+				t.Fatalf("unexpected response: got %s, want to contain %s", respBody, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterToolsByAuth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	authTool := MockTool{Name: "auth_tool", Params: []tools.Parameter{}, authRequired: []string{"my-auth"}}
+	policyTool := MockTool{Name: "policy_tool", Params: []tools.Parameter{}}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, authTool, policyTool, tool4, tool5})
+	policy, err := tools.ParsePolicy(fmt.Sprintf("tool %q", policyTool.Name), `claims.groups contains "admins"`)
+	if err != nil {
+		t.Fatalf("unable to parse policy: %s", err)
+	}
+	toolsMap[policyTool.Name] = tools.WithPolicies(toolsMap[policyTool.Name], policy)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	authServices := map[string]auth.AuthService{"my-auth": MockAuthService{Name: "my-auth"}}
+	newServer := func(filter bool) (*httptest.Server, *httptest.Server) {
+		server := &Server{
+			version:           fakeVersionString,
+			logger:            testLogger,
+			instrumentation:   instrumentation,
+			sseManager:        newSseManager(ctx),
+			filterToolsByAuth: filter,
+			ResourceMgr:       resources.NewResourceManager(nil, authServices, toolsMap, toolsets, nil),
+		}
+		apiR, err := apiRouter(server)
+		if err != nil {
+			t.Fatalf("unable to initialize api router: %s", err)
+		}
+		mcpR, err := mcpRouter(server)
+		if err != nil {
+			t.Fatalf("unable to initialize mcp router: %s", err)
+		}
+		apiTs, mcpTs := runServer(apiR, false), runServer(mcpR, false)
+		t.Cleanup(apiTs.Close)
+		t.Cleanup(mcpTs.Close)
+		return apiTs, mcpTs
+	}
+
+	testCases := []struct {
+		name   string
+		filter bool
+		header map[string]string
+		want   []string
+	}{
+		{
+			name:   "filtering disabled",
+			filter: false,
+			want:   []string{tool1.Name, authTool.Name, policyTool.Name, tool4.Name, tool5.Name},
+		},
+		{
+			name:   "unauthenticated caller",
+			filter: true,
+			want:   []string{tool1.Name},
+		},
+		{
+			name:   "authenticated caller",
+			filter: true,
+			header: map[string]string{"my-auth_token": `{"groups": ["eng"]}`},
+			want:   []string{tool1.Name, authTool.Name},
+		},
+		{
+			name:   "caller satisfying policy",
+			filter: true,
+			header: map[string]string{"my-auth_token": `{"groups": ["admins"]}`},
+			want:   []string{tool1.Name, authTool.Name, policyTool.Name},
+		},
+		{
+			name:   "caller with access token",
+			filter: true,
+			header: map[string]string{"Authorization": "Bearer token"},
+			want:   []string{tool1.Name, tool5.Name},
+		},
+	}
+	for _, tc := range testCases {
+		apiTs, mcpTs := newServer(tc.filter)
+		t.Run("api "+tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(apiTs, http.MethodGet, "/toolset", nil, tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+			}
+			var m tools.ToolsetManifest
+			if err := json.Unmarshal(body, &m); err != nil {
+				t.Fatalf("unable to parse ToolsetManifest: %s", err)
+			}
+			got := slices.Sorted(maps.Keys(m.ToolsManifest))
+			if diff := cmp.Diff(slices.Sorted(slices.Values(tc.want)), got); diff != "" {
+				t.Fatalf("unexpected tools: diff %v", diff)
+			}
+		})
+		t.Run("mcp "+tc.name, func(t *testing.T) {
+			reqBody := `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`
+			resp, body, err := runRequest(mcpTs, http.MethodPost, "/", bytes.NewBuffer([]byte(reqBody)), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+			}
+			var res struct {
+				Result struct {
+					Tools []tools.McpManifest `json:"tools"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(body, &res); err != nil {
+				t.Fatalf("unable to parse response: %s", err)
+			}
+			var got []string
+			for _, m := range res.Result.Tools {
+				got = append(got, m.Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected tools: diff %v", diff)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,7 +25,9 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
@@ -40,6 +43,7 @@ type MockTool struct {
 	Description                  string
 	Params                       []tools.Parameter
 	manifest                     tools.Manifest
+	authRequired                 []string
 	unauthorized                 bool
 	requiresClientAuthrorization bool
 }
@@ -64,7 +68,7 @@ func (t MockTool) Manifest() tools.Manifest {
 
 func (t MockTool) Authorized(verifiedAuthServices []string) bool {
 	// defaulted to true
-	return !t.unauthorized
+	return !t.unauthorized && tools.IsAuthorized(t.authRequired, verifiedAuthServices)
 }
 
 func (t MockTool) RequiresClientAuthorization() bool {
@@ -109,6 +113,34 @@ func (t MockTool) McpManifest() tools.McpManifest {
 	return mcpManifest
 }
 
+var _ auth.AuthService = MockAuthService{}
+
+// MockAuthService is used to mock auth services in tests. The claims are read
+// as JSON from the "<name>_token" header.
+type MockAuthService struct {
+	Name string
+}
+
+func (a MockAuthService) AuthServiceKind() string {
+	return "mock"
+}
+
+func (a MockAuthService) GetName() string {
+	return a.Name
+}
+
+func (a MockAuthService) GetClaimsFromHeader(_ context.Context, h http.Header) (map[string]any, error) {
+	token := h.Get(a.Name + "_token")
+	if token == "" {
+		return nil, nil
+	}
+	var claims map[string]any
+	if err := json.Unmarshal([]byte(token), &claims); err != nil {
+		return nil, fmt.Errorf("invalid mock token: %w", err)
+	}
+	return claims, nil
+}
+
 var tool1 = MockTool{
 	Name:   "no_params",
 	Params: []tools.Parameter{},
@@ -189,7 +221,7 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := NewResourceManager(nil, nil, tools, toolsets)
+	resourceManager := resources.NewResourceManager(nil, nil, tools, toolsets, nil)
 
 	server := Server{
 		version:         fakeVersionString,
@@ -17,10 +17,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/auth/oidc"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -41,6 +44,8 @@ type ServerConfig struct {
 	ToolConfigs ToolConfigs
 	// ToolsetConfigs defines what tools are available.
 	ToolsetConfigs ToolsetConfigs
+	// PromptConfigs defines what prompts are available.
+	PromptConfigs PromptConfigs
 	// LoggingFormat defines whether structured loggings are used.
 	LoggingFormat logFormat
 	// LogLevel defines the levels to log.
@@ -57,6 +62,18 @@ type ServerConfig struct {
 	DisableReload bool
 	// UI indicates if Toolbox UI endpoints (/ui) are available
 	UI bool
+	// PageSize is the maximum number of tools returned per page when listing
+	// tools. Pagination is disabled if it is 0.
+	PageSize int
+	// PageTokenKey is the key that the page tokens of paginated tools are
+	// signed with. A random key is generated if it is empty.
+	PageTokenKey string
+	// PageTokenTTL is how long the page tokens of paginated tools are valid
+	// for.
+	PageTokenTTL time.Duration
+	// FilterToolsByAuth indicates if tools that the caller isn't authorized
+	// to invoke are omitted when listing tools.
+	FilterToolsByAuth bool
 }
 
 type logFormat string
@@ -132,6 +149,18 @@ func (c *SourceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interf
 			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
 		}
 
+		// `startup` is supported by every kind of source, so it is removed
+		// before decoding the kind-specific config
+		startup := sources.StartupEager
+		if st, ok := v["startup"]; ok {
+			str, _ := st.(string)
+			startup = sources.StartupPolicy(str)
+			if !startup.Valid() {
+				return fmt.Errorf("invalid 'startup' field for source %q (must be one of %q)", name, sources.StartupPolicies)
+			}
+			delete(v, "startup")
+		}
+
 		kind, ok := v["kind"]
 		if !ok {
 			return fmt.Errorf("missing 'kind' field for source %q", name)
@@ -150,6 +179,9 @@ func (c *SourceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interf
 		if err != nil {
 			return err
 		}
+		if startup != sources.StartupEager {
+			sourceConfig = sources.StartupConfig{SourceConfig: sourceConfig, Startup: startup}
+		}
 		(*c)[name] = sourceConfig
 	}
 	return nil
@@ -191,6 +223,12 @@ func (c *AuthServiceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(i
 				return fmt.Errorf("unable to parse as %q: %w", kind, err)
 			}
 			(*c)[name] = actual
+		case oidc.AuthServiceKind, oidc.JWTAuthServiceKind:
+			actual := oidc.Config{Name: name}
+			if err := dec.DecodeContext(ctx, &actual); err != nil {
+				return fmt.Errorf("unable to parse as %q: %w", kind, err)
+			}
+			(*c)[name] = actual
 		default:
 			return fmt.Errorf("%q is not a valid kind of auth source", kind)
 		}
@@ -218,6 +256,12 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
 		}
 
+		// Merge the templates extended by the tool before decoding its kind
+		v, err := toolTemplatesFromContext(ctx).Extend(name, v)
+		if err != nil {
+			return err
+		}
+
 		// `authRequired` and `useClientOAuth` cannot be specified together
 		if v["authRequired"] != nil && v["useClientOAuth"] == true {
 			return fmt.Errorf("`authRequired` and `useClientOAuth` are mutually exclusive. Choose only one authentication method")
@@ -228,6 +272,20 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 			v["authRequired"] = []string{}
 		}
 
+		// `allow` is supported by every kind of tool, so it is removed before
+		// decoding the kind-specific config
+		var allow string
+		if a, ok := v["allow"]; ok {
+			allow, ok = a.(string)
+			if !ok || allow == "" {
+				return fmt.Errorf("invalid 'allow' field for tool %q (must be a non-empty string)", name)
+			}
+			if _, err := tools.ParsePolicy(fmt.Sprintf("tool %q", name), allow); err != nil {
+				return err
+			}
+			delete(v, "allow")
+		}
+
 		kindVal, ok := v["kind"]
 		if !ok {
 			return fmt.Errorf("missing 'kind' field for tool %q", name)
@@ -246,6 +304,9 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 		if err != nil {
 			return err
 		}
+		if allow != "" {
+			toolCfg = tools.PolicyConfig{ToolConfig: toolCfg, Name: name, Allow: allow}
+		}
 		(*c)[name] = toolCfg
 	}
 	return nil
@@ -260,13 +321,73 @@ var _ yaml.InterfaceUnmarshalerContext = &ToolsetConfigs{}
 func (c *ToolsetConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	*c = make(ToolsetConfigs)
 
-	var raw map[string][]string
+	var raw map[string]util.DelayedUnmarshaler
 	if err := unmarshal(&raw); err != nil {
 		return err
 	}
 
-	for name, toolList := range raw {
-		(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: toolList}
+	for name, u := range raw {
+		// a toolset is either a list of tool names, or a mapping with a list
+		// of tools and an optional policy
+		var toolList []string
+		if err := u.Unmarshal(&toolList); err == nil {
+			(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: toolList}
+			continue
+		}
+		var v map[string]any
+		if err := u.Unmarshal(&v); err != nil {
+			return fmt.Errorf("unable to parse toolset %q: must be a list of tools, or a mapping with 'tools' and 'allow'", name)
+		}
+		dec, err := util.NewStrictDecoder(v)
+		if err != nil {
+			return fmt.Errorf("error creating YAML decoder for toolset %q: %w", name, err)
+		}
+		var actual struct {
+			Tools []string `yaml:"tools" validate:"required"`
+			Allow string   `yaml:"allow"`
+		}
+		if err := dec.DecodeContext(ctx, &actual); err != nil {
+			return fmt.Errorf("unable to parse toolset %q: %w", name, err)
+		}
+		if actual.Allow != "" {
+			if _, err := tools.ParsePolicy(fmt.Sprintf("toolset %q", name), actual.Allow); err != nil {
+				return err
+			}
+		}
+		(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: actual.Tools, Allow: actual.Allow}
+	}
+	return nil
+}
+
+// PromptConfigs is a type used to allow unmarshal of the prompt configs
+type PromptConfigs map[string]prompts.Config
+
+// validate interface
+var _ yaml.InterfaceUnmarshalerContext = &PromptConfigs{}
+
+func (c *PromptConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	*c = make(PromptConfigs)
+
+	var raw map[string]util.DelayedUnmarshaler
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	for name, u := range raw {
+		var v map[string]any
+		if err := u.Unmarshal(&v); err != nil {
+			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
+		}
+
+		dec, err := util.NewStrictDecoder(v)
+		if err != nil {
+			return fmt.Errorf("error creating YAML decoder for prompt %q: %w", name, err)
+		}
+		actual := prompts.Config{Name: name}
+		if err := dec.DecodeContext(ctx, &actual); err != nil {
+			return fmt.Errorf("unable to parse prompt %q: %w", name, err)
+		}
+		(*c)[name] = actual
 	}
 	return nil
 }
@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// healthCheckTimeout bounds the duration of the ping of a source.
+const healthCheckTimeout = 5 * time.Second
+
+// Statuses of a source.
+const (
+	// SourceStatusOK is the status of a source whose last ping succeeded.
+	SourceStatusOK = "ok"
+	// SourceStatusError is the status of a source whose last ping failed.
+	SourceStatusError = "error"
+	// SourceStatusUnavailable is the status of an optional source that failed
+	// to initialize.
+	SourceStatusUnavailable = "unavailable"
+	// SourceStatusNotInitialized is the status of a lazy source that wasn't
+	// used yet.
+	SourceStatusNotInitialized = "not initialized"
+	// SourceStatusUnknown is the status of a source that doesn't support
+	// health checks.
+	SourceStatusUnknown = "unknown"
+)
+
+// SourceStatus is the health of a source, as served by /api/sources.
+type SourceStatus struct {
+	Name               string                `json:"name"`
+	Kind               string                `json:"kind"`
+	Startup            sources.StartupPolicy `json:"startup"`
+	Status             string                `json:"status"`
+	LastSuccessfulPing *time.Time            `json:"lastSuccessfulPing,omitempty"`
+	LastError          string                `json:"lastError,omitempty"`
+	LastErrorTime      *time.Time            `json:"lastErrorTime,omitempty"`
+	Pool               *sources.PoolStats    `json:"pool,omitempty"`
+}
+
+// sourceHealth records the results of the health checks of the sources, by
+// name. The zero value is ready to use.
+type sourceHealth struct {
+	mu      sync.Mutex
+	results map[string]healthResult
+}
+
+type healthResult struct {
+	lastSuccess   time.Time
+	lastError     string
+	lastErrorTime time.Time
+}
+
+// record records the result of a health check, and returns the results of
+// the source so far.
+func (h *sourceHealth) record(name string, err error) healthResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.results == nil {
+		h.results = make(map[string]healthResult)
+	}
+	r := h.results[name]
+	if err == nil {
+		r.lastSuccess = time.Now()
+	} else {
+		r.lastError = err.Error()
+		r.lastErrorTime = time.Now()
+	}
+	h.results[name] = r
+	return r
+}
+
+// get returns the results of the health checks of a source so far.
+func (h *sourceHealth) get(name string) healthResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.results[name]
+}
+
+// checkSources checks the health of every source concurrently, and returns
+// their status sorted by name.
+func (s *Server) checkSources(ctx context.Context) []SourceStatus {
+	var configs SourceConfigs
+	var unavailable map[string]error
+	if loaded := s.loaded.Load(); loaded != nil {
+		configs = loaded.cfg.SourceConfigs
+		unavailable = loaded.unavailableSources
+	}
+	srcs := s.ResourceMgr.GetSourcesMap()
+	names := slices.Sorted(maps.Keys(srcs))
+	for name := range unavailable {
+		if _, ok := srcs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	statuses := make([]SourceStatus, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = s.checkSource(ctx, name, srcs[name], configs[name], unavailable[name])
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// checkSource checks the health of a source. initErr is the error of an
+// optional source that failed to initialize.
+func (s *Server) checkSource(ctx context.Context, name string, src sources.Source, sc sources.SourceConfig, initErr error) SourceStatus {
+	status := SourceStatus{Name: name, Startup: sources.StartupEager}
+	if sc != nil {
+		status.Kind = sc.SourceConfigKind()
+		status.Startup = sources.GetStartupPolicy(sc)
+	}
+	if lazy, ok := src.(*lazySource); ok {
+		src = lazy.initialized()
+	}
+
+	var result healthResult
+	switch {
+	case initErr != nil:
+		status.Status = SourceStatusUnavailable
+		result = s.sourceHealth.record(name, initErr)
+	case src == nil:
+		status.Status = SourceStatusNotInitialized
+		result = s.sourceHealth.get(name)
+	default:
+		if status.Kind == "" {
+			status.Kind = src.SourceKind()
+		}
+		hc, ok := src.(sources.HealthChecker)
+		if !ok {
+			status.Status = SourceStatusUnknown
+			break
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := hc.Ping(pingCtx)
+		cancel()
+		status.Status = SourceStatusOK
+		if err != nil {
+			status.Status = SourceStatusError
+			err = fmt.Errorf("unable to ping source %q: %w", name, err)
+			s.logger.DebugContext(ctx, err.Error())
+		}
+		result = s.sourceHealth.record(name, err)
+	}
+	if ps, ok := src.(sources.PoolStatsProvider); ok {
+		stats := ps.PoolStats()
+		status.Pool = &stats
+	}
+
+	if !result.lastSuccess.IsZero() {
+		status.LastSuccessfulPing = &result.lastSuccess
+	}
+	if result.lastError != "" {
+		status.LastError = result.lastError
+		status.LastErrorTime = &result.lastErrorTime
+	}
+	return status
+}
+
+// healthzHandler reports that the process is up.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]string{"status": "ok"})
+}
+
+// readyzHandler reports whether every eager source passes a ping. Sources
+// that don't support health checks are considered ready. Since the endpoint
+// isn't authenticated, only the names of the failing sources are returned,
+// and their errors are served by sourcesHandler.
+func readyzHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/readyz")
+	defer span.End()
+
+	failed := make([]string, 0)
+	for _, status := range s.checkSources(ctx) {
+		if status.Startup != sources.StartupEager {
+			continue
+		}
+		if status.Status != SourceStatusOK && status.Status != SourceStatusUnknown {
+			failed = append(failed, status.Name)
+		}
+	}
+	if len(failed) > 0 {
+		render.Status(r, http.StatusServiceUnavailable)
+		render.JSON(w, r, map[string]any{"status": "not ready", "sources": failed})
+		return
+	}
+	render.JSON(w, r, map[string]string{"status": "ready"})
+}
+
+// sourcesHandler serves the status of every source. Callers must be verified
+// by at least one of the configured auth services.
+func sourcesHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/sources/get")
+	defer span.End()
+
+	if !s.authenticated(ctx, r.Header) {
+		err := fmt.Errorf("listing sources requires a token from one of the configured auth services")
+		s.logger.DebugContext(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+	render.JSON(w, r, map[string]any{"sources": s.checkSources(ctx)})
+}
+
+// authenticated reports whether any of the auth services verifies the
+// claims in header.
+func (s *Server) authenticated(ctx context.Context, header http.Header) bool {
+	for _, aS := range s.ResourceMgr.GetAuthServiceMap() {
+		claims, err := aS.GetClaimsFromHeader(ctx, header)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			continue
+		}
+		if claims != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// pingSourceConfig initializes a pingSource shared with the test.
+type pingSourceConfig struct {
+	source *pingSource
+}
+
+func (c pingSourceConfig) SourceConfigKind() string {
+	return "mock-ping"
+}
+
+func (c pingSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	return c.source, nil
+}
+
+var _ sources.HealthChecker = &pingSource{}
+var _ sources.PoolStatsProvider = &pingSource{}
+
+// pingSource fails its health checks while down is set.
+type pingSource struct {
+	down atomic.Bool
+}
+
+func (s *pingSource) SourceKind() string {
+	return "mock-ping"
+}
+
+func (s *pingSource) Ping(context.Context) error {
+	if s.down.Load() {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (s *pingSource) PoolStats() sources.PoolStats {
+	return sources.PoolStats{MaxConns: 4, OpenConns: 2, InUseConns: 1, IdleConns: 1}
+}
+
+type mockAuthServiceConfig struct {
+	Name string
+}
+
+func (c mockAuthServiceConfig) AuthServiceConfigKind() string {
+	return "mock"
+}
+
+func (c mockAuthServiceConfig) Initialize() (auth.AuthService, error) {
+	return MockAuthService{Name: c.Name}, nil
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	ctx := newStartupTestContext(t)
+	eager := &pingSource{}
+	cfg := ServerConfig{
+		Version: fakeVersionString,
+		SourceConfigs: SourceConfigs{
+			"eager-source": pingSourceConfig{source: eager},
+			"lazy-source": sources.StartupConfig{
+				SourceConfig: mockSourceConfig{Name: "lazy-source"},
+				Startup:      sources.StartupLazy,
+			},
+			"optional-source": sources.StartupConfig{
+				SourceConfig: mockSourceConfig{Name: "optional-source", Conn: "unreachable", Fail: true},
+				Startup:      sources.StartupOptional,
+			},
+			"unchecked-source": mockSourceConfig{Name: "unchecked-source"},
+		},
+		AuthServiceConfigs: AuthServiceConfigs{"my-auth": mockAuthServiceConfig{Name: "my-auth"}},
+	}
+	s, err := NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+	ts := runServer(s.root, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodGet, "/healthz", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response from /healthz: %d, %s", resp.StatusCode, body)
+	}
+
+	// the optional and lazy sources don't prevent readiness
+	resp, body, err = runRequest(ts, http.MethodGet, "/readyz", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response from /readyz: %d, %s", resp.StatusCode, body)
+	}
+
+	eager.down.Store(true)
+	resp, body, err = runRequest(ts, http.MethodGet, "/readyz", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected response from /readyz with a failing source: %d, %s", resp.StatusCode, body)
+	}
+	var notReady struct {
+		Sources []string `json:"sources"`
+	}
+	if err := json.Unmarshal(body, &notReady); err != nil {
+		t.Fatalf("unable to parse response: %s", err)
+	}
+	if want := []string{"eager-source"}; !cmp.Equal(notReady.Sources, want) {
+		t.Fatalf("unexpected failing sources: got %v, want %v", notReady.Sources, want)
+	}
+	// the errors of the sources are only served to authenticated callers
+	if strings.Contains(string(body), "connection refused") {
+		t.Fatalf("unexpected error of failing source in response: %s", body)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodGet, "/api/sources", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unexpected response from /api/sources without a token: %d, %s", resp.StatusCode, body)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodGet, "/api/sources", nil, map[string]string{"my-auth_token": `{"sub": "admin"}`})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response from /api/sources: %d, %s", resp.StatusCode, body)
+	}
+	var got struct {
+		Sources []SourceStatus `json:"sources"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse response: %s", err)
+	}
+	if len(got.Sources) != 4 {
+		t.Fatalf("unexpected number of sources: %+v", got.Sources)
+	}
+	e := got.Sources[0]
+	if e.Name != "eager-source" || e.Kind != "mock-ping" || e.Status != SourceStatusError || e.LastSuccessfulPing == nil || e.LastErrorTime == nil || e.Pool == nil || e.Pool.MaxConns != 4 {
+		t.Fatalf("unexpected status of the eager source: %+v", e)
+	}
+
+	// the timestamps and errors are checked above
+	for i := range got.Sources {
+		got.Sources[i].LastSuccessfulPing = nil
+		got.Sources[i].LastError = ""
+		got.Sources[i].LastErrorTime = nil
+		got.Sources[i].Pool = nil
+	}
+	want := []SourceStatus{
+		{Name: "eager-source", Kind: "mock-ping", Startup: sources.StartupEager, Status: SourceStatusError},
+		{Name: "lazy-source", Kind: "mock", Startup: sources.StartupLazy, Status: SourceStatusNotInitialized},
+		{Name: "optional-source", Kind: "mock", Startup: sources.StartupOptional, Status: SourceStatusUnavailable},
+		{Name: "unchecked-source", Kind: "mock", Startup: sources.StartupEager, Status: SourceStatusUnknown},
+	}
+	if diff := cmp.Diff(want, got.Sources); diff != "" {
+		t.Fatalf("unexpected statuses (-want +got):\n%s", diff)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -36,6 +37,7 @@ import (
 	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	v20241105 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20241105"
 	v20250326 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250326"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
@@ -49,6 +51,21 @@ type sseSession struct {
 	done       chan struct{}
 	eventQueue chan string
 	lastActive time.Time
+	state      *sessionState
+}
+
+// queueMessage queues a JSON-RPC message to be sent to the client through the
+// sse stream.
+func (s *sseSession) queueMessage(message any) error {
+	eventData, _ := json.Marshal(message)
+	select {
+	case s.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+		return nil
+	case <-s.done:
+		return fmt.Errorf("session is closed")
+	default:
+		return fmt.Errorf("unable to add to event queue")
+	}
 }
 
 // sseManager manages and control access to sse sessions
@@ -111,11 +128,145 @@ func (m *sseManager) cleanupRoutine(ctx context.Context) {
 	}
 }
 
+// sessionState is the state shared by the messages of a stateful session
+// (stdio or http with sse).
+type sessionState struct {
+	mu       sync.Mutex
+	inFlight map[string]*inFlightRequest
+	// notify sends a server-initiated notification to the client.
+	notify func(ctx context.Context, notification any) error
+	// initialized is set once the client has initialized the session
+	initialized atomic.Bool
+}
+
+// sessionRegistry tracks the live stateful sessions, so that the server is
+// able to notify their clients of changes. The zero value is ready to use.
+type sessionRegistry struct {
+	mu     sync.Mutex
+	states map[*sessionState]struct{}
+}
+
+// add registers the state of a session. The returned func removes it once the
+// session is closed.
+func (r *sessionRegistry) add(st *sessionState) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.states == nil {
+		r.states = make(map[*sessionState]struct{})
+	}
+	r.states[st] = struct{}{}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.states, st)
+	}
+}
+
+// notifyAll sends a notification to the clients of every initialized
+// session. Notifications are sent in the background, so that a slow client
+// doesn't hold up the caller or the other clients.
+func (r *sessionRegistry) notifyAll(ctx context.Context, notification any) {
+	r.mu.Lock()
+	states := make([]*sessionState, 0, len(r.states))
+	for st := range r.states {
+		if st.initialized.Load() {
+			states = append(states, st)
+		}
+	}
+	r.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, st := range states {
+		go func() {
+			if err := st.notify(ctx, notification); err != nil {
+				if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+					logger.DebugContext(ctx, fmt.Sprintf("unable to send notification: %s", err))
+				}
+			}
+		}()
+	}
+}
+
+// notifyToolsListChanged notifies the clients of the live sessions that the
+// list of tools has changed.
+func (s *Server) notifyToolsListChanged(ctx context.Context) {
+	s.sessions.notifyAll(ctx, mcputil.NewToolListChangedNotification())
+}
+
+// inFlightRequest is a request that is still being processed.
+type inFlightRequest struct {
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+func newSessionState(notify func(ctx context.Context, notification any) error) *sessionState {
+	return &sessionState{
+		inFlight: make(map[string]*inFlightRequest),
+		notify:   notify,
+	}
+}
+
+// requestKey returns the key of a request id. Ids are compared by their JSON
+// representation, since they could either be a string or a number.
+func requestKey(id jsonrpc.RequestId) string {
+	b, _ := json.Marshal(id)
+	return string(b)
+}
+
+// track registers a request as in-flight. The returned context is cancelled
+// if the client cancels the request, and reports progress to the client if a
+// progress token was provided. The returned func must be called once the
+// request is processed, and reports whether the request was cancelled.
+func (st *sessionState) track(ctx context.Context, id jsonrpc.RequestId, body []byte) (context.Context, func() bool) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var req jsonrpc.Request
+	if err := json.Unmarshal(body, &req); err == nil && req.Params.Meta.ProgressToken != nil {
+		token := req.Params.Meta.ProgressToken
+		ctx = util.WithProgressReporter(ctx, func(ctx context.Context, progress, total float64, message string) {
+			if err := st.notify(ctx, mcputil.NewProgressNotification(token, progress, total, message)); err != nil {
+				if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+					logger.DebugContext(ctx, fmt.Sprintf("unable to send progress notification: %s", err))
+				}
+			}
+		})
+	}
+
+	key := requestKey(id)
+	tracked := &inFlightRequest{cancel: cancel}
+	st.mu.Lock()
+	st.inFlight[key] = tracked
+	st.mu.Unlock()
+
+	return ctx, func() bool {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		delete(st.inFlight, key)
+		cancel()
+		return tracked.cancelled
+	}
+}
+
+// cancel cancels an in-flight request. It reports whether the request was found.
+func (st *sessionState) cancel(id jsonrpc.RequestId) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	req, ok := st.inFlight[requestKey(id)]
+	if !ok {
+		return false
+	}
+	req.cancelled = true
+	req.cancel()
+	return true
+}
+
 type stdioSession struct {
 	protocol string
 	server   *Server
 	reader   *bufio.Reader
 	writer   io.Writer
+	writeMu  sync.Mutex
+	state    *sessionState
 }
 
 func NewStdioSession(s *Server, stdin io.Reader, stdout io.Writer) *stdioSession {
@@ -124,15 +275,21 @@ func NewStdioSession(s *Server, stdin io.Reader, stdout io.Writer) *stdioSession
 		reader: bufio.NewReader(stdin),
 		writer: stdout,
 	}
+	stdioSession.state = newSessionState(stdioSession.write)
 	return stdioSession
 }
 
 func (s *stdioSession) Start(ctx context.Context) error {
+	defer s.server.sessions.add(s.state)()
 	return s.readInputStream(ctx)
 }
 
 // readInputStream reads requests/notifications from MCP clients through stdin
 func (s *stdioSession) readInputStream(ctx context.Context) error {
+	// wait for in-flight requests before returning
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -144,32 +301,54 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 			}
 			return err
 		}
-		v, res, err := processMcpMessage(ctx, []byte(line), s.server, s.protocol, "", nil)
-		if err != nil {
-			// errors during the processing of message will generate a valid MCP Error response.
-			// server can continue to run.
-			s.server.logger.ErrorContext(ctx, err.Error())
-		}
-		if v != "" {
-			s.protocol = v
+
+		// Requests are processed concurrently so that the client is able to
+		// cancel them while they are running. Initialize requests and
+		// notifications are processed in order.
+		var baseMessage jsonrpc.BaseMessage
+		if err := json.Unmarshal([]byte(line), &baseMessage); err == nil && baseMessage.Id != nil && baseMessage.Method != mcputil.INITIALIZE {
+			wg.Add(1)
+			go func(protocol string) {
+				defer wg.Done()
+				if err := s.processMessage(ctx, line, protocol); err != nil {
+					s.server.logger.ErrorContext(ctx, err.Error())
+				}
+			}(s.protocol)
+			continue
 		}
-		// no responses for notifications
-		if res != nil {
-			if err = s.write(ctx, res); err != nil {
-				return err
-			}
+		if err := s.processMessage(ctx, line, s.protocol); err != nil {
+			return err
 		}
 	}
 }
 
+// processMessage processes a single message and writes the response, if any.
+// It only returns an error if the response could not be written.
+func (s *stdioSession) processMessage(ctx context.Context, line string, protocol string) error {
+	v, res, err := processMcpMessage(ctx, []byte(line), s.server, protocol, "", nil, s.state)
+	if err != nil {
+		// errors during the processing of message will generate a valid MCP Error response.
+		// server can continue to run.
+		s.server.logger.ErrorContext(ctx, err.Error())
+	}
+	if v != "" {
+		s.protocol = v
+	}
+	// no responses for notifications
+	if res == nil {
+		return nil
+	}
+	return s.write(ctx, res)
+}
+
 // readLine process each line within the input stream.
 func (s *stdioSession) readLine(ctx context.Context) (string, error) {
+	// the channels are buffered and never closed, since the reading goroutine
+	// might still send to them after the context is cancelled
 	readChan := make(chan string, 1)
 	errChan := make(chan error, 1)
 	done := make(chan struct{})
 	defer close(done)
-	defer close(readChan)
-	defer close(errChan)
 
 	go func() {
 		select {
@@ -209,6 +388,9 @@ func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 func (s *stdioSession) write(ctx context.Context, response any) error {
 	res, _ := json.Marshal(response)
 
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	_, err := fmt.Fprintf(s.writer, "%s\n", res)
 	return err
 }
@@ -283,8 +465,12 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		done:       make(chan struct{}),
 		eventQueue: make(chan string, 100),
 	}
+	session.state = newSessionState(func(_ context.Context, notification any) error {
+		return session.queueMessage(notification)
+	})
 	s.sseManager.add(sessionId, session)
 	defer s.sseManager.remove(sessionId)
+	defer s.sessions.add(session.state)()
 
 	// https scheme formatting if (forwarded) request is a TLS request
 	proto := r.Header.Get("X-Forwarded-Proto")
@@ -406,7 +592,11 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, r.Header)
+	var state *sessionState
+	if session != nil {
+		state = session.state
+	}
+	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, r.Header, state)
 	if err != nil {
 		s.logger.DebugContext(ctx, fmt.Errorf("error processing message: %w", err).Error())
 	}
@@ -414,7 +604,6 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	// notifications will return empty string
 	if res == nil {
 		// Notifications do not expect a response
-		// Toolbox doesn't do anything with notifications yet
 		w.WriteHeader(http.StatusAccepted)
 		return
 	}
@@ -427,14 +616,10 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 
 	if session != nil {
 		// queue sse event
-		eventData, _ := json.Marshal(res)
-		select {
-		case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+		if qErr := session.queueMessage(res); qErr != nil {
+			s.logger.DebugContext(ctx, qErr.Error())
+		} else {
 			s.logger.DebugContext(ctx, "event queue successful")
-		case <-session.done:
-			s.logger.DebugContext(ctx, "session is close")
-		default:
-			s.logger.DebugContext(ctx, "unable to add to event queue")
 		}
 	}
 	if rpcResponse, ok := res.(jsonrpc.JSONRPCError); ok {
@@ -444,7 +629,10 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 		case jsonrpc.INVALID_REQUEST:
 			errStr := err.Error()
-			if errors.Is(err, tools.ErrUnauthorized) {
+			var denied *tools.PolicyDeniedError
+			if errors.As(err, &denied) {
+				w.WriteHeader(http.StatusForbidden)
+			} else if errors.Is(err, tools.ErrUnauthorized) {
 				w.WriteHeader(http.StatusUnauthorized)
 			} else if strings.Contains(errStr, "Error 401") {
 				w.WriteHeader(http.StatusUnauthorized)
@@ -458,8 +646,9 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, res)
 }
 
-// processMcpMessage process the messages received from clients
-func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, header http.Header) (string, any, error) {
+// processMcpMessage process the messages received from clients. State is nil
+// for stateless sessions (streamable HTTP).
+func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, header http.Header, state *sessionState) (string, any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return "", jsonrpc.NewError("", jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
@@ -497,24 +686,80 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 
 	// Check if message is a notification
 	if baseMessage.Id == nil {
+		if baseMessage.Method == mcputil.NOTIFICATIONS_CANCELLED {
+			return "", nil, cancelledNotificationHandler(ctx, body, state)
+		}
 		err := mcp.NotificationHandler(ctx, body)
 		return "", nil, err
 	}
 
 	switch baseMessage.Method {
 	case mcputil.INITIALIZE:
-		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version)
+		// resources are only advertised if the toolset uses a source that
+		// provides them
+		toolset, _ := s.ResourceMgr.GetToolset(toolsetName)
+		providers := s.schemaProviders(ctx, toolset, header)
+		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version, s.ResourceMgr, providers)
 		if err != nil {
 			return "", res, err
 		}
+		if state != nil {
+			state.initialized.Store(true)
+		}
 		return v, res, err
 	default:
+		// keep the sources used by the method open until it is done
+		defer s.ResourceMgr.Track()()
 		toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
 		if !ok {
 			err = fmt.Errorf("toolset does not exist")
 			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 		}
-		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.ResourceMgr.GetToolsMap(), s.ResourceMgr.GetAuthServiceMap(), body, header)
+		if baseMessage.Method == mcputil.TOOLS_LIST {
+			toolset = s.authorizedToolset(ctx, toolset, header)
+		}
+		var providers map[string]sources.SchemaProvider
+		if strings.HasPrefix(baseMessage.Method, "resources/") {
+			providers = s.schemaProviders(ctx, toolset, header)
+		}
+		ctx = tools.WithPageTokenSigner(ctx, s.pageTokens)
+		if state == nil {
+			res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.pageSize, s.ResourceMgr, providers, body, header)
+			s.recordPolicyDenial(ctx, err)
+			return "", res, err
+		}
+		reqCtx, done := state.track(ctx, baseMessage.Id, body)
+		res, err := mcp.ProcessMethod(reqCtx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.pageSize, s.ResourceMgr, providers, body, header)
+		s.recordPolicyDenial(ctx, err)
+		if cancelled := done(); cancelled {
+			// no response is sent for cancelled requests
+			logger.DebugContext(ctx, fmt.Sprintf("request %s was cancelled", requestKey(baseMessage.Id)))
+			return "", nil, err
+		}
 		return "", res, err
 	}
 }
+
+// cancelledNotificationHandler cancels the in-flight request referred to by a
+// cancelled notification.
+func cancelledNotificationHandler(ctx context.Context, body []byte, state *sessionState) error {
+	var notification mcputil.CancelledNotification
+	if err := util.DecodeJSON(bytes.NewBuffer(body), &notification); err != nil {
+		return fmt.Errorf("invalid cancelled notification: %w", err)
+	}
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	id := requestKey(notification.Params.RequestId)
+	if state == nil {
+		// stateless clients cancel requests by closing the connection
+		logger.DebugContext(ctx, fmt.Sprintf("ignoring cancellation of request %s: no session available", id))
+		return nil
+	}
+	// the request might have already finished, in which case the notification is ignored
+	if state.cancel(notification.Params.RequestId) {
+		logger.DebugContext(ctx, fmt.Sprintf("cancelled request %s: %s", id, notification.Params.Reason))
+	}
+	return nil
+}
@@ -21,12 +21,13 @@ import (
 	"net/http"
 	"slices"
 
-	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	v20241105 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20241105"
 	v20250326 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250326"
 	v20250618 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250618"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -43,8 +44,9 @@ var SUPPORTED_PROTOCOL_VERSIONS = []string{
 
 // InitializeResponse runs capability negotiation and protocol version agreement.
 // This is the Initialization phase of the lifecycle for MCP client-server connections.
-// Always start with the latest protocol version supported.
-func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string) (any, string, error) {
+// Always start with the latest protocol version supported. Resources are
+// advertised if any of the schemaProviders is available to the session.
+func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string, resourceMgr *resources.ResourceManager, schemaProviders map[string]sources.SchemaProvider) (any, string, error) {
 	var req mcputil.InitializeRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp initialize request: %w", err)
@@ -59,7 +61,8 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 		protocolVersion = LATEST_PROTOCOL_VERSION
 	}
 
-	toolsListChanged := false
+	// clients are notified when the tools change on reload
+	toolsListChanged := true
 	result := mcputil.InitializeResult{
 		ProtocolVersion: protocolVersion,
 		Capabilities: mcputil.ServerCapabilities{
@@ -74,6 +77,20 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 			Version: toolboxVersion,
 		},
 	}
+	// only advertise prompts if at least one prompt is configured
+	if len(resourceMgr.GetPromptsMap()) > 0 {
+		promptsListChanged := false
+		result.Capabilities.Prompts = &mcputil.ListChanged{
+			ListChanged: &promptsListChanged,
+		}
+	}
+	// only advertise resources if at least one source is able to provide them
+	if len(schemaProviders) > 0 {
+		resourcesListChanged := false
+		result.Capabilities.Resources = &mcputil.ResourcesCapability{
+			ListChanged: &resourcesListChanged,
+		}
+	}
 	res := jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
 		Id:      id,
@@ -95,14 +112,14 @@ func NotificationHandler(ctx context.Context, body []byte) error {
 
 // ProcessMethod returns a response for the request.
 // This is the Operation phase of the lifecycle for MCP client-server connections.
-func ProcessMethod(ctx context.Context, mcpVersion string, id jsonrpc.RequestId, method string, toolset tools.Toolset, tools map[string]tools.Tool, authServices map[string]auth.AuthService, body []byte, header http.Header) (any, error) {
+func ProcessMethod(ctx context.Context, mcpVersion string, id jsonrpc.RequestId, method string, toolset tools.Toolset, pageSize int, resourceMgr *resources.ResourceManager, schemaProviders map[string]sources.SchemaProvider, body []byte, header http.Header) (any, error) {
 	switch mcpVersion {
 	case v20250618.PROTOCOL_VERSION:
-		return v20250618.ProcessMethod(ctx, id, method, toolset, tools, authServices, body, header)
+		return v20250618.ProcessMethod(ctx, id, method, toolset, pageSize, resourceMgr, schemaProviders, body, header)
 	case v20250326.PROTOCOL_VERSION:
-		return v20250326.ProcessMethod(ctx, id, method, toolset, tools, authServices, body, header)
+		return v20250326.ProcessMethod(ctx, id, method, toolset, pageSize, resourceMgr, schemaProviders, body, header)
 	default:
-		return v20241105.ProcessMethod(ctx, id, method, toolset, tools, authServices, body, header)
+		return v20241105.ProcessMethod(ctx, id, method, toolset, pageSize, resourceMgr, schemaProviders, body, header)
 	}
 }
 
@@ -88,7 +88,18 @@ type ClientCapabilities struct {
 // capabilities are defined here, in this schema, but this is not a closed set: any
 // server can define its own, additional capabilities.
 type ServerCapabilities struct {
-	Tools *ListChanged `json:"tools,omitempty"`
+	Prompts   *ListChanged         `json:"prompts,omitempty"`
+	Resources *ResourcesCapability `json:"resources,omitempty"`
+	Tools     *ListChanged         `json:"tools,omitempty"`
+}
+
+// ResourcesCapability represents whether the server supports subscriptions and
+// notification for changes to the list of resources.
+type ResourcesCapability struct {
+	// Whether this server supports subscribing to resource updates.
+	Subscribe *bool `json:"subscribe,omitempty"`
+	// Whether this server supports notifications for changes to the resource list.
+	ListChanged *bool `json:"listChanged,omitempty"`
 }
 
 // Base interface for metadata with name (identifier) and title (display name) properties.
@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// RESOURCE_URI_SCHEME is the URI scheme used for resources served by Toolbox.
+	RESOURCE_URI_SCHEME = "toolbox"
+	// RESOURCE_MIME_TYPE is the MIME type of resource contents served by Toolbox.
+	RESOURCE_MIME_TYPE = "application/json"
+)
+
+// SchemaResourceURI returns the URI of a table or view, in the format of
+// `toolbox://<source>/<schema>/<table>`.
+func SchemaResourceURI(sourceName, schema, name string) string {
+	return fmt.Sprintf("%s://%s/%s/%s", RESOURCE_URI_SCHEME, url.PathEscape(sourceName), url.PathEscape(schema), url.PathEscape(name))
+}
+
+// SchemaResourceURITemplate returns the RFC 6570 URI template for the tables
+// and views of a source.
+func SchemaResourceURITemplate(sourceName string) string {
+	return fmt.Sprintf("%s://%s/{schema}/{table}", RESOURCE_URI_SCHEME, url.PathEscape(sourceName))
+}
+
+// ParseSchemaResourceURI parses a URI created by SchemaResourceURI into its
+// source, schema and table or view names.
+func ParseSchemaResourceURI(uri string) (string, string, string, error) {
+	prefix := RESOURCE_URI_SCHEME + "://"
+	if !strings.HasPrefix(uri, prefix) {
+		return "", "", "", fmt.Errorf("invalid resource uri %q: scheme must be %q", uri, RESOURCE_URI_SCHEME)
+	}
+	parts := strings.Split(strings.TrimPrefix(uri, prefix), "/")
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid resource uri %q: expected format %s://<source>/<schema>/<table>", uri, RESOURCE_URI_SCHEME)
+	}
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		n, err := url.PathUnescape(p)
+		if err != nil || n == "" {
+			return "", "", "", fmt.Errorf("invalid resource uri %q: malformed path segment %q", uri, p)
+		}
+		names[i] = n
+	}
+	return names[0], names[1], names[2], nil
+}
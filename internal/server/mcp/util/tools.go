@@ -0,0 +1,34 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// TOOLS_LIST is the tools/list method, which is shared by all supported
+// protocol versions.
+const TOOLS_LIST = "tools/list"
+
+// ToolsWithoutOutputSchema removes the output schema from tool manifests, for
+// protocol versions that do not support structured content.
+func ToolsWithoutOutputSchema(manifests []tools.McpManifest) []tools.McpManifest {
+	stripped := make([]tools.McpManifest, len(manifests))
+	for i, m := range manifests {
+		m.OutputSchema = nil
+		stripped[i] = m
+	}
+	return stripped
+}
@@ -20,24 +20,41 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
-// ProcessMethod returns a response for the request.
-func ProcessMethod(ctx context.Context, id jsonrpc.RequestId, method string, toolset tools.Toolset, tools map[string]tools.Tool, authServices map[string]auth.AuthService, body []byte, header http.Header) (any, error) {
+// ProcessMethod returns a response for the request. Resources are only served
+// for the schemaProviders that are available to the session.
+func ProcessMethod(ctx context.Context, id jsonrpc.RequestId, method string, toolset tools.Toolset, pageSize int, resourceMgr *resources.ResourceManager, schemaProviders map[string]sources.SchemaProvider, body []byte, header http.Header) (any, error) {
 	switch method {
 	case PING:
 		return pingHandler(id)
 	case TOOLS_LIST:
-		return toolsListHandler(id, toolset, body)
+		return toolsListHandler(id, toolset, pageSize, body)
 	case TOOLS_CALL:
-		return toolsCallHandler(ctx, id, tools, authServices, body, header)
+		return toolsCallHandler(ctx, id, resourceMgr.GetToolsMap(), resourceMgr.GetAuthServiceMap(), body, header)
+	case RESOURCES_LIST:
+		return resourcesListHandler(ctx, id, schemaProviders, body)
+	case RESOURCES_TEMPLATES_LIST:
+		return resourceTemplatesListHandler(id, schemaProviders, body)
+	case RESOURCES_READ:
+		return resourcesReadHandler(ctx, id, schemaProviders, body)
+	case PROMPTS_LIST:
+		return promptsListHandler(id, resourceMgr, body)
+	case PROMPTS_GET:
+		return promptsGetHandler(id, resourceMgr, body)
 	default:
 		err := fmt.Errorf("invalid method %s", method)
 		return jsonrpc.NewError(id, jsonrpc.METHOD_NOT_FOUND, err.Error(), nil), err
@@ -53,15 +70,22 @@ func pingHandler(id jsonrpc.RequestId) (any, error) {
 	}, nil
 }
 
-func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte) (any, error) {
+func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, pageSize int, body []byte) (any, error) {
 	var req ListToolsRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp tools list request: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	page, nextCursor, err := toolset.Paginate(string(req.Params.Cursor), pageSize)
+	if err != nil {
+		err = fmt.Errorf("invalid mcp tools list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
 	result := ListToolsResult{
-		Tools: toolset.McpManifest,
+		PaginatedResult: PaginatedResult{NextCursor: Cursor(nextCursor)},
+		Tools:           mcputil.ToolsWithoutOutputSchema(page.McpManifest),
 	}
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -150,17 +174,40 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 		err = fmt.Errorf("unauthorized Tool call: Please make sure your specify correct auth headers: %w", tools.ErrUnauthorized)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
+	// Check the claims against the authorization policies of the tool
+	if err = tools.AuthorizePolicies(toolName, tool, claimsFromAuth); err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
 	logger.DebugContext(ctx, "tool invocation authorized")
 
 	params, err := tool.ParseParams(data, claimsFromAuth)
+	if errors.Is(err, tools.ErrUnavailable) {
+		// the source of the tool failed to initialize
+		text := TextContent{
+			Type: "text",
+			Text: err.Error(),
+		}
+		return jsonrpc.JSONRPCResponse{
+			Jsonrpc: jsonrpc.JSONRPC_VERSION,
+			Id:      id,
+			Result:  CallToolResult{Content: []TextContent{text}, IsError: true},
+		}, nil
+	}
 	if err != nil {
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
 	}
 	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
+	format, err := tools.ParseResultFormat(req.Params.Meta.ResultFormat)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	ctx = tools.WithResultFormat(ctx, format)
+	ctx = tools.WithPageTokenCaller(ctx, claimsFromAuth)
+
 	// run tool invocation and generate response.
-	results, err := tool.Invoke(ctx, params, accessToken)
+	results, err := tools.InvokeWithTimeout(ctx, tool, params, accessToken)
 	if err != nil {
 		errStr := err.Error()
 		// Missing authService tokens.
@@ -190,8 +237,28 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 
 	content := make([]TextContent, 0)
 
-	sliceRes, ok := results.([]any)
-	if !ok {
+	// tools that do not encode their rows are encoded with sorted columns
+	results = tools.EncodeResult(results, format)
+
+	var sliceRes []any
+	switch r := results.(type) {
+	case []any:
+		sliceRes = r
+	case tools.TruncatedRows:
+		// the last block tells the client that rows were left out
+		sliceRes = r.Items()
+	case tools.PagedRows:
+		// the last block has the token of the next page, if any
+		sliceRes = r.Items()
+	case tools.EncodedRows:
+		// the rows are a single block, followed by their markers, if any
+		text, err := r.Text()
+		if err != nil {
+			return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+		}
+		content = append(content, TextContent{Type: "text", Text: text})
+		sliceRes = r.Markers()
+	default:
 		sliceRes = []any{results}
 	}
 
@@ -212,3 +279,176 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 		Result:  CallToolResult{Content: content},
 	}, nil
 }
+
+// resourcesListHandler lists the tables and views of every source that is able
+// to describe its schema and is available to the session.
+func resourcesListHandler(ctx context.Context, id jsonrpc.RequestId, providers map[string]sources.SchemaProvider, body []byte) (any, error) {
+	var req ListResourcesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	resourceList := make([]Resource, 0)
+	for _, sourceName := range slices.Sorted(maps.Keys(providers)) {
+		objects, err := providers[sourceName].ListSchemaObjects(ctx)
+		if err != nil {
+			err = fmt.Errorf("unable to list resources for source %q: %w", sourceName, err)
+			return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+		}
+		for _, o := range objects {
+			resourceList = append(resourceList, Resource{
+				URI:         mcputil.SchemaResourceURI(sourceName, o.Schema, o.Name),
+				Name:        fmt.Sprintf("%s.%s", o.Schema, o.Name),
+				Description: fmt.Sprintf("Schema of %s %q in source %q.", o.Type, o.Name, sourceName),
+				MimeType:    mcputil.RESOURCE_MIME_TYPE,
+			})
+		}
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ListResourcesResult{Resources: resourceList},
+	}, nil
+}
+
+// resourceTemplatesListHandler returns a resource template for every source
+// that is able to describe its schema and is available to the session.
+func resourceTemplatesListHandler(id jsonrpc.RequestId, providers map[string]sources.SchemaProvider, body []byte) (any, error) {
+	var req ListResourceTemplatesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resource templates list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	templates := make([]ResourceTemplate, 0, len(providers))
+	for _, sourceName := range slices.Sorted(maps.Keys(providers)) {
+		templates = append(templates, ResourceTemplate{
+			URITemplate: mcputil.SchemaResourceURITemplate(sourceName),
+			Name:        sourceName,
+			Description: fmt.Sprintf("Schema of a table or view in source %q.", sourceName),
+			MimeType:    mcputil.RESOURCE_MIME_TYPE,
+		})
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ListResourceTemplatesResult{ResourceTemplates: templates},
+	}, nil
+}
+
+// resourcesReadHandler returns the schema of the table or view identified by the resource URI.
+func resourcesReadHandler(ctx context.Context, id jsonrpc.RequestId, providers map[string]sources.SchemaProvider, body []byte) (any, error) {
+	var req ReadResourceRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources read request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	uri := req.Params.URI
+	sourceName, schema, name, err := mcputil.ParseSchemaResourceURI(uri)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	provider, ok := providers[sourceName]
+	if !ok {
+		err = fmt.Errorf("resource not found: source %q does not provide resources", sourceName)
+		return jsonrpc.NewError(id, RESOURCE_NOT_FOUND, err.Error(), map[string]any{"uri": uri}), err
+	}
+
+	desc, err := provider.DescribeSchemaObject(ctx, schema, name)
+	if err != nil {
+		if errors.Is(err, sources.ErrSchemaObjectNotFound) {
+			err = fmt.Errorf("resource not found: %w", err)
+			return jsonrpc.NewError(id, RESOURCE_NOT_FOUND, err.Error(), map[string]any{"uri": uri}), err
+		}
+		err = fmt.Errorf("unable to read resource %q: %w", uri, err)
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	text, err := json.Marshal(desc)
+	if err != nil {
+		err = fmt.Errorf("unable to marshal resource %q: %w", uri, err)
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result: ReadResourceResult{
+			Contents: []TextResourceContents{{
+				URI:      uri,
+				MimeType: mcputil.RESOURCE_MIME_TYPE,
+				Text:     string(text),
+			}},
+		},
+	}, nil
+}
+
+// promptsListHandler lists all the prompts configured for the server.
+func promptsListHandler(id jsonrpc.RequestId, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	var req ListPromptsRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp prompts list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	promptsMap := resourceMgr.GetPromptsMap()
+	promptList := make([]prompts.McpManifest, 0, len(promptsMap))
+	for _, name := range slices.Sorted(maps.Keys(promptsMap)) {
+		promptList = append(promptList, promptsMap[name].McpManifest())
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ListPromptsResult{Prompts: promptList},
+	}, nil
+}
+
+// promptsGetHandler renders a prompt with the arguments provided by the client.
+func promptsGetHandler(id jsonrpc.RequestId, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	var req GetPromptRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp prompts get request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	promptName := req.Params.Name
+	prompt, ok := resourceMgr.GetPrompt(promptName)
+	if !ok {
+		err := fmt.Errorf("invalid prompt name: prompt with name %q does not exist", promptName)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	args, err := prompt.ParseArgs(req.Params.Arguments)
+	if err != nil {
+		err = fmt.Errorf("provided arguments were invalid: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	rendered, err := prompt.Render(args)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	messages := make([]PromptMessage, 0, len(rendered))
+	for _, m := range rendered {
+		messages = append(messages, PromptMessage{
+			Role:    Role(m.Role),
+			Content: TextContent{Type: "text", Text: m.Text},
+		})
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result: GetPromptResult{
+			Description: prompt.Description,
+			Messages:    messages,
+		},
+	}, nil
+}
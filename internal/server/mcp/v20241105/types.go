@@ -15,6 +15,7 @@
 package v20241105
 
 import (
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
@@ -27,11 +28,19 @@ const PROTOCOL_VERSION = "2024-11-05"
 
 // methods that are supported.
 const (
-	PING       = "ping"
-	TOOLS_LIST = "tools/list"
-	TOOLS_CALL = "tools/call"
+	PING                     = "ping"
+	TOOLS_LIST               = "tools/list"
+	TOOLS_CALL               = "tools/call"
+	RESOURCES_LIST           = "resources/list"
+	RESOURCES_READ           = "resources/read"
+	RESOURCES_TEMPLATES_LIST = "resources/templates/list"
+	PROMPTS_LIST             = "prompts/list"
+	PROMPTS_GET              = "prompts/get"
 )
 
+// RESOURCE_NOT_FOUND is the error code returned when a requested resource does not exist.
+const RESOURCE_NOT_FOUND = -32002
+
 /* Empty result */
 
 // EmptyResult represents a response that indicates success but carries no data.
@@ -58,6 +67,122 @@ type PaginatedResult struct {
 	NextCursor Cursor `json:"nextCursor,omitempty"`
 }
 
+/* Resources */
+
+// Sent from the client to request a list of resources the server has.
+type ListResourcesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/list request from the client.
+type ListResourcesResult struct {
+	PaginatedResult
+	Resources []Resource `json:"resources"`
+}
+
+// Sent from the client to request a list of resource templates the server has.
+type ListResourceTemplatesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/templates/list request from the client.
+type ListResourceTemplatesResult struct {
+	PaginatedResult
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// Sent from the client to the server, to read a specific resource URI.
+type ReadResourceRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The URI of the resource to read. The URI can use any protocol; it is up
+		// to the server how to interpret it.
+		URI string `json:"uri"`
+	} `json:"params,omitempty"`
+}
+
+// The server's response to a resources/read request from the client.
+type ReadResourceResult struct {
+	jsonrpc.Result
+	Contents []TextResourceContents `json:"contents"`
+}
+
+// A known resource that the server is capable of reading.
+type Resource struct {
+	Annotated
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// A human-readable name for this resource.
+	Name string `json:"name"`
+	// A description of what this resource represents.
+	Description string `json:"description,omitempty"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// A template description for resources available on the server.
+type ResourceTemplate struct {
+	Annotated
+	// A URI template (according to RFC 6570) that can be used to construct
+	// resource URIs.
+	URITemplate string `json:"uriTemplate"`
+	// A human-readable name for the type of resource this template refers to.
+	Name string `json:"name"`
+	// A description of what this template is for.
+	Description string `json:"description,omitempty"`
+	// The MIME type for all resources that match this template.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// The contents of a specific resource, represented as text.
+type TextResourceContents struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+	// The text of the item.
+	Text string `json:"text"`
+}
+
+/* Prompts */
+
+// Sent from the client to request a list of prompts and prompt templates the server has.
+type ListPromptsRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a prompts/list request from the client.
+type ListPromptsResult struct {
+	PaginatedResult
+	Prompts []prompts.McpManifest `json:"prompts"`
+}
+
+// Used by the client to get a prompt provided by the server.
+type GetPromptRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The name of the prompt or prompt template.
+		Name string `json:"name"`
+		// Arguments to use for templating the prompt.
+		Arguments map[string]string `json:"arguments,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+// The server's response to a prompts/get request from the client.
+type GetPromptResult struct {
+	jsonrpc.Result
+	// An optional description for the prompt.
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// Describes a message returned as part of a prompt.
+type PromptMessage struct {
+	Role Role `json:"role"`
+	// For Toolbox, we will only be sending TextContent
+	Content TextContent `json:"content"`
+}
+
 /* Tools */
 
 // Sent from the client to request a list of tools the server has.
@@ -77,6 +202,11 @@ type CallToolRequest struct {
 	Params struct {
 		Name      string         `json:"name"`
 		Arguments map[string]any `json:"arguments,omitempty"`
+		Meta      struct {
+			// ResultFormat overrides the result format of the tool, e.g.
+			// "csv".
+			ResultFormat string `json:"resultFormat,omitempty"`
+		} `json:"_meta,omitempty"`
 	} `json:"params,omitempty"`
 }
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -28,9 +29,13 @@ import (
 	"testing"
 
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 const jsonrpcVersion = "2.0"
@@ -359,7 +364,7 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2024-11-05",
 					"capabilities": map[string]any{
-						"tools": map[string]any{"listChanged": false},
+						"tools": map[string]any{"listChanged": true},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -375,7 +380,7 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2025-03-26",
 					"capabilities": map[string]any{
-						"tools": map[string]any{"listChanged": false},
+						"tools": map[string]any{"listChanged": true},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -391,7 +396,7 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2025-06-18",
 					"capabilities": map[string]any{
-						"tools": map[string]any{"listChanged": false},
+						"tools": map[string]any{"listChanged": true},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -955,7 +960,7 @@ func TestStdioSession(t *testing.T) {
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := NewResourceManager(nil, nil, toolsMap, toolsets)
+	resourceManager := resources.NewResourceManager(nil, nil, toolsMap, toolsets, nil)
 
 	server := &Server{
 		version:         fakeVersionString,
@@ -999,3 +1004,989 @@ func TestStdioSession(t *testing.T) {
 		t.Fatalf("unexpected read: got %s, want %s", read, want)
 	}
 }
+
+var _ sources.SchemaProvider = &mockSchemaSource{}
+
+// mockSchemaSource is used to mock a source that is able to describe its schema
+type mockSchemaSource struct {
+	objects []sources.SchemaObject
+}
+
+func (s *mockSchemaSource) SourceKind() string {
+	return "mock-schema"
+}
+
+func (s *mockSchemaSource) ListSchemaObjects(context.Context) ([]sources.SchemaObject, error) {
+	return s.objects, nil
+}
+
+func (s *mockSchemaSource) DescribeSchemaObject(_ context.Context, schema, name string) (sources.SchemaDescription, error) {
+	for _, o := range s.objects {
+		if o.Schema == schema && o.Name == name {
+			columns := []sources.SchemaColumn{{Name: "id", DataType: "integer", Nullable: false}}
+			return sources.SchemaDescription{SchemaObject: o, Columns: columns}, nil
+		}
+	}
+	return sources.SchemaDescription{}, sources.ErrSchemaObjectNotFound
+}
+
+func TestMcpResources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// resources are scoped to the sources of the tools that the caller is
+	// able to invoke
+	mockTools := []MockTool{tool1, tool4}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox")
+	if err != nil {
+		t.Fatalf("unable to setup otel: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("error shutting down OpenTelemetry: %s", err)
+		}
+	}()
+
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+
+	sourcesMap := map[string]sources.Source{
+		"my-db": &mockSchemaSource{objects: []sources.SchemaObject{
+			{Schema: "public", Name: "users", Type: "table"},
+			{Schema: "public", Name: "active_users", Type: "view"},
+		}},
+		"secret-db": &mockSchemaSource{objects: []sources.SchemaObject{
+			{Schema: "public", Name: "tokens", Type: "table"},
+		}},
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(sourcesMap, nil, toolsMap, toolsets, nil),
+	}
+	server.loaded.Store(&loadedResources{cfg: ServerConfig{ToolConfigs: ToolConfigs{
+		tool1.Name: mockToolConfig{Name: tool1.Name, Source: "my-db"},
+		tool4.Name: mockToolConfig{Name: tool4.Name, Source: "secret-db"},
+	}}})
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities": map[string]any{
+				"resources": map[string]any{"listChanged": false},
+				"tools":     map[string]any{"listChanged": true},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	runInitializeLifecycle(t, ts, protocolVersion20250618, initWant, false)
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+
+	testCases := []struct {
+		name string
+		body jsonrpc.JSONRPCRequest
+		want map[string]any
+	}{
+		{
+			name: "resources/list",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "resources-list",
+				Request: jsonrpc.Request{Method: "resources/list"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-list",
+				"result": map[string]any{
+					"resources": []any{
+						map[string]any{
+							"uri":         "toolbox://my-db/public/users",
+							"name":        "public.users",
+							"description": `Schema of table "users" in source "my-db".`,
+							"mimeType":    "application/json",
+						},
+						map[string]any{
+							"uri":         "toolbox://my-db/public/active_users",
+							"name":        "public.active_users",
+							"description": `Schema of view "active_users" in source "my-db".`,
+							"mimeType":    "application/json",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resources/templates/list",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "resources-templates-list",
+				Request: jsonrpc.Request{Method: "resources/templates/list"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-templates-list",
+				"result": map[string]any{
+					"resourceTemplates": []any{
+						map[string]any{
+							"uriTemplate": "toolbox://my-db/{schema}/{table}",
+							"name":        "my-db",
+							"description": `Schema of a table or view in source "my-db".`,
+							"mimeType":    "application/json",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resources/read",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "resources-read",
+				Request: jsonrpc.Request{Method: "resources/read"},
+				Params:  map[string]any{"uri": "toolbox://my-db/public/users"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read",
+				"result": map[string]any{
+					"contents": []any{
+						map[string]any{
+							"uri":      "toolbox://my-db/public/users",
+							"mimeType": "application/json",
+							"text":     `{"schema":"public","name":"users","type":"table","columns":[{"name":"id","dataType":"integer","nullable":false}]}`,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resources/read unknown table",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "resources-read-unknown",
+				Request: jsonrpc.Request{Method: "resources/read"},
+				Params:  map[string]any{"uri": "toolbox://my-db/public/orders"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read-unknown",
+				"error": map[string]any{
+					"code":    -32002.0,
+					"message": "resource not found: table or view not found",
+					"data":    map[string]any{"uri": "toolbox://my-db/public/orders"},
+				},
+			},
+		},
+		{
+			name: "resources/read of a source without authorized tools",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "resources-read-unauthorized",
+				Request: jsonrpc.Request{Method: "resources/read"},
+				Params:  map[string]any{"uri": "toolbox://secret-db/public/tokens"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read-unauthorized",
+				"error": map[string]any{
+					"code":    -32002.0,
+					"message": `resource not found: source "secret-db" does not provide resources`,
+					"data":    map[string]any{"uri": "toolbox://secret-db/public/tokens"},
+				},
+			},
+		},
+		{
+			name: "resources/read invalid uri",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "resources-read-invalid",
+				Request: jsonrpc.Request{Method: "resources/read"},
+				Params:  map[string]any{"uri": "file:///etc/passwd"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read-invalid",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `invalid resource uri "file:///etc/passwd": scheme must be "toolbox"`,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(tc.body)
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	// the toolset only has a tool that the caller isn't able to invoke
+	reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "resources-templates-list",
+		Request: jsonrpc.Request{Method: "resources/templates/list"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/tool2_only", bytes.NewBuffer(reqMarshal), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if want := `{"jsonrpc":"2.0","id":"resources-templates-list","result":{"resourceTemplates":[]}}`; strings.TrimSpace(string(body)) != want {
+		t.Fatalf("unexpected response: got %s, want %s", body, want)
+	}
+}
+
+func TestMcpPrompts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox")
+	if err != nil {
+		t.Fatalf("unable to setup otel: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("error shutting down OpenTelemetry: %s", err)
+		}
+	}()
+
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+
+	prompt, err := prompts.Config{
+		Name:        "summarize_table",
+		Description: "Summarize the contents of a table.",
+		Arguments: tools.Parameters{
+			tools.NewStringParameter("table", "name of the table"),
+			tools.NewIntParameterWithDefault("limit", 10, "number of rows to look at"),
+		},
+		Messages: []prompts.MessageConfig{
+			{Content: "Summarize the first {{.limit}} rows of {{.table}}."},
+			{Role: "assistant", Content: "Sure, let me look at {{.table}}."},
+		},
+	}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+	promptsMap := map[string]prompts.Prompt{prompt.Name: prompt}
+
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(nil, nil, toolsMap, toolsets, promptsMap),
+	}
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities": map[string]any{
+				"prompts": map[string]any{"listChanged": false},
+				"tools":   map[string]any{"listChanged": true},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	runInitializeLifecycle(t, ts, protocolVersion20250618, initWant, false)
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+
+	testCases := []struct {
+		name string
+		body jsonrpc.JSONRPCRequest
+		want map[string]any
+	}{
+		{
+			name: "prompts/list",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "prompts-list",
+				Request: jsonrpc.Request{Method: "prompts/list"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-list",
+				"result": map[string]any{
+					"prompts": []any{
+						map[string]any{
+							"name":        "summarize_table",
+							"description": "Summarize the contents of a table.",
+							"arguments": []any{
+								map[string]any{"name": "table", "description": "name of the table", "required": true},
+								map[string]any{"name": "limit", "description": "number of rows to look at"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "prompts/get",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "prompts-get",
+				Request: jsonrpc.Request{Method: "prompts/get"},
+				Params: map[string]any{
+					"name":      "summarize_table",
+					"arguments": map[string]any{"table": "users", "limit": "5"},
+				},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-get",
+				"result": map[string]any{
+					"description": "Summarize the contents of a table.",
+					"messages": []any{
+						map[string]any{
+							"role":    "user",
+							"content": map[string]any{"type": "text", "text": "Summarize the first 5 rows of users."},
+						},
+						map[string]any{
+							"role":    "assistant",
+							"content": map[string]any{"type": "text", "text": "Sure, let me look at users."},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "prompts/get with default argument",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "prompts-get-default",
+				Request: jsonrpc.Request{Method: "prompts/get"},
+				Params: map[string]any{
+					"name":      "summarize_table",
+					"arguments": map[string]any{"table": "orders"},
+				},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-get-default",
+				"result": map[string]any{
+					"description": "Summarize the contents of a table.",
+					"messages": []any{
+						map[string]any{
+							"role":    "user",
+							"content": map[string]any{"type": "text", "text": "Summarize the first 10 rows of orders."},
+						},
+						map[string]any{
+							"role":    "assistant",
+							"content": map[string]any{"type": "text", "text": "Sure, let me look at orders."},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "prompts/get missing argument",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "prompts-get-missing",
+				Request: jsonrpc.Request{Method: "prompts/get"},
+				Params:  map[string]any{"name": "summarize_table"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-get-missing",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `provided arguments were invalid: parameter "table" is required`,
+				},
+			},
+		},
+		{
+			name: "prompts/get unknown prompt",
+			body: jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "prompts-get-unknown",
+				Request: jsonrpc.Request{Method: "prompts/get"},
+				Params:  map[string]any{"name": "foo"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-get-unknown",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `invalid prompt name: prompt with name "foo" does not exist`,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(tc.body)
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+var _ tools.Tool = &slowTool{}
+
+// slowTool is used to mock a long-running tool, that reports its progress and
+// runs until the request is cancelled
+type slowTool struct {
+	MockTool
+}
+
+func (t slowTool) Invoke(ctx context.Context, _ tools.ParamValues, _ tools.AccessToken) (any, error) {
+	util.ReportProgress(ctx, 1, 2, "started")
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStdioCancellationAndProgress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tool := slowTool{MockTool{Name: "slow_tool", Params: []tools.Parameter{}}}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	toolset, err := tools.ToolsetConfig{Name: "", ToolNames: []string{tool.Name}}.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	toolsets := map[string]tools.Toolset{"": toolset}
+
+	testLogger, err := log.NewStdLogger(os.Stderr, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(nil, nil, toolsMap, toolsets, nil),
+	}
+
+	ctx = util.WithLogger(ctx, testLogger)
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	stdioSession := NewStdioSession(server, inR, outW)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- stdioSession.Start(ctx)
+		outW.Close()
+	}()
+	out := bufio.NewReader(outR)
+
+	send := func(msg map[string]any) {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of message: %s", err)
+		}
+		if _, err := fmt.Fprintf(inW, "%s\n", b); err != nil {
+			t.Fatalf("error writing to stdin: %s", err)
+		}
+	}
+	receive := func() map[string]any {
+		line, err := out.ReadString('\n')
+		if err != nil {
+			t.Fatalf("error reading from stdout: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling line %q: %s", line, err)
+		}
+		return got
+	}
+
+	send(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "mcp-initialize",
+		"method":  "initialize",
+		"params":  map[string]any{"protocolVersion": protocolVersion20250618},
+	})
+	if got := receive(); got["id"] != "mcp-initialize" {
+		t.Fatalf("unexpected initialize response: %+v", got)
+	}
+	send(map[string]any{"jsonrpc": jsonrpcVersion, "method": "notifications/initialized"})
+
+	send(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      2,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "slow_tool",
+			"arguments": map[string]any{},
+			"_meta":     map[string]any{"progressToken": "my-token"},
+		},
+	})
+	wantProgress := map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "notifications/progress",
+		"params": map[string]any{
+			"progressToken": "my-token",
+			"progress":      1.0,
+			"total":         2.0,
+			"message":       "started",
+		},
+	}
+	if got := receive(); !reflect.DeepEqual(got, wantProgress) {
+		t.Fatalf("unexpected progress notification: got %+v, want %+v", got, wantProgress)
+	}
+
+	send(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"method":  "notifications/cancelled",
+		"params":  map[string]any{"requestId": 2, "reason": "user cancelled"},
+	})
+	// the cancelled request must not get a response, so the next message
+	// received is the response to tools/list
+	send(map[string]any{"jsonrpc": jsonrpcVersion, "id": 3, "method": "tools/list"})
+	if got := receive(); got["id"] != 3.0 {
+		t.Fatalf("unexpected response: got %+v, want response to request 3", got)
+	}
+
+	inW.Close()
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected error from stdio session: %s", err)
+	}
+	if rest, _ := io.ReadAll(out); len(rest) != 0 {
+		t.Fatalf("unexpected output after cancellation: %s", rest)
+	}
+}
+
+func TestMcpToolsListPagination(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		pageSize:        2,
+		ResourceMgr:     resources.NewResourceManager(nil, nil, toolsMap, toolsets, nil),
+	}
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+	listTools := func(cursor string) map[string]any {
+		req := jsonrpc.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "tools-list",
+			Request: jsonrpc.Request{Method: "tools/list"},
+		}
+		if cursor != "" {
+			req.Params = map[string]any{"cursor": cursor}
+		}
+		reqMarshal, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got
+	}
+
+	var gotNames []string
+	cursor := ""
+	pages := 0
+	for {
+		got := listTools(cursor)
+		result, ok := got["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected response: %+v", got)
+		}
+		toolList, _ := result["tools"].([]any)
+		if len(toolList) > server.pageSize {
+			t.Fatalf("page has %d tools, want at most %d", len(toolList), server.pageSize)
+		}
+		for _, tool := range toolList {
+			gotNames = append(gotNames, tool.(map[string]any)["name"].(string))
+		}
+		pages++
+		next, ok := result["nextCursor"].(string)
+		if !ok {
+			break
+		}
+		cursor = next
+	}
+	wantNames := []string{tool1.Name, tool2.Name, tool3.Name}
+	if pages != 2 || !reflect.DeepEqual(gotNames, wantNames) {
+		t.Fatalf("unexpected pages: got %d pages with %v, want 2 pages with %v", pages, gotNames, wantNames)
+	}
+
+	got := listTools("invalid cursor")
+	wantErr := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "tools-list",
+		"error": map[string]any{
+			"code":    -32602.0,
+			"message": "invalid mcp tools list request: invalid cursor",
+		},
+	}
+	if !reflect.DeepEqual(got, wantErr) {
+		t.Fatalf("unexpected response: got %+v, want %+v", got, wantErr)
+	}
+}
+
+var _ tools.Tool = &rowsTool{}
+
+// rowsTool is used to mock a tool that returns rows and declares an output schema
+type rowsTool struct {
+	MockTool
+	truncated bool
+}
+
+func (t rowsTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	rows := []any{
+		map[string]any{"id": 1, "name": "Hilton"},
+		map[string]any{"id": 2, "name": nil},
+	}
+	if t.truncated {
+		return tools.TruncatedRows{Rows: rows, Truncated: true, RowCount: len(rows)}, nil
+	}
+	return rows, nil
+}
+
+func (t rowsTool) McpManifest() tools.McpManifest {
+	m := t.MockTool.McpManifest()
+	m.OutputSchema, _ = tools.GetRowsOutputSchema(tools.Parameters{
+		tools.NewIntParameter("id", "hotel id"),
+		tools.NewStringParameter("name", "hotel name"),
+	})
+	return m
+}
+
+func TestMcpStructuredContent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tool := rowsTool{MockTool: MockTool{Name: "rows_tool", Params: []tools.Parameter{}}}
+	truncatedTool := rowsTool{MockTool: MockTool{Name: "truncated_rows_tool", Params: []tools.Parameter{}}, truncated: true}
+	toolsMap := map[string]tools.Tool{tool.Name: tool, truncatedTool.Name: truncatedTool}
+	toolset, err := tools.ToolsetConfig{Name: "", ToolNames: []string{tool.Name, truncatedTool.Name}}.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	toolsets := map[string]tools.Toolset{"": toolset}
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(nil, nil, toolsMap, toolsets, nil),
+	}
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	send := func(header map[string]string, body jsonrpc.JSONRPCRequest) map[string]any {
+		reqMarshal, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(respBody, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		result, ok := got["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected response: %+v", got)
+		}
+		return result
+	}
+	listReq := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-list",
+		Request: jsonrpc.Request{Method: "tools/list"},
+	}
+	callReq := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-call",
+		Request: jsonrpc.Request{Method: "tools/call"},
+		Params:  map[string]any{"name": "rows_tool"},
+	}
+	header20250618 := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+
+	t.Run("output schema is listed for 2025-06-18", func(t *testing.T) {
+		result := send(header20250618, listReq)
+		got := result["tools"].([]any)[0].(map[string]any)["outputSchema"]
+		want := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"rows": map[string]any{
+					"type":        "array",
+					"description": "Rows returned by the tool. Columns with a NULL value are omitted.",
+					"items": map[string]any{
+						"type":        "object",
+						"description": "",
+						"properties": map[string]any{
+							"id":   map[string]any{"type": "integer", "description": "hotel id"},
+							"name": map[string]any{"type": "string", "description": "hotel name"},
+						},
+					},
+				},
+				"truncated": map[string]any{
+					"type":        "boolean",
+					"description": "Whether rows were left out because they exceeded the result limits.",
+				},
+				"rowCount": map[string]any{
+					"type":        "integer",
+					"description": "Number of rows returned, if rows were left out.",
+				},
+				"nextPageToken": map[string]any{
+					"type":        "string",
+					"description": "Token to pass as the pageToken parameter to get the next page of rows, if there is one.",
+				},
+			},
+			"required": []any{"rows"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected output schema: got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("output schema is not listed for 2024-11-05", func(t *testing.T) {
+		result := send(nil, listReq)
+		if _, ok := result["tools"].([]any)[0].(map[string]any)["outputSchema"]; ok {
+			t.Fatalf("unexpected output schema for 2024-11-05: %+v", result)
+		}
+	})
+
+	t.Run("structured content for 2025-06-18", func(t *testing.T) {
+		result := send(header20250618, callReq)
+		want := map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": `{"id":1,"name":"Hilton"}`},
+				map[string]any{"type": "text", "text": `{"id":2,"name":null}`},
+			},
+			"structuredContent": map[string]any{
+				"rows": []any{
+					map[string]any{"id": 1.0, "name": "Hilton"},
+					map[string]any{"id": 2.0},
+				},
+			},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Fatalf("unexpected result: got %+v, want %+v", result, want)
+		}
+	})
+
+	t.Run("no structured content for 2024-11-05", func(t *testing.T) {
+		result := send(nil, callReq)
+		if _, ok := result["structuredContent"]; ok {
+			t.Fatalf("unexpected structured content for 2024-11-05: %+v", result)
+		}
+	})
+
+	truncatedReq := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-call-truncated",
+		Request: jsonrpc.Request{Method: "tools/call"},
+		Params:  map[string]any{"name": "truncated_rows_tool"},
+	}
+
+	t.Run("truncated structured content for 2025-06-18", func(t *testing.T) {
+		result := send(header20250618, truncatedReq)
+		want := map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": `{"id":1,"name":"Hilton"}`},
+				map[string]any{"type": "text", "text": `{"id":2,"name":null}`},
+				map[string]any{"type": "text", "text": `{"rowCount":2,"truncated":true}`},
+			},
+			"structuredContent": map[string]any{
+				"rows": []any{
+					map[string]any{"id": 1.0, "name": "Hilton"},
+					map[string]any{"id": 2.0},
+				},
+				"truncated": true,
+				"rowCount":  2.0,
+			},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Fatalf("unexpected result: got %+v, want %+v", result, want)
+		}
+	})
+
+	t.Run("truncated content for 2024-11-05", func(t *testing.T) {
+		result := send(nil, truncatedReq)
+		want := map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": `{"id":1,"name":"Hilton"}`},
+				map[string]any{"type": "text", "text": `{"id":2,"name":null}`},
+				map[string]any{"type": "text", "text": `{"rowCount":2,"truncated":true}`},
+			},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Fatalf("unexpected result: got %+v, want %+v", result, want)
+		}
+	})
+
+	csvReq := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-call-csv",
+		Request: jsonrpc.Request{Method: "tools/call"},
+		Params: map[string]any{
+			"name":  "truncated_rows_tool",
+			"_meta": map[string]any{"resultFormat": "csv"},
+		},
+	}
+
+	t.Run("csv content for 2024-11-05", func(t *testing.T) {
+		result := send(nil, csvReq)
+		want := map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": "id,name\n1,Hilton\n2,\n"},
+				map[string]any{"type": "text", "text": `{"rowCount":2,"truncated":true}`},
+			},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Fatalf("unexpected result: got %+v, want %+v", result, want)
+		}
+	})
+
+	t.Run("csv content with structured content for 2025-06-18", func(t *testing.T) {
+		result := send(header20250618, csvReq)
+		want := map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": "id,name\n1,Hilton\n2,\n"},
+				map[string]any{"type": "text", "text": `{"rowCount":2,"truncated":true}`},
+			},
+			"structuredContent": map[string]any{
+				"rows": []any{
+					map[string]any{"id": 1.0, "name": "Hilton"},
+					map[string]any{"id": 2.0},
+				},
+				"truncated": true,
+				"rowCount":  2.0,
+			},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Fatalf("unexpected result: got %+v, want %+v", result, want)
+		}
+	})
+
+	t.Run("invalid result format", func(t *testing.T) {
+		reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "tools-call-xml",
+			Request: jsonrpc.Request{Method: "tools/call"},
+			Params: map[string]any{
+				"name":  "rows_tool",
+				"_meta": map[string]any{"resultFormat": "xml"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if !strings.Contains(string(respBody), `invalid result format \"xml\"`) {
+			t.Fatalf("unexpected response: %s", string(respBody))
+		}
+	})
+}
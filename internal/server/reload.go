@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// loadedResources are the resources initialized from a ServerConfig, along
+// with the configs they were initialized from, so that reloads can reuse the
+// resources whose config didn't change.
+type loadedResources struct {
+	cfg          ServerConfig
+	sources      map[string]sources.Source
+	authServices map[string]auth.AuthService
+	// baseTools are the tools before the policies of their toolsets are applied
+	baseTools map[string]tools.Tool
+	tools     map[string]tools.Tool
+	toolsets  map[string]tools.Toolset
+	prompts   map[string]prompts.Prompt
+	// unavailableSources are the optional sources that failed to initialize
+	unavailableSources map[string]error
+}
+
+// ResourceChanges lists the names of the resources of a kind that were
+// added, changed or removed by a reload.
+type ResourceChanges struct {
+	Added   []string `json:"added,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// touched reports if the resource was added or changed.
+func (c ResourceChanges) touched(name string) bool {
+	return slices.Contains(c.Added, name) || slices.Contains(c.Changed, name)
+}
+
+// without returns the changes without the given resource.
+func (c ResourceChanges) without(name string) ResourceChanges {
+	del := func(names []string) []string {
+		names = slices.DeleteFunc(names, func(n string) bool { return n == name })
+		if len(names) == 0 {
+			return nil
+		}
+		return names
+	}
+	return ResourceChanges{Added: del(c.Added), Changed: del(c.Changed), Removed: del(c.Removed)}
+}
+
+func (c ResourceChanges) empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+func (c ResourceChanges) String() string {
+	var parts []string
+	for _, p := range []struct {
+		change string
+		names  []string
+	}{{"added", c.Added}, {"changed", c.Changed}, {"removed", c.Removed}} {
+		if len(p.names) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %v", p.change, p.names))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ReloadReport describes the resources that were added, changed or removed
+// by a reload. A tool is also reported as changed if its source changed, and
+// a toolset if any of its tools changed.
+type ReloadReport struct {
+	Sources      ResourceChanges `json:"sources"`
+	AuthServices ResourceChanges `json:"authServices"`
+	Tools        ResourceChanges `json:"tools"`
+	Toolsets     ResourceChanges `json:"toolsets"`
+	Prompts      ResourceChanges `json:"prompts"`
+}
+
+// kinds returns the changes of the report, keyed by resource kind.
+func (r ReloadReport) kinds() []struct {
+	kind    string
+	changes ResourceChanges
+} {
+	return []struct {
+		kind    string
+		changes ResourceChanges
+	}{
+		{"sources", r.Sources},
+		{"authServices", r.AuthServices},
+		{"tools", r.Tools},
+		{"toolsets", r.Toolsets},
+		{"prompts", r.Prompts},
+	}
+}
+
+func (r ReloadReport) String() string {
+	var parts []string
+	for _, k := range r.kinds() {
+		if !k.changes.empty() {
+			parts = append(parts, fmt.Sprintf("%s: %s", k.kind, k.changes))
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// diffConfigs compares two sets of configs by name and content.
+func diffConfigs[M ~map[string]V, V any](prev, next M) ResourceChanges {
+	var c ResourceChanges
+	for name, cfg := range next {
+		old, ok := prev[name]
+		switch {
+		case !ok:
+			c.Added = append(c.Added, name)
+		case !reflect.DeepEqual(old, cfg):
+			c.Changed = append(c.Changed, name)
+		}
+	}
+	for name := range prev {
+		if _, ok := next[name]; !ok {
+			c.Removed = append(c.Removed, name)
+		}
+	}
+	slices.Sort(c.Added)
+	slices.Sort(c.Changed)
+	slices.Sort(c.Removed)
+	return c
+}
+
+// toolSource returns the name of the source used by a tool config. It returns
+// false if the tool doesn't use a source.
+func toolSource(tc tools.ToolConfig) (string, bool) {
+	if pc, ok := tc.(tools.PolicyConfig); ok {
+		return toolSource(pc.ToolConfig)
+	}
+	sc, ok := tc.(tools.SourceToolConfig)
+	if !ok {
+		return "", false
+	}
+	return sc.ToolConfigInfo().Source, true
+}
+
+// Reload replaces the resources of the server with the resources of cfg.
+// Resources whose config didn't change are reused, so that only the sources
+// that were added or changed are connected to again. The resources of the
+// server are left untouched if any resource fails to initialize. Clients of
+// the live MCP sessions are notified that the list of tools has changed.
+func (s *Server) Reload(ctx context.Context, cfg ServerConfig) (ReloadReport, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/reload")
+	defer span.End()
+
+	refreshCtx := context.WithoutCancel(ctx)
+	loaded, report, err := initializeResources(ctx, cfg, s.loaded.Load(), func() { s.refreshToolsets(refreshCtx) })
+	status := "success"
+	if err != nil {
+		status = "error"
+		span.SetStatus(codes.Error, err.Error())
+	}
+	s.instrumentation.Reload.Add(
+		ctx,
+		1,
+		metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+	)
+	if err != nil {
+		return ReloadReport{}, fmt.Errorf("unable to initialize reloaded configs: %w", err)
+	}
+
+	s.ResourceMgr.SetResources(ctx, loaded.sources, loaded.authServices, loaded.tools, loaded.toolsets, loaded.prompts)
+	s.loaded.Store(loaded)
+	s.notifyToolsListChanged(ctx)
+
+	for _, k := range report.kinds() {
+		for change, names := range map[string][]string{
+			"added":   k.changes.Added,
+			"changed": k.changes.Changed,
+			"removed": k.changes.Removed,
+		} {
+			if len(names) == 0 {
+				continue
+			}
+			s.instrumentation.ReloadChanges.Add(
+				ctx,
+				int64(len(names)),
+				metric.WithAttributes(attribute.String("toolbox.resource.kind", k.kind)),
+				metric.WithAttributes(attribute.String("toolbox.reload.change", change)),
+			)
+			span.SetAttributes(attribute.StringSlice(fmt.Sprintf("%s.%s", k.kind, change), names))
+		}
+	}
+	s.logger.InfoContext(ctx, "Reloaded tools file.", "report", report)
+	return report, nil
+}
@@ -0,0 +1,389 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// initialized records the names of the sources and tools initialized by the
+// mock configs below.
+var (
+	initialized   []string
+	initializedMu sync.Mutex
+)
+
+// recordInitialized records that a source or tool was initialized. Sources
+// are initialized concurrently.
+func recordInitialized(name string) {
+	initializedMu.Lock()
+	defer initializedMu.Unlock()
+	initialized = append(initialized, name)
+}
+
+// mockSourceConfig is used to mock source configs in reload tests
+type mockSourceConfig struct {
+	Name string
+	Conn string
+	Fail bool
+}
+
+func (c mockSourceConfig) SourceConfigKind() string {
+	return "mock"
+}
+
+func (c mockSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	if c.Fail {
+		return nil, fmt.Errorf("unable to connect to %q", c.Conn)
+	}
+	recordInitialized("source/" + c.Name)
+	return &mockSource{}, nil
+}
+
+// mockSource records when it is closed
+type mockSource struct {
+	closed atomic.Bool
+}
+
+func (s *mockSource) SourceKind() string {
+	return "mock"
+}
+
+func (s *mockSource) Close(context.Context) error {
+	s.closed.Store(true)
+	return nil
+}
+
+// mockToolConfig is used to mock tool configs in reload tests
+type mockToolConfig struct {
+	Name        string
+	Source      string
+	Description string
+}
+
+func (c mockToolConfig) ToolConfigKind() string {
+	return "mock"
+}
+
+func (c mockToolConfig) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{Source: c.Source, Description: c.Description}
+}
+
+func (c mockToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	if _, ok := srcs[c.Source]; !ok {
+		return nil, fmt.Errorf("no source named %q configured", c.Source)
+	}
+	recordInitialized("tool/" + c.Name)
+	return MockTool{Name: c.Name, Description: c.Description, Params: []tools.Parameter{}}, nil
+}
+
+func TestReload(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unable to set up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := ServerConfig{
+		Version: fakeVersionString,
+		Address: "127.0.0.1",
+		Port:    5000,
+		SourceConfigs: SourceConfigs{
+			"s1": mockSourceConfig{Name: "s1", Conn: "a"},
+			"s2": mockSourceConfig{Name: "s2", Conn: "b"},
+		},
+		ToolConfigs: ToolConfigs{
+			"t1": mockToolConfig{Name: "t1", Source: "s1"},
+			"t2": mockToolConfig{Name: "t2", Source: "s2"},
+		},
+		ToolsetConfigs: ToolsetConfigs{
+			"ts1": tools.ToolsetConfig{Name: "ts1", ToolNames: []string{"t1"}},
+			"ts2": tools.ToolsetConfig{Name: "ts2", ToolNames: []string{"t2"}},
+		},
+	}
+	initialized = nil
+	s, err := NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+	if _, ok := cfg.ToolsetConfigs[""]; ok {
+		t.Fatalf("the default toolset was added to the configs of the caller")
+	}
+	s1, _ := s.ResourceMgr.GetSource("s1")
+	s2, _ := s.ResourceMgr.GetSource("s2")
+
+	// change a source and add a tool
+	initialized = nil
+	cfg.SourceConfigs = SourceConfigs{
+		"s1": mockSourceConfig{Name: "s1", Conn: "a"},
+		"s2": mockSourceConfig{Name: "s2", Conn: "c"},
+	}
+	cfg.ToolConfigs = ToolConfigs{
+		"t1": mockToolConfig{Name: "t1", Source: "s1"},
+		"t2": mockToolConfig{Name: "t2", Source: "s2"},
+		"t3": mockToolConfig{Name: "t3", Source: "s1"},
+	}
+	report, err := s.Reload(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := ReloadReport{
+		Sources:  ResourceChanges{Changed: []string{"s2"}},
+		Tools:    ResourceChanges{Added: []string{"t3"}, Changed: []string{"t2"}},
+		Toolsets: ResourceChanges{Changed: []string{"ts2"}},
+	}
+	if diff := cmp.Diff(want, report); diff != "" {
+		t.Fatalf("unexpected report (-want +got):\n%s", diff)
+	}
+	slices.Sort(initialized)
+	if diff := cmp.Diff([]string{"source/s2", "tool/t2", "tool/t3"}, initialized); diff != "" {
+		t.Fatalf("unexpected initialized resources (-want +got):\n%s", diff)
+	}
+	if got, _ := s.ResourceMgr.GetSource("s1"); got != s1 {
+		t.Fatalf("unchanged source was not reused")
+	}
+	waitFor(t, s2.(*mockSource).closed.Load)
+	if s1.(*mockSource).closed.Load() {
+		t.Fatalf("unchanged source was closed")
+	}
+	if toolset, _ := s.ResourceMgr.GetToolset(""); len(toolset.McpManifest) != 3 {
+		t.Fatalf("default toolset was not updated: %+v", toolset.McpManifest)
+	}
+
+	// a failed reload leaves the resources untouched
+	initialized = nil
+	failedCfg := cfg
+	failedCfg.SourceConfigs = SourceConfigs{
+		"s1": mockSourceConfig{Name: "s1", Conn: "a"},
+		"s2": mockSourceConfig{Name: "s2", Conn: "c"},
+		"s3": mockSourceConfig{Name: "s3", Conn: "d"},
+		"s4": mockSourceConfig{Name: "s4", Conn: "e", Fail: true},
+	}
+	if _, err := s.Reload(ctx, failedCfg); err == nil {
+		t.Fatalf("expected reload to fail")
+	}
+	if _, ok := s.ResourceMgr.GetSource("s3"); ok {
+		t.Fatalf("resources of a failed reload were applied")
+	}
+
+	// remove a source and its tool
+	initialized = nil
+	cfg.SourceConfigs = SourceConfigs{
+		"s1": mockSourceConfig{Name: "s1", Conn: "a"},
+	}
+	cfg.ToolConfigs = ToolConfigs{
+		"t1": mockToolConfig{Name: "t1", Source: "s1"},
+		"t3": mockToolConfig{Name: "t3", Source: "s1", Description: "new description"},
+	}
+	cfg.ToolsetConfigs = ToolsetConfigs{
+		"ts1": tools.ToolsetConfig{Name: "ts1", ToolNames: []string{"t1"}},
+	}
+	report, err = s.Reload(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want = ReloadReport{
+		Sources:  ResourceChanges{Removed: []string{"s2"}},
+		Tools:    ResourceChanges{Changed: []string{"t3"}, Removed: []string{"t2"}},
+		Toolsets: ResourceChanges{Removed: []string{"ts2"}},
+	}
+	if diff := cmp.Diff(want, report); diff != "" {
+		t.Fatalf("unexpected report (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"tool/t3"}, initialized); diff != "" {
+		t.Fatalf("unexpected initialized resources (-want +got):\n%s", diff)
+	}
+	if got := report.String(); got != "sources: removed [s2]; tools: changed [t3], removed [t2]; toolsets: removed [ts2]" {
+		t.Fatalf("unexpected report string: %s", got)
+	}
+}
+
+func TestReloadClosesSourcesOfFailedReload(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unable to set up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	initializedSources := map[string]sources.Source{}
+	cfg := ServerConfig{
+		Version: fakeVersionString,
+		SourceConfigs: SourceConfigs{
+			"s2": sourceRecorder{sources: initializedSources},
+		},
+		ToolConfigs: ToolConfigs{
+			"t1": mockToolConfig{Name: "t1", Source: "missing"},
+		},
+	}
+	// the source is initialized before the tool fails
+	if _, _, err := initializeResources(ctx, cfg, nil, nil); err == nil {
+		t.Fatalf("expected initialization to fail")
+	}
+	s2, ok := initializedSources["s2"]
+	if !ok {
+		t.Fatalf("source was not initialized")
+	}
+	if !s2.(*mockSource).closed.Load() {
+		t.Fatalf("source of a failed initialization was not closed")
+	}
+}
+
+// sourceRecorder records the source it initializes, so that tests can check
+// it after a failed initialization.
+type sourceRecorder struct {
+	sources map[string]sources.Source
+}
+
+func (c sourceRecorder) SourceConfigKind() string {
+	return "mock"
+}
+
+func (c sourceRecorder) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	s := &mockSource{}
+	c.sources["s2"] = s
+	return s, nil
+}
+
+// waitFor waits for cond to be true.
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReloadNotifiesToolsListChanged(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	testLogger, err := log.NewStdLogger(os.Stderr, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx = util.WithLogger(ctx, testLogger)
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := ServerConfig{
+		Version:       fakeVersionString,
+		SourceConfigs: SourceConfigs{"s1": mockSourceConfig{Name: "s1"}},
+		ToolConfigs:   ToolConfigs{"t1": mockToolConfig{Name: "t1", Source: "s1"}},
+	}
+	s, err := NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+
+	// stdio session
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	defer inW.Close()
+	go func() {
+		_ = NewStdioSession(s, inR, outW).Start(ctx)
+		outW.Close()
+	}()
+	stdout := bufio.NewReader(outR)
+	initialize := fmt.Sprintf(`{"jsonrpc":"2.0","id":"mcp-initialize","method":"initialize","params":{"protocolVersion":%q}}`, protocolVersion20241105)
+	if _, err := fmt.Fprintf(inW, "%s\n", initialize); err != nil {
+		t.Fatalf("error writing to stdin: %s", err)
+	}
+	if line, err := stdout.ReadString('\n'); err != nil || !strings.Contains(line, `"listChanged":true`) {
+		t.Fatalf("unexpected initialize response: %q, %v", line, err)
+	}
+
+	// sse session
+	ts := httptest.NewServer(s.root)
+	defer ts.Close()
+	resp, err := runSseRequest(ts, "/mcp/sse", "")
+	if err != nil {
+		t.Fatalf("unable to run sse request: %s", err)
+	}
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+	readData := func() string {
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatalf("unable to read sse event: %s", err)
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				return strings.TrimSpace(data)
+			}
+		}
+	}
+	endpoint := readData()
+	postResp, err := http.Post(endpoint, "application/json", strings.NewReader(initialize))
+	if err != nil {
+		t.Fatalf("unable to initialize sse session: %s", err)
+	}
+	postResp.Body.Close()
+	if data := readData(); !strings.Contains(data, `"id":"mcp-initialize"`) {
+		t.Fatalf("unexpected initialize event: %s", data)
+	}
+
+	cfg.ToolConfigs = ToolConfigs{
+		"t1": mockToolConfig{Name: "t1", Source: "s1"},
+		"t2": mockToolConfig{Name: "t2", Source: "s1"},
+	}
+	if _, err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`
+	line, err := stdout.ReadString('\n')
+	if err != nil {
+		t.Fatalf("error reading from stdout: %s", err)
+	}
+	if got := strings.TrimSpace(line); got != want {
+		t.Fatalf("unexpected stdio notification: got %s, want %s", got, want)
+	}
+	if got := readData(); got != want {
+		t.Fatalf("unexpected sse notification: got %s, want %s", got, want)
+	}
+}
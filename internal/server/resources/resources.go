@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// ResourceManager contains available resources for the server. Should be initialized with NewResourceManager().
+type ResourceManager struct {
+	mu           sync.RWMutex
+	sources      map[string]sources.Source
+	authServices map[string]auth.AuthService
+	tools        map[string]tools.Tool
+	toolsets     map[string]tools.Toolset
+	prompts      map[string]prompts.Prompt
+	// inFlight counts the requests that use the current resources.
+	inFlight *sync.WaitGroup
+	// closing counts the replaced sources that are waiting to be closed.
+	closing sync.WaitGroup
+}
+
+func NewResourceManager(
+	sourcesMap map[string]sources.Source,
+	authServicesMap map[string]auth.AuthService,
+	toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset,
+	promptsMap map[string]prompts.Prompt,
+) *ResourceManager {
+	resourceMgr := &ResourceManager{
+		mu:           sync.RWMutex{},
+		sources:      sourcesMap,
+		authServices: authServicesMap,
+		tools:        toolsMap,
+		toolsets:     toolsetsMap,
+		prompts:      promptsMap,
+		inFlight:     &sync.WaitGroup{},
+	}
+
+	return resourceMgr
+}
+
+func (r *ResourceManager) GetSource(sourceName string) (sources.Source, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	source, ok := r.sources[sourceName]
+	return source, ok
+}
+
+func (r *ResourceManager) GetAuthService(authServiceName string) (auth.AuthService, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	authService, ok := r.authServices[authServiceName]
+	return authService, ok
+}
+
+func (r *ResourceManager) GetTool(toolName string) (tools.Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tool, ok := r.tools[toolName]
+	return tool, ok
+}
+
+func (r *ResourceManager) GetToolset(toolsetName string) (tools.Toolset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	toolset, ok := r.toolsets[toolsetName]
+	return toolset, ok
+}
+
+func (r *ResourceManager) GetPrompt(promptName string) (prompts.Prompt, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prompt, ok := r.prompts[promptName]
+	return prompt, ok
+}
+
+// Track marks the start of a request that uses the current resources, and
+// returns the function to call once the request is done. It must be called
+// before the resources of the request are retrieved.
+func (r *ResourceManager) Track() (done func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	inFlight := r.inFlight
+	inFlight.Add(1)
+	return sync.OnceFunc(inFlight.Done)
+}
+
+// SetResources replaces the resources of the manager. Sources that are
+// replaced are closed in the background, once the requests tracked before
+// the replacement are done.
+func (r *ResourceManager) SetResources(ctx context.Context, sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset, promptsMap map[string]prompts.Prompt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	replaced := make(map[string]sources.Source)
+	for name, s := range r.sources {
+		if n, ok := sourcesMap[name]; !ok || n != s {
+			replaced[name] = s
+		}
+	}
+	inFlight := r.inFlight
+	r.inFlight = &sync.WaitGroup{}
+	r.sources = sourcesMap
+	r.authServices = authServicesMap
+	r.tools = toolsMap
+	r.toolsets = toolsetsMap
+	r.prompts = promptsMap
+
+	if len(replaced) == 0 {
+		return
+	}
+	r.closing.Add(1)
+	go func() {
+		defer r.closing.Done()
+		inFlight.Wait()
+		// the replaced sources are closed even if the server is shutting down
+		ctx := context.WithoutCancel(ctx)
+		if err := closeSources(ctx, replaced); err != nil {
+			if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+				logger.WarnContext(ctx, err.Error())
+			}
+		}
+	}()
+}
+
+// Close waits for the in-flight requests to be done and for the replaced
+// sources to be closed, then closes the current sources. It returns the
+// context's error if it is done before the requests are.
+func (r *ResourceManager) Close(ctx context.Context) error {
+	r.mu.RLock()
+	inFlight, current := r.inFlight, r.sources
+	r.mu.RUnlock()
+
+	done := make(chan struct{})
+	go func() {
+		inFlight.Wait()
+		r.closing.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("unable to close sources: %w", ctx.Err())
+	}
+	return closeSources(ctx, current)
+}
+
+// closeSources closes the sources that implement sources.Closer.
+func closeSources(ctx context.Context, sourcesMap map[string]sources.Source) error {
+	var errs []error
+	for name, s := range sourcesMap {
+		c, ok := s.(sources.Closer)
+		if !ok {
+			continue
+		}
+		if err := c.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close source %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *ResourceManager) GetSourcesMap() map[string]sources.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sources
+}
+
+func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.authServices
+}
+
+func (r *ResourceManager) GetToolsMap() map[string]tools.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.tools
+}
+
+func (r *ResourceManager) GetPromptsMap() map[string]prompts.Prompt {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.prompts
+}
+
+// GetSchemaProviders returns the sources that are able to describe their
+// tables and views, keyed by source name.
+func (r *ResourceManager) GetSchemaProviders() map[string]sources.SchemaProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	providers := make(map[string]sources.SchemaProvider)
+	for name, s := range r.sources {
+		if p, ok := s.(sources.SchemaProvider); ok {
+			providers[name] = p
+		}
+	}
+	return providers
+}
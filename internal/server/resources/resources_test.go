@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+// mockSource is a source that records when it is closed
+type mockSource struct {
+	closed   atomic.Int32
+	closeErr error
+}
+
+func (s *mockSource) SourceKind() string {
+	return "mock"
+}
+
+func (s *mockSource) Close(context.Context) error {
+	s.closed.Add(1)
+	return s.closeErr
+}
+
+// waitClosed waits for a source to be closed in the background.
+func waitClosed(t *testing.T, s *mockSource) {
+	deadline := time.Now().Add(5 * time.Second)
+	for s.closed.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("source was not closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSetResourcesClosesReplacedSources(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unable to set up logger: %s", err)
+	}
+	replaced, kept := &mockSource{}, &mockSource{}
+	r := resources.NewResourceManager(map[string]sources.Source{"replaced": replaced, "kept": kept}, nil, nil, nil, nil)
+
+	// an invocation that started before the reload
+	done := r.Track()
+	newSource := &mockSource{}
+	r.SetResources(ctx, map[string]sources.Source{"replaced": newSource, "kept": kept}, nil, nil, nil, nil)
+
+	// invocations that start after the reload don't delay closing
+	newDone := r.Track()
+	defer newDone()
+
+	time.Sleep(10 * time.Millisecond)
+	if replaced.closed.Load() != 0 {
+		t.Fatalf("replaced source was closed before the in-flight invocation was done")
+	}
+	done()
+	waitClosed(t, replaced)
+
+	if kept.closed.Load() != 0 || newSource.closed.Load() != 0 {
+		t.Fatalf("sources that are still used were closed")
+	}
+	if got, _ := r.GetSource("replaced"); got != newSource {
+		t.Fatalf("unexpected source after reload: %v", got)
+	}
+}
+
+func TestClose(t *testing.T) {
+	ctx := context.Background()
+	s1, s2 := &mockSource{}, &mockSource{closeErr: errors.New("close failed")}
+	r := resources.NewResourceManager(map[string]sources.Source{"s1": s1, "s2": s2}, nil, nil, nil, nil)
+
+	done := r.Track()
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := r.Close(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected close to time out while an invocation is in flight, got %v", err)
+	}
+	if s1.closed.Load() != 0 {
+		t.Fatalf("source was closed while an invocation was in flight")
+	}
+
+	done()
+	// done is idempotent
+	done()
+	err := r.Close(ctx)
+	if err == nil || !strings.Contains(err.Error(), `unable to close source "s2": close failed`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s1.closed.Load() != 1 || s2.closed.Load() != 1 {
+		t.Fatalf("expected every source to be closed once")
+	}
+}
@@ -16,12 +16,16 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -29,11 +33,14 @@ import (
 	"github.com/go-chi/httplog/v2"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -46,81 +53,20 @@ type Server struct {
 	logger          log.Logger
 	instrumentation *telemetry.Instrumentation
 	sseManager      *sseManager
-	ResourceMgr     *ResourceManager
-}
-
-// ResourceManager contains available resources for the server. Should be initialized with NewResourceManager().
-type ResourceManager struct {
-	mu           sync.RWMutex
-	sources      map[string]sources.Source
-	authServices map[string]auth.AuthService
-	tools        map[string]tools.Tool
-	toolsets     map[string]tools.Toolset
-}
-
-func NewResourceManager(
-	sourcesMap map[string]sources.Source,
-	authServicesMap map[string]auth.AuthService,
-	toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset,
-) *ResourceManager {
-	resourceMgr := &ResourceManager{
-		mu:           sync.RWMutex{},
-		sources:      sourcesMap,
-		authServices: authServicesMap,
-		tools:        toolsMap,
-		toolsets:     toolsetsMap,
-	}
-
-	return resourceMgr
-}
-
-func (r *ResourceManager) GetSource(sourceName string) (sources.Source, bool) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	source, ok := r.sources[sourceName]
-	return source, ok
-}
-
-func (r *ResourceManager) GetAuthService(authServiceName string) (auth.AuthService, bool) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	authService, ok := r.authServices[authServiceName]
-	return authService, ok
-}
-
-func (r *ResourceManager) GetTool(toolName string) (tools.Tool, bool) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	tool, ok := r.tools[toolName]
-	return tool, ok
-}
-
-func (r *ResourceManager) GetToolset(toolsetName string) (tools.Toolset, bool) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	toolset, ok := r.toolsets[toolsetName]
-	return toolset, ok
-}
-
-func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.sources = sourcesMap
-	r.authServices = authServicesMap
-	r.tools = toolsMap
-	r.toolsets = toolsetsMap
-}
-
-func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return r.authServices
-}
-
-func (r *ResourceManager) GetToolsMap() map[string]tools.Tool {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return r.tools
+	// sessions are the live stateful sessions (stdio or http with sse)
+	sessions sessionRegistry
+	pageSize int
+	// pageTokens signs the page tokens of paginated tools
+	pageTokens tools.PageTokenSigner
+	// filterToolsByAuth hides tools that the caller can't invoke when listing tools
+	filterToolsByAuth bool
+	// reloadMu serializes reloads of the resources
+	reloadMu sync.Mutex
+	// loaded holds the configs and resources of the last successful load
+	loaded atomic.Pointer[loadedResources]
+	// sourceHealth holds the results of the health checks of the sources
+	sourceHealth sourceHealth
+	ResourceMgr  *resources.ResourceManager
 }
 
 func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
@@ -128,8 +74,22 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	map[string]auth.AuthService,
 	map[string]tools.Tool,
 	map[string]tools.Toolset,
+	map[string]prompts.Prompt,
 	error,
 ) {
+	res, _, err := initializeResources(ctx, cfg, nil, nil)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	return res.sources, res.authServices, res.tools, res.toolsets, res.prompts, nil
+}
+
+// initializeResources initializes the resources of cfg. Resources of prev
+// whose config didn't change are reused instead of being initialized again,
+// and the returned report lists the resources that were added, changed or
+// removed compared to prev. onLazyInit is called whenever a tool of a lazy
+// source is initialized.
+func initializeResources(ctx context.Context, cfg ServerConfig, prev *loadedResources, onLazyInit func()) (_ *loadedResources, _ ReloadReport, err error) {
 	ctx = util.WithUserAgent(ctx, cfg.Version)
 	instrumentation, err := util.InstrumentationFromContext(ctx)
 	if err != nil {
@@ -141,33 +101,72 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 		panic(err)
 	}
 
+	if prev == nil {
+		prev = &loadedResources{}
+	}
+	res := &loadedResources{
+		cfg:          cfg,
+		sources:      make(map[string]sources.Source),
+		authServices: make(map[string]auth.AuthService),
+		baseTools:    make(map[string]tools.Tool),
+		toolsets:     make(map[string]tools.Toolset),
+		prompts:      make(map[string]prompts.Prompt),
+	}
+	// the default toolset is added to a copy, to leave the configs of the caller untouched
+	res.cfg.ToolsetConfigs = maps.Clone(cfg.ToolsetConfigs)
+	if res.cfg.ToolsetConfigs == nil {
+		res.cfg.ToolsetConfigs = make(ToolsetConfigs)
+	}
+	report := ReloadReport{
+		Sources:      diffConfigs(prev.cfg.SourceConfigs, cfg.SourceConfigs),
+		AuthServices: diffConfigs(prev.cfg.AuthServiceConfigs, cfg.AuthServiceConfigs),
+		Tools:        diffConfigs(prev.cfg.ToolConfigs, cfg.ToolConfigs),
+		Prompts:      diffConfigs(prev.cfg.PromptConfigs, cfg.PromptConfigs),
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		// close the sources initialized by this call, since they won't be used
+		for name, s := range res.sources {
+			if c, ok := s.(sources.Closer); ok && s != prev.sources[name] {
+				_ = c.Close(ctx)
+			}
+		}
+	}()
+
+	// optional sources that were unavailable are initialized again
+	for name := range prev.unavailableSources {
+		if _, ok := cfg.SourceConfigs[name]; ok && !report.Sources.touched(name) {
+			report.Sources.Changed = append(report.Sources.Changed, name)
+		}
+	}
+	slices.Sort(report.Sources.Changed)
+
 	// initialize and validate the sources from configs
-	sourcesMap := make(map[string]sources.Source)
+	sourceConfigs := make(map[string]sources.SourceConfig)
 	for name, sc := range cfg.SourceConfigs {
-		s, err := func() (sources.Source, error) {
-			childCtx, span := instrumentation.Tracer.Start(
-				ctx,
-				"toolbox/server/source/init",
-				trace.WithAttributes(attribute.String("source_kind", sc.SourceConfigKind())),
-				trace.WithAttributes(attribute.String("source_name", name)),
-			)
-			defer span.End()
-			s, err := sc.Initialize(childCtx, instrumentation.Tracer)
-			if err != nil {
-				return nil, fmt.Errorf("unable to initialize source %q: %w", name, err)
-			}
-			return s, nil
-		}()
-		if err != nil {
-			return nil, nil, nil, nil, err
+		if !report.Sources.touched(name) {
+			res.sources[name] = prev.sources[name]
+			continue
 		}
-		sourcesMap[name] = s
+		sourceConfigs[name] = sc
 	}
-	l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources.", len(sourcesMap)))
+	initialized, unavailable, err := initializeSources(ctx, instrumentation.Tracer, sourceConfigs)
+	maps.Copy(res.sources, initialized)
+	res.unavailableSources = unavailable
+	if err != nil {
+		return nil, report, err
+	}
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources.", len(res.sources)))
 
 	// initialize and validate the auth services from configs
-	authServicesMap := make(map[string]auth.AuthService)
 	for name, sc := range cfg.AuthServiceConfigs {
+		if !report.AuthServices.touched(name) {
+			res.authServices[name] = prev.authServices[name]
+			continue
+		}
 		a, err := func() (auth.AuthService, error) {
 			_, span := instrumentation.Tracer.Start(
 				ctx,
@@ -183,15 +182,30 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			return a, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, report, err
 		}
-		authServicesMap[name] = a
+		res.authServices[name] = a
 	}
-	l.InfoContext(ctx, fmt.Sprintf("Initialized %d authServices.", len(authServicesMap)))
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d authServices.", len(res.authServices)))
+
+	// tools are initialized again if the source they use was replaced
+	replacedSources := slices.Concat(report.Sources.Changed, report.Sources.Removed)
+	for name, tc := range cfg.ToolConfigs {
+		if report.Tools.touched(name) || len(replacedSources) == 0 {
+			continue
+		}
+		if source, ok := toolSource(tc); !ok || slices.Contains(replacedSources, source) {
+			report.Tools.Changed = append(report.Tools.Changed, name)
+		}
+	}
+	slices.Sort(report.Tools.Changed)
 
 	// initialize and validate the tools from configs
-	toolsMap := make(map[string]tools.Tool)
 	for name, tc := range cfg.ToolConfigs {
+		if !report.Tools.touched(name) {
+			res.baseTools[name] = prev.baseTools[name]
+			continue
+		}
 		t, err := func() (tools.Tool, error) {
 			_, span := instrumentation.Tracer.Start(
 				ctx,
@@ -200,32 +214,66 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 				trace.WithAttributes(attribute.String("tool_name", name)),
 			)
 			defer span.End()
-			t, err := tc.Initialize(sourcesMap)
+			t, err := initializeTool(name, tc, res.sources, res.unavailableSources, onLazyInit)
 			if err != nil {
 				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
 			}
 			return t, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, report, err
 		}
-		toolsMap[name] = t
+		res.baseTools[name] = t
 	}
-	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(toolsMap)))
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(res.baseTools)))
 
 	// create a default toolset that contains all tools
-	allToolNames := make([]string, 0, len(toolsMap))
-	for name := range toolsMap {
+	allToolNames := make([]string, 0, len(res.baseTools))
+	for name := range res.baseTools {
 		allToolNames = append(allToolNames, name)
 	}
-	if cfg.ToolsetConfigs == nil {
-		cfg.ToolsetConfigs = make(ToolsetConfigs)
+	// keep a stable order, so that the default toolset can be paginated
+	slices.Sort(allToolNames)
+	res.cfg.ToolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames}
+
+	// the policy of a toolset applies to each of its tools, no matter which
+	// toolset the tool is invoked through
+	res.tools = maps.Clone(res.baseTools)
+	toolsetNames := slices.Sorted(maps.Keys(res.cfg.ToolsetConfigs))
+	for _, name := range toolsetNames {
+		tc := res.cfg.ToolsetConfigs[name]
+		if tc.Allow == "" {
+			continue
+		}
+		p, err := tools.ParsePolicy(fmt.Sprintf("toolset %q", name), tc.Allow)
+		if err != nil {
+			return nil, report, err
+		}
+		for _, toolName := range tc.ToolNames {
+			if t, ok := res.tools[toolName]; ok {
+				res.tools[toolName] = tools.WithPolicies(t, p)
+			}
+		}
 	}
-	cfg.ToolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames}
+
+	// toolsets are initialized again if any of their tools changed
+	report.Toolsets = diffConfigs(prev.cfg.ToolsetConfigs, res.cfg.ToolsetConfigs)
+	for name, tc := range res.cfg.ToolsetConfigs {
+		if report.Toolsets.touched(name) {
+			continue
+		}
+		if slices.ContainsFunc(tc.ToolNames, report.Tools.touched) {
+			report.Toolsets.Changed = append(report.Toolsets.Changed, name)
+		}
+	}
+	slices.Sort(report.Toolsets.Changed)
 
 	// initialize and validate the toolsets from configs
-	toolsetsMap := make(map[string]tools.Toolset)
-	for name, tc := range cfg.ToolsetConfigs {
+	for name, tc := range res.cfg.ToolsetConfigs {
+		if !report.Toolsets.touched(name) {
+			res.toolsets[name] = prev.toolsets[name]
+			continue
+		}
 		t, err := func() (tools.Toolset, error) {
 			_, span := instrumentation.Tracer.Start(
 				ctx,
@@ -233,20 +281,48 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 				trace.WithAttributes(attribute.String("toolset_name", name)),
 			)
 			defer span.End()
-			t, err := tc.Initialize(cfg.Version, toolsMap)
+			t, err := tc.Initialize(cfg.Version, res.tools)
 			if err != nil {
 				return tools.Toolset{}, fmt.Errorf("unable to initialize toolset %q: %w", name, err)
 			}
 			return t, err
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, report, err
+		}
+		res.toolsets[name] = t
+	}
+	// the default toolset isn't reported
+	report.Toolsets = report.Toolsets.without("")
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d toolsets.", len(res.toolsets)))
+
+	// initialize and validate the prompts from configs
+	for name, pc := range cfg.PromptConfigs {
+		if !report.Prompts.touched(name) {
+			res.prompts[name] = prev.prompts[name]
+			continue
+		}
+		p, err := func() (prompts.Prompt, error) {
+			_, span := instrumentation.Tracer.Start(
+				ctx,
+				"toolbox/server/prompt/init",
+				trace.WithAttributes(attribute.String("prompt_name", name)),
+			)
+			defer span.End()
+			p, err := pc.Initialize()
+			if err != nil {
+				return prompts.Prompt{}, fmt.Errorf("unable to initialize prompt %q: %w", name, err)
+			}
+			return p, nil
+		}()
+		if err != nil {
+			return nil, report, err
 		}
-		toolsetsMap[name] = t
+		res.prompts[name] = p
 	}
-	l.InfoContext(ctx, fmt.Sprintf("Initialized %d toolsets.", len(toolsetsMap)))
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d prompts.", len(res.prompts)))
 
-	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
+	return res, report, nil
 }
 
 // NewServer returns a Server object based on provided Config.
@@ -264,6 +340,11 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		return nil, err
 	}
 
+	pageTokens, err := tools.NewPageTokenSigner([]byte(cfg.PageTokenKey), cfg.PageTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
 	// set up http serving
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
@@ -298,7 +379,10 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	httpLogger := httplog.NewLogger("httplog", httpOpts)
 	r.Use(httplog.RequestLogger(httpLogger))
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := InitializeConfigs(ctx, cfg)
+	// s is assigned below, before any tool of a lazy source is used
+	var s *Server
+	refreshCtx := context.WithoutCancel(ctx)
+	loaded, _, err := initializeResources(ctx, cfg, nil, func() { s.refreshToolsets(refreshCtx) })
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize configs: %w", err)
 	}
@@ -308,17 +392,21 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
-
-	s := &Server{
-		version:         cfg.Version,
-		srv:             srv,
-		root:            r,
-		logger:          l,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+	resourceManager := resources.NewResourceManager(loaded.sources, loaded.authServices, loaded.tools, loaded.toolsets, loaded.prompts)
+
+	s = &Server{
+		version:           cfg.Version,
+		srv:               srv,
+		root:              r,
+		logger:            l,
+		instrumentation:   instrumentation,
+		sseManager:        sseManager,
+		pageSize:          cfg.PageSize,
+		pageTokens:        pageTokens,
+		filterToolsByAuth: cfg.FilterToolsByAuth,
+		ResourceMgr:       resourceManager,
 	}
+	s.loaded.Store(loaded)
 	// control plane
 	apiR, err := apiRouter(s)
 	if err != nil {
@@ -341,6 +429,8 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("🧰 Hello, World! 🧰"))
 	})
+	r.Get("/healthz", healthzHandler)
+	r.Get("/readyz", func(w http.ResponseWriter, r *http.Request) { readyzHandler(s, w, r) })
 
 	return s, nil
 }
@@ -374,9 +464,105 @@ func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writ
 	return stdioServer.Start(ctx)
 }
 
+// recordPolicyDenial logs and counts tool invocations that were denied by an
+// authorization policy. Other errors are ignored.
+func (s *Server) recordPolicyDenial(ctx context.Context, err error) {
+	var denied *tools.PolicyDeniedError
+	if !errors.As(err, &denied) {
+		return
+	}
+	s.logger.WarnContext(ctx, denied.Error())
+	s.instrumentation.ToolDenied.Add(
+		ctx,
+		1,
+		metric.WithAttributes(attribute.String("toolbox.name", denied.Tool)),
+		metric.WithAttributes(attribute.String("toolbox.policy", denied.Source)),
+	)
+}
+
+// authorizedToolset returns the toolset without the tools that the caller
+// can't invoke with the auth services verified from header. The toolset is
+// returned as is if filtering tools by auth is disabled.
+func (s *Server) authorizedToolset(ctx context.Context, toolset tools.Toolset, header http.Header) tools.Toolset {
+	if !s.filterToolsByAuth {
+		return toolset
+	}
+	return toolset.Filter(s.toolAuthorizer(ctx, header))
+}
+
+// schemaProviders returns the sources that are able to describe their schema
+// and are used by a tool of the toolset that the caller is able to invoke,
+// with the auth services verified from header. Unlike tools, resources are
+// always scoped to the caller, since reading them doesn't check the auth
+// requirements of any tool.
+func (s *Server) schemaProviders(ctx context.Context, toolset tools.Toolset, header http.Header) map[string]sources.SchemaProvider {
+	providers := s.ResourceMgr.GetSchemaProviders()
+	scoped := make(map[string]sources.SchemaProvider)
+	loaded := s.loaded.Load()
+	if len(providers) == 0 || loaded == nil {
+		return scoped
+	}
+	var authorized func(name string) bool
+	for _, m := range toolset.McpManifest {
+		sourceName, ok := toolSource(loaded.cfg.ToolConfigs[m.Name])
+		if !ok {
+			continue
+		}
+		p, ok := providers[sourceName]
+		if _, seen := scoped[sourceName]; !ok || seen {
+			continue
+		}
+		// the auth services are only verified if the toolset uses a provider
+		if authorized == nil {
+			authorized = s.toolAuthorizer(ctx, header)
+		}
+		if authorized(m.Name) {
+			scoped[sourceName] = p
+		}
+	}
+	return scoped
+}
+
+// toolAuthorizer returns a function that reports whether the caller is able
+// to invoke a tool, with the auth services verified from header.
+func (s *Server) toolAuthorizer(ctx context.Context, header http.Header) func(name string) bool {
+	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
+	claimsFromAuth := make(map[string]map[string]any)
+	for _, aS := range s.ResourceMgr.GetAuthServiceMap() {
+		claims, err := aS.GetClaimsFromHeader(ctx, header)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			continue
+		}
+		if claims == nil {
+			// authService not present in header
+			continue
+		}
+		claimsFromAuth[aS.GetName()] = claims
+	}
+	verifiedAuthServices := slices.Collect(maps.Keys(claimsFromAuth))
+	hasAccessToken := tools.AccessToken(header.Get("Authorization")) != ""
+
+	return func(name string) bool {
+		tool, ok := s.ResourceMgr.GetTool(name)
+		if !ok {
+			return false
+		}
+		if tool.RequiresClientAuthorization() && !hasAccessToken {
+			return false
+		}
+		return tool.Authorized(verifiedAuthServices) && tools.AuthorizePolicies(name, tool, claimsFromAuth) == nil
+	}
+}
+
 // Shutdown gracefully shuts down the server without interrupting any active
 // connections. It uses http.Server.Shutdown() and has the same functionality.
+// Once the active connections are done, the sources are closed.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "shutting down the server.")
-	return s.srv.Shutdown(ctx)
+	if err := s.srv.Shutdown(ctx); err != nil {
+		return err
+	}
+	s.logger.DebugContext(ctx, "closing sources.")
+	return s.ResourceMgr.Close(ctx)
 }
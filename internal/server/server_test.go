@@ -24,8 +24,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
@@ -147,7 +149,10 @@ func TestUpdateServer(t *testing.T) {
 			Name: "example-toolset", Tools: []*tools.Tool{},
 		},
 	}
-	s.ResourceMgr.SetResources(newSources, newAuth, newTools, newToolsets)
+	newPrompts := map[string]prompts.Prompt{
+		"example-prompt": {Name: "example-prompt"},
+	}
+	s.ResourceMgr.SetResources(ctx, newSources, newAuth, newTools, newToolsets, newPrompts)
 	if err != nil {
 		t.Errorf("error updating server: %s", err)
 	}
@@ -171,4 +176,9 @@ func TestUpdateServer(t *testing.T) {
 	if diff := cmp.Diff(gotToolset, newToolsets["example-toolset"]); diff != "" {
 		t.Errorf("error updating server, toolset (-want +got):\n%s", diff)
 	}
+
+	gotPrompt, _ := s.ResourceMgr.GetPrompt("example-prompt")
+	if diff := cmp.Diff(gotPrompt, newPrompts["example-prompt"], cmpopts.IgnoreUnexported(prompts.Prompt{})); diff != "" {
+		t.Errorf("error updating server, prompt (-want +got):\n%s", diff)
+	}
 }
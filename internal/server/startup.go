@@ -0,0 +1,428 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxConcurrentSourceInits bounds the number of sources that are initialized
+// concurrently.
+const maxConcurrentSourceInits = 8
+
+// initializeSource initializes a source from its config.
+func initializeSource(ctx context.Context, tracer trace.Tracer, name string, sc sources.SourceConfig) (sources.Source, error) {
+	childCtx, span := tracer.Start(
+		ctx,
+		"toolbox/server/source/init",
+		trace.WithAttributes(attribute.String("source_kind", sc.SourceConfigKind())),
+		trace.WithAttributes(attribute.String("source_name", name)),
+	)
+	defer span.End()
+	s, err := sc.Initialize(childCtx, tracer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize source %q: %w", name, err)
+	}
+	return s, nil
+}
+
+// initializeSources initializes the given source configs concurrently. Lazy
+// sources are replaced by a lazySource, and optional sources that fail to
+// initialize are returned in unavailable instead of failing.
+func initializeSources(ctx context.Context, tracer trace.Tracer, configs map[string]sources.SourceConfig) (initialized map[string]sources.Source, unavailable map[string]error, err error) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	initialized = make(map[string]sources.Source)
+	unavailable = make(map[string]error)
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	sem := make(chan struct{}, maxConcurrentSourceInits)
+	for name, sc := range configs {
+		startup := sources.GetStartupPolicy(sc)
+		if startup == sources.StartupLazy {
+			mu.Lock()
+			initialized[name] = newLazySource(ctx, tracer, name, sc)
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			s, err := initializeSource(ctx, tracer, name, sc)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				initialized[name] = s
+			case startup == sources.StartupOptional:
+				logger.WarnContext(ctx, fmt.Sprintf("Optional source %q is unavailable: %s", name, err))
+				unavailable[name] = err
+			default:
+				errs = append(errs, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// keep a stable order, since sources are initialized concurrently
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return initialized, unavailable, errors.Join(errs...)
+}
+
+var _ sources.Source = &lazySource{}
+var _ sources.Closer = &lazySource{}
+var _ sources.SchemaProvider = &lazySource{}
+
+// lazySource stands in for a source with a lazy startup policy, until the
+// first use of one of its tools initializes it.
+type lazySource struct {
+	// ctx is the context the source is initialized with
+	ctx    context.Context
+	tracer trace.Tracer
+	name   string
+	cfg    sources.SourceConfig
+
+	mu     sync.Mutex
+	source sources.Source
+	closed bool
+}
+
+func newLazySource(ctx context.Context, tracer trace.Tracer, name string, cfg sources.SourceConfig) *lazySource {
+	return &lazySource{
+		ctx:    context.WithoutCancel(ctx),
+		tracer: tracer,
+		name:   name,
+		cfg:    cfg,
+	}
+}
+
+func (s *lazySource) SourceKind() string {
+	return s.cfg.SourceConfigKind()
+}
+
+// get returns the source, and initializes it on first use. Failed
+// initializations are attempted again on the next use.
+func (s *lazySource) get() (sources.Source, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("source %q is closed", s.name)
+	}
+	if s.source != nil {
+		return s.source, nil
+	}
+	src, err := initializeSource(s.ctx, s.tracer, s.name, s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.source = src
+	return src, nil
+}
+
+// initialized returns the source, or nil if it wasn't initialized yet.
+func (s *lazySource) initialized() sources.Source {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.source
+}
+
+// ListSchemaObjects initializes the source, and lists its tables and views if
+// it is able to describe its schema.
+func (s *lazySource) ListSchemaObjects(ctx context.Context) ([]sources.SchemaObject, error) {
+	src, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	p, ok := src.(sources.SchemaProvider)
+	if !ok {
+		return []sources.SchemaObject{}, nil
+	}
+	return p.ListSchemaObjects(ctx)
+}
+
+// DescribeSchemaObject initializes the source, and describes one of its tables
+// or views if it is able to describe its schema.
+func (s *lazySource) DescribeSchemaObject(ctx context.Context, schema, name string) (sources.SchemaDescription, error) {
+	src, err := s.get()
+	if err != nil {
+		return sources.SchemaDescription{}, err
+	}
+	p, ok := src.(sources.SchemaProvider)
+	if !ok {
+		return sources.SchemaDescription{}, sources.ErrSchemaObjectNotFound
+	}
+	return p.DescribeSchemaObject(ctx, schema, name)
+}
+
+// Close closes the source if it was initialized.
+func (s *lazySource) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if c, ok := s.source.(sources.Closer); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+// configTool serves the manifests of a tool that isn't initialized. The
+// manifests are built from the description, parameters and auth requirements
+// of the tool config, so tools that compute their parameters when they are
+// initialized are listed without them.
+type configTool struct {
+	authRequired []string
+	manifest     tools.Manifest
+	mcpManifest  tools.McpManifest
+}
+
+func newConfigTool(name string, tc tools.SourceToolConfig) configTool {
+	info := tc.ToolConfigInfo()
+	params, _, err := tools.ProcessParameters(info.TemplateParameters, tools.WithPageTokenParameter(info.Parameters, info.PageSize))
+	if err != nil {
+		// the error is reported once the tool is initialized
+		params = info.Parameters
+	}
+	return configTool{
+		authRequired: info.AuthRequired,
+		manifest:     tools.Manifest{Description: info.Description, Parameters: params.Manifest(), AuthRequired: info.AuthRequired},
+		mcpManifest:  tools.GetMcpManifest(name, info.Description, info.AuthRequired, params),
+	}
+}
+
+func (t configTool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t configTool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t configTool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.authRequired, verifiedAuthServices)
+}
+
+func (t configTool) RequiresClientAuthorization() bool {
+	return false
+}
+
+var _ tools.Tool = unavailableTool{}
+
+// unavailableTool is a tool whose source failed to initialize.
+type unavailableTool struct {
+	configTool
+	name string
+	err  error
+}
+
+func newUnavailableTool(name string, tc tools.SourceToolConfig, sourceErr error) unavailableTool {
+	t := unavailableTool{configTool: newConfigTool(name, tc), name: name, err: sourceErr}
+	t.manifest.Unavailable = sourceErr.Error()
+	if t.mcpManifest.Metadata == nil {
+		t.mcpManifest.Metadata = make(map[string]any)
+	}
+	t.mcpManifest.Metadata["toolbox/unavailable"] = sourceErr.Error()
+	return t
+}
+
+func (t unavailableTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	return nil, fmt.Errorf("tool %q is %w: %w", t.name, tools.ErrUnavailable, t.err)
+}
+
+func (t unavailableTool) ParseParams(map[string]any, map[string]map[string]any) (tools.ParamValues, error) {
+	return nil, fmt.Errorf("tool %q is %w: %w", t.name, tools.ErrUnavailable, t.err)
+}
+
+var _ tools.Tool = &lazyTool{}
+
+// lazyTool is a tool of a lazy source. The source and the tool are
+// initialized the first time the tool is used.
+type lazyTool struct {
+	configTool
+	name       string
+	cfg        tools.ToolConfig
+	sourceName string
+	source     *lazySource
+	// onInit is called once the tool is initialized
+	onInit func()
+
+	mu   sync.Mutex
+	tool atomic.Pointer[tools.Tool]
+}
+
+func newLazyTool(name string, tc tools.SourceToolConfig, sourceName string, source *lazySource, onInit func()) *lazyTool {
+	return &lazyTool{
+		configTool: newConfigTool(name, tc),
+		name:       name,
+		cfg:        tc,
+		sourceName: sourceName,
+		source:     source,
+		onInit:     onInit,
+	}
+}
+
+// get returns the initialized tool, and initializes it on first use.
+func (t *lazyTool) get() (tools.Tool, error) {
+	if tool := t.tool.Load(); tool != nil {
+		return *tool, nil
+	}
+	tool, initialized, err := func() (tools.Tool, bool, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if tool := t.tool.Load(); tool != nil {
+			return *tool, false, nil
+		}
+		src, err := t.source.get()
+		if err != nil {
+			return nil, false, fmt.Errorf("tool %q is %w: %w", t.name, tools.ErrUnavailable, err)
+		}
+		tool, err := t.cfg.Initialize(map[string]sources.Source{t.sourceName: src})
+		if err != nil {
+			return nil, false, fmt.Errorf("tool %q is %w: unable to initialize tool: %w", t.name, tools.ErrUnavailable, err)
+		}
+		t.tool.Store(&tool)
+		return tool, true, nil
+	}()
+	if initialized && t.onInit != nil {
+		t.onInit()
+	}
+	return tool, err
+}
+
+func (t *lazyTool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	tool, err := t.get()
+	if err != nil {
+		return nil, err
+	}
+	return tools.InvokeWithTimeout(ctx, tool, params, accessToken)
+}
+
+func (t *lazyTool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	tool, err := t.get()
+	if err != nil {
+		return nil, err
+	}
+	return tool.ParseParams(data, claims)
+}
+
+func (t *lazyTool) Manifest() tools.Manifest {
+	if tool := t.tool.Load(); tool != nil {
+		return (*tool).Manifest()
+	}
+	return t.configTool.Manifest()
+}
+
+func (t *lazyTool) McpManifest() tools.McpManifest {
+	if tool := t.tool.Load(); tool != nil {
+		return (*tool).McpManifest()
+	}
+	return t.configTool.McpManifest()
+}
+
+func (t *lazyTool) Authorized(verifiedAuthServices []string) bool {
+	if tool := t.tool.Load(); tool != nil {
+		return (*tool).Authorized(verifiedAuthServices)
+	}
+	return t.configTool.Authorized(verifiedAuthServices)
+}
+
+func (t *lazyTool) RequiresClientAuthorization() bool {
+	if tool := t.tool.Load(); tool != nil {
+		return (*tool).RequiresClientAuthorization()
+	}
+	return t.configTool.RequiresClientAuthorization()
+}
+
+// initializeTool initializes a tool from its config. Tools of a lazy source
+// are initialized on first use, and tools of an unavailable source are
+// replaced by an unavailableTool.
+func initializeTool(name string, tc tools.ToolConfig, srcs map[string]sources.Source, unavailable map[string]error, onLazyInit func()) (tools.Tool, error) {
+	sourceName, ok := toolSource(tc)
+	if !ok {
+		return tc.Initialize(srcs)
+	}
+	lazy, isLazy := srcs[sourceName].(*lazySource)
+	sourceErr, isUnavailable := unavailable[sourceName]
+	if !isLazy && !isUnavailable {
+		return tc.Initialize(srcs)
+	}
+
+	// the policy of the tool is applied to the placeholder, so that it is
+	// enforced before the tool is initialized
+	var policies []*tools.Policy
+	if pc, ok := tc.(tools.PolicyConfig); ok {
+		p, err := tools.ParsePolicy(fmt.Sprintf("tool %q", pc.Name), pc.Allow)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+		tc = pc.ToolConfig
+	}
+	// tools with a source implement SourceToolConfig, see toolSource
+	sc := tc.(tools.SourceToolConfig)
+	var t tools.Tool
+	if isLazy {
+		t = newLazyTool(name, sc, sourceName, lazy, onLazyInit)
+	} else {
+		t = newUnavailableTool(name, sc, sourceErr)
+	}
+	if len(policies) > 0 {
+		t = tools.WithPolicies(t, policies...)
+	}
+	return t, nil
+}
+
+// refreshToolsets initializes the toolsets again, so that they list the
+// manifests of the lazy tools that were initialized since, and notifies the
+// clients of the live sessions.
+func (s *Server) refreshToolsets(ctx context.Context) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	loaded := *s.loaded.Load()
+	toolsets := make(map[string]tools.Toolset, len(loaded.toolsets))
+	for name, tc := range loaded.cfg.ToolsetConfigs {
+		t, err := tc.Initialize(loaded.cfg.Version, loaded.tools)
+		if err != nil {
+			s.logger.ErrorContext(ctx, fmt.Sprintf("unable to refresh toolset %q: %s", name, err))
+			return
+		}
+		toolsets[name] = t
+	}
+	loaded.toolsets = toolsets
+	s.loaded.Store(&loaded)
+	s.ResourceMgr.SetResources(ctx, loaded.sources, loaded.authServices, loaded.tools, loaded.toolsets, loaded.prompts)
+	s.notifyToolsListChanged(ctx)
+}
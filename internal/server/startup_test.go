@@ -0,0 +1,374 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sqlToolConfig mocks tools that compute their parameters when they are
+// initialized, such as the execute-sql tools.
+type sqlToolConfig struct {
+	Name   string
+	Source string
+}
+
+func (c sqlToolConfig) ToolConfigKind() string {
+	return "mock-sql"
+}
+
+func (c sqlToolConfig) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{Source: c.Source}
+}
+
+func (c sqlToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	if _, ok := srcs[c.Source].(*mockSource); !ok {
+		return nil, fmt.Errorf("invalid source for %q", c.Name)
+	}
+	recordInitialized("tool/" + c.Name)
+	return MockTool{Name: c.Name, Params: []tools.Parameter{tools.NewStringParameter("sql", "The sql to execute.")}}, nil
+}
+
+func newStartupTestContext(t *testing.T) context.Context {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unable to set up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	return util.WithInstrumentation(ctx, instrumentation)
+}
+
+func TestStartupPolicies(t *testing.T) {
+	ctx := newStartupTestContext(t)
+	cfg := ServerConfig{
+		Version: fakeVersionString,
+		SourceConfigs: SourceConfigs{
+			"eager-source": mockSourceConfig{Name: "eager-source"},
+			"optional-source": sources.StartupConfig{
+				SourceConfig: mockSourceConfig{Name: "optional-source", Conn: "unreachable", Fail: true},
+				Startup:      sources.StartupOptional,
+			},
+			"lazy-source": sources.StartupConfig{
+				SourceConfig: mockSourceConfig{Name: "lazy-source"},
+				Startup:      sources.StartupLazy,
+			},
+		},
+		ToolConfigs: ToolConfigs{
+			"eager-tool":    mockToolConfig{Name: "eager-tool", Source: "eager-source"},
+			"optional-tool": mockToolConfig{Name: "optional-tool", Source: "optional-source", Description: "optional"},
+			"lazy-tool":     sqlToolConfig{Name: "lazy-tool", Source: "lazy-source"},
+		},
+	}
+	initialized = nil
+	s, err := NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+	if slices.Contains(initialized, "source/lazy-source") || slices.Contains(initialized, "tool/lazy-tool") {
+		t.Fatalf("lazy source was initialized on startup: %v", initialized)
+	}
+
+	toolset, _ := s.ResourceMgr.GetToolset("")
+	if got := len(toolset.Manifest.ToolsManifest); got != 3 {
+		t.Fatalf("unexpected number of tools: got %d, want 3", got)
+	}
+	unavailable := toolset.Manifest.ToolsManifest["optional-tool"]
+	if want := `unable to initialize source "optional-source": unable to connect to "unreachable"`; unavailable.Unavailable != want || unavailable.Description != "optional" {
+		t.Fatalf("unexpected manifest of unavailable tool: got %+v, want reason %q", unavailable, want)
+	}
+	if got := len(toolset.Manifest.ToolsManifest["lazy-tool"].Parameters); got != 0 {
+		t.Fatalf("unexpected parameters before the lazy tool is initialized: %d", got)
+	}
+
+	ts := runServer(s.root, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/api/tool/optional-tool/invoke", bytes.NewBufferString(`{}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || !strings.Contains(string(body), `tool \"optional-tool\" is unavailable`) {
+		t.Fatalf("unexpected response for unavailable tool: %d, %s", resp.StatusCode, body)
+	}
+
+	for range 2 {
+		resp, body, err = runRequest(ts, http.MethodPost, "/api/tool/lazy-tool/invoke", bytes.NewBufferString(`{"sql": "SELECT 1"}`), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected response for lazy tool: %d, %s", resp.StatusCode, body)
+		}
+	}
+	if got := slices.DeleteFunc(slices.Clone(initialized), func(n string) bool { return !strings.Contains(n, "lazy") }); !slices.Equal(got, []string{"source/lazy-source", "tool/lazy-tool"}) {
+		t.Fatalf("lazy source should be initialized once on first use, got %v", got)
+	}
+
+	// the toolsets are refreshed with the manifest of the initialized tool
+	toolset, _ = s.ResourceMgr.GetToolset("")
+	if got := len(toolset.Manifest.ToolsManifest["lazy-tool"].Parameters); got != 1 {
+		t.Fatalf("unexpected parameters after the lazy tool is initialized: %d", got)
+	}
+
+	// the lazy source is closed with the server
+	lazy, _ := s.ResourceMgr.GetSource("lazy-source")
+	if err := s.ResourceMgr.Close(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !lazy.(*lazySource).source.(*mockSource).closed.Load() {
+		t.Fatalf("lazy source was not closed")
+	}
+}
+
+func TestStartupEagerFailure(t *testing.T) {
+	ctx := newStartupTestContext(t)
+	cfg := ServerConfig{
+		Version: fakeVersionString,
+		SourceConfigs: SourceConfigs{
+			"s1": mockSourceConfig{Name: "s1", Conn: "a", Fail: true},
+			"s2": mockSourceConfig{Name: "s2", Conn: "b", Fail: true},
+			"s3": mockSourceConfig{Name: "s3"},
+		},
+	}
+	_, err := NewServer(ctx, cfg)
+	want := "unable to initialize configs: unable to initialize source \"s1\": unable to connect to \"a\"\nunable to initialize source \"s2\": unable to connect to \"b\""
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+// pagedToolConfig mocks the configs of SQL tools, which declare their
+// parameters and can be paginated.
+type pagedToolConfig struct {
+	sqlToolConfig
+	AuthRequired []string
+}
+
+func (c pagedToolConfig) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:             c.Source,
+		Description:        "some description",
+		AuthRequired:       c.AuthRequired,
+		Parameters:         tools.Parameters{tools.NewIntParameter("id", "some id")},
+		TemplateParameters: tools.Parameters{tools.NewStringParameter("table", "some table")},
+		PageSize:           10,
+	}
+}
+
+func TestConfigToolManifest(t *testing.T) {
+	tc := pagedToolConfig{sqlToolConfig: sqlToolConfig{Name: "paged-tool", Source: "lazy-source"}, AuthRequired: []string{"my-auth"}}
+	ct := newConfigTool("paged-tool", tc)
+
+	var got []string
+	for _, p := range ct.Manifest().Parameters {
+		got = append(got, p.Name)
+	}
+	want := []string{"id", tools.PageTokenParameter, "table"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected parameters: got %q, want %q", got, want)
+	}
+	if props := ct.McpManifest().InputSchema.Properties; len(props) != len(want) {
+		t.Fatalf("unexpected MCP parameters: got %v, want %q", props, want)
+	}
+	if ct.Authorized(nil) {
+		t.Fatalf("expected the tool to require the my-auth auth service")
+	}
+	if !ct.Authorized([]string{"my-auth"}) {
+		t.Fatalf("expected the tool to be authorized with the my-auth auth service")
+	}
+}
+
+// blockingSourceConfig is initialized once all of the sources of its group
+// are initializing at the same time.
+type blockingSourceConfig struct {
+	group *sync.WaitGroup
+}
+
+func (c blockingSourceConfig) SourceConfigKind() string {
+	return "mock"
+}
+
+func (c blockingSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	c.group.Done()
+	done := make(chan struct{})
+	go func() {
+		c.group.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return &mockSource{}, nil
+	case <-time.After(5 * time.Second):
+		return nil, fmt.Errorf("sources were not initialized concurrently")
+	}
+}
+
+func TestInitializeSourcesConcurrently(t *testing.T) {
+	ctx := newStartupTestContext(t)
+	var group sync.WaitGroup
+	configs := make(map[string]sources.SourceConfig)
+	for i := range maxConcurrentSourceInits {
+		group.Add(1)
+		configs[fmt.Sprintf("s%d", i)] = blockingSourceConfig{group: &group}
+	}
+	instrumentation, _ := util.InstrumentationFromContext(ctx)
+	initialized, unavailable, err := initializeSources(ctx, instrumentation.Tracer, configs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(initialized) != maxConcurrentSourceInits || len(unavailable) != 0 {
+		t.Fatalf("unexpected sources: %v, %v", initialized, unavailable)
+	}
+}
+
+func TestReloadRetriesUnavailableSources(t *testing.T) {
+	ctx := newStartupTestContext(t)
+	optional := sources.StartupConfig{
+		SourceConfig: &flakySourceConfig{failures: 1},
+		Startup:      sources.StartupOptional,
+	}
+	cfg := ServerConfig{
+		Version:       fakeVersionString,
+		SourceConfigs: SourceConfigs{"s1": optional},
+		ToolConfigs:   ToolConfigs{"t1": mockToolConfig{Name: "t1", Source: "s1"}},
+	}
+	s, err := NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+	if tool, _ := s.ResourceMgr.GetTool("t1"); tool.Manifest().Unavailable == "" {
+		t.Fatalf("expected tool to be unavailable")
+	}
+	report, err := s.Reload(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !slices.Equal(report.Sources.Changed, []string{"s1"}) || !slices.Equal(report.Tools.Changed, []string{"t1"}) {
+		t.Fatalf("unexpected report: %s", report)
+	}
+	if tool, _ := s.ResourceMgr.GetTool("t1"); tool.Manifest().Unavailable != "" {
+		t.Fatalf("expected tool to be available after reload")
+	}
+}
+
+// flakySourceConfig fails to initialize the given number of times.
+type flakySourceConfig struct {
+	failures int
+}
+
+func (c *flakySourceConfig) SourceConfigKind() string {
+	return "mock"
+}
+
+func (c *flakySourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	if c.failures > 0 {
+		c.failures--
+		return nil, fmt.Errorf("connection refused")
+	}
+	return &mockSource{}, nil
+}
+
+// schemaSourceConfig initializes a source that is able to describe its
+// schema.
+type schemaSourceConfig struct {
+	Name    string
+	Objects []sources.SchemaObject
+}
+
+func (c schemaSourceConfig) SourceConfigKind() string {
+	return "mock-schema"
+}
+
+func (c schemaSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	recordInitialized("source/" + c.Name)
+	return &mockSchemaSource{objects: c.Objects}, nil
+}
+
+func TestLazySourceSchema(t *testing.T) {
+	ctx := newStartupTestContext(t)
+	users := sources.SchemaObject{Schema: "public", Name: "users", Type: "table"}
+	cfg := ServerConfig{
+		Version: fakeVersionString,
+		SourceConfigs: SourceConfigs{
+			"lazy-schema": sources.StartupConfig{
+				SourceConfig: schemaSourceConfig{Name: "lazy-schema", Objects: []sources.SchemaObject{users}},
+				Startup:      sources.StartupLazy,
+			},
+			"lazy-source": sources.StartupConfig{
+				SourceConfig: mockSourceConfig{Name: "lazy-source"},
+				Startup:      sources.StartupLazy,
+			},
+		},
+	}
+	initialized = nil
+	s, err := NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+
+	// lazy sources are listed as schema providers before they are initialized
+	providers := s.ResourceMgr.GetSchemaProviders()
+	if got := slices.Sorted(maps.Keys(providers)); !slices.Equal(got, []string{"lazy-schema", "lazy-source"}) {
+		t.Fatalf("unexpected schema providers: %v", got)
+	}
+	if len(initialized) != 0 {
+		t.Fatalf("lazy sources were initialized on startup: %v", initialized)
+	}
+
+	objects, err := providers["lazy-schema"].ListSchemaObjects(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !slices.Equal(objects, []sources.SchemaObject{users}) {
+		t.Fatalf("unexpected schema objects: %v", objects)
+	}
+	desc, err := providers["lazy-schema"].DescribeSchemaObject(ctx, "public", "users")
+	if err != nil || desc.SchemaObject != users {
+		t.Fatalf("unexpected description: %+v, %v", desc, err)
+	}
+
+	// sources that aren't able to describe their schema have no tables
+	objects, err = providers["lazy-source"].ListSchemaObjects(ctx)
+	if err != nil || len(objects) != 0 {
+		t.Fatalf("unexpected schema objects: %v, %v", objects, err)
+	}
+	if _, err := providers["lazy-source"].DescribeSchemaObject(ctx, "public", "users"); !errors.Is(err, sources.ErrSchemaObjectNotFound) {
+		t.Fatalf("unexpected error: got %v, want %v", err, sources.ErrSchemaObjectNotFound)
+	}
+	slices.Sort(initialized)
+	if !slices.Equal(initialized, []string{"source/lazy-schema", "source/lazy-source"}) {
+		t.Fatalf("lazy sources should be initialized on first use, got %v", initialized)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// extendsField is the field of the tools and templates that extend a template.
+const extendsField = "extends"
+
+// ToolTemplates are reusable, partial tool configs by name. Tools and other
+// templates use a template with `extends: <template>`, and override its
+// fields:
+//   - mappings are merged recursively,
+//   - sequences of mappings that all have a `name`, such as parameters, are
+//     merged by name: the items of the template keep their order, items with
+//     the same name are replaced and the other items are appended,
+//   - other values replace the value of the template, and null removes it.
+type ToolTemplates map[string]map[string]any
+
+type toolTemplatesKey struct{}
+
+// WithToolTemplates returns a context with the templates that the tools
+// decoded with it may extend.
+func WithToolTemplates(ctx context.Context, templates ToolTemplates) context.Context {
+	return context.WithValue(ctx, toolTemplatesKey{}, templates)
+}
+
+func toolTemplatesFromContext(ctx context.Context) ToolTemplates {
+	templates, _ := ctx.Value(toolTemplatesKey{}).(ToolTemplates)
+	return templates
+}
+
+// Extend returns the config of a tool merged with the templates it extends.
+// Configs that don't extend a template are returned as is.
+func (t ToolTemplates) Extend(name string, config map[string]any) (map[string]any, error) {
+	return t.extend(fmt.Sprintf("tool %q", name), config, nil)
+}
+
+// extend merges config with the chain of templates it extends. chain has the
+// templates extended so far, to detect cycles.
+func (t ToolTemplates) extend(what string, config map[string]any, chain []string) (map[string]any, error) {
+	e, ok := config[extendsField]
+	if !ok {
+		return config, nil
+	}
+	parent, ok := e.(string)
+	if !ok || parent == "" {
+		return nil, fmt.Errorf("invalid %q field for %s (must be the name of a template)", extendsField, what)
+	}
+	if slices.Contains(chain, parent) {
+		return nil, fmt.Errorf("%s extends template %q in a cycle: %s", what, parent, strings.Join(append(chain, parent), " -> "))
+	}
+	template, ok := t[parent]
+	if !ok {
+		return nil, fmt.Errorf("%s extends template %q, which isn't declared", what, parent)
+	}
+	base, err := t.extend(fmt.Sprintf("template %q", parent), template, append(chain, parent))
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := maps.Clone(config)
+	delete(overrides, extendsField)
+	return mergeTemplateMaps(base, overrides), nil
+}
+
+// mergeTemplateMaps returns the fields of base overridden by the fields of
+// overrides. Neither map is modified.
+func mergeTemplateMaps(base, overrides map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overrides))
+	for k, v := range base {
+		if k != extendsField {
+			merged[k] = cloneTemplateValue(v)
+		}
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = mergeTemplateValues(merged[k], v)
+	}
+	return merged
+}
+
+// mergeTemplateValues returns the value of a field of a template overridden
+// by the value of the same field.
+func mergeTemplateValues(base, override any) any {
+	switch o := override.(type) {
+	case map[string]any:
+		if b, ok := base.(map[string]any); ok {
+			return mergeTemplateMaps(b, o)
+		}
+	case []any:
+		b, ok := base.([]any)
+		if !ok || !namedItems(b) || !namedItems(o) {
+			break
+		}
+		merged := make([]any, 0, len(b)+len(o))
+		index := make(map[string]int)
+		for _, item := range b {
+			index[item.(map[string]any)["name"].(string)] = len(merged)
+			merged = append(merged, item)
+		}
+		for _, item := range o {
+			name := item.(map[string]any)["name"].(string)
+			if i, ok := index[name]; ok {
+				merged[i] = item
+				continue
+			}
+			index[name] = len(merged)
+			merged = append(merged, item)
+		}
+		return cloneTemplateValue(merged)
+	}
+	return cloneTemplateValue(override)
+}
+
+// namedItems reports whether every item of a sequence is a mapping with a
+// string name.
+func namedItems(items []any) bool {
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return false
+		}
+		if _, ok := m["name"].(string); !ok {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// cloneTemplateValue returns a deep copy of a value, so that the templates
+// aren't modified through the configs extending them.
+func cloneTemplateValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, item := range v {
+			c[k] = cloneTemplateValue(item)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = cloneTemplateValue(item)
+		}
+		return c
+	}
+	return v
+}
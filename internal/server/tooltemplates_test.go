@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExtendToolTemplates(t *testing.T) {
+	templates := ToolTemplates{
+		"pg-tool": {
+			"kind":         "postgres-sql",
+			"source":       "my-pg-instance",
+			"authRequired": []any{"my-google-auth"},
+			"parameters": []any{
+				map[string]any{"name": "limit", "type": "integer", "description": "Max rows."},
+				map[string]any{"name": "offset", "type": "integer", "description": "First row."},
+			},
+		},
+		"pg-search-tool": {
+			"extends":     "pg-tool",
+			"description": "Search a table.",
+		},
+	}
+	tcs := []struct {
+		desc   string
+		config map[string]any
+		want   map[string]any
+	}{
+		{
+			desc:   "no template",
+			config: map[string]any{"kind": "postgres-sql", "statement": "SELECT 1"},
+			want:   map[string]any{"kind": "postgres-sql", "statement": "SELECT 1"},
+		},
+		{
+			desc: "chain of templates",
+			config: map[string]any{
+				"extends":   "pg-search-tool",
+				"statement": "SELECT 1",
+			},
+			want: map[string]any{
+				"kind":         "postgres-sql",
+				"source":       "my-pg-instance",
+				"description":  "Search a table.",
+				"statement":    "SELECT 1",
+				"authRequired": []any{"my-google-auth"},
+				"parameters": []any{
+					map[string]any{"name": "limit", "type": "integer", "description": "Max rows."},
+					map[string]any{"name": "offset", "type": "integer", "description": "First row."},
+				},
+			},
+		},
+		{
+			desc: "overridden fields",
+			config: map[string]any{
+				"extends":      "pg-tool",
+				"source":       "other-pg-instance",
+				"authRequired": []any{"other-auth"},
+				"parameters": []any{
+					map[string]any{"name": "name", "type": "string", "description": "Name."},
+					map[string]any{"name": "limit", "type": "integer", "description": "Max rows, up to 10."},
+				},
+			},
+			want: map[string]any{
+				"kind":         "postgres-sql",
+				"source":       "other-pg-instance",
+				"authRequired": []any{"other-auth"},
+				"parameters": []any{
+					map[string]any{"name": "limit", "type": "integer", "description": "Max rows, up to 10."},
+					map[string]any{"name": "offset", "type": "integer", "description": "First row."},
+					map[string]any{"name": "name", "type": "string", "description": "Name."},
+				},
+			},
+		},
+		{
+			desc: "removed fields",
+			config: map[string]any{
+				"extends":      "pg-tool",
+				"authRequired": nil,
+				"parameters":   []any{},
+			},
+			want: map[string]any{
+				"kind":       "postgres-sql",
+				"source":     "my-pg-instance",
+				"parameters": []any{},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := templates.Extend("my-tool", tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected config (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// the templates aren't modified through the configs extending them
+	got, err := templates.Extend("my-tool", map[string]any{"extends": "pg-tool"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got["parameters"].([]any)[0].(map[string]any)["type"] = "string"
+	if templates["pg-tool"]["parameters"].([]any)[0].(map[string]any)["type"] != "integer" {
+		t.Fatalf("template was modified: %v", templates["pg-tool"])
+	}
+}
+
+func TestFailExtendToolTemplates(t *testing.T) {
+	templates := ToolTemplates{
+		"a": {"extends": "b"},
+		"b": {"extends": "a"},
+	}
+	tcs := []struct {
+		desc    string
+		config  map[string]any
+		wantErr string
+	}{
+		{
+			desc:    "undeclared template",
+			config:  map[string]any{"extends": "missing"},
+			wantErr: `tool "my-tool" extends template "missing", which isn't declared`,
+		},
+		{
+			desc:    "invalid extends",
+			config:  map[string]any{"extends": []any{"a"}},
+			wantErr: `invalid "extends" field for tool "my-tool" (must be the name of a template)`,
+		},
+		{
+			desc:    "cycle",
+			config:  map[string]any{"extends": "a"},
+			wantErr: `template "b" extends template "a" in a cycle: a -> b -> a`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := templates.Extend("my-tool", tc.config)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -135,6 +135,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close is a no-op since the source doesn't hold any connections.
+func (s *Source) Close(ctx context.Context) error {
+	return nil
+}
+
 func (s *Source) GetService(ctx context.Context, accessToken string) (*alloydbrestapi.Service, error) {
 	if s.UseClientOAuth {
 		token := &oauth2.Token{AccessToken: accessToken}
@@ -70,6 +70,8 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
+	sources.ResultLimits `yaml:",inline"`
+
 	// BigQuery configs
 	Name            string   `yaml:"name" validate:"required"`
 	Kind            string   `yaml:"kind" validate:"required"`
@@ -78,6 +80,7 @@ type Config struct {
 	WriteMode       string   `yaml:"writeMode"`
 	AllowedDatasets []string `yaml:"allowedDatasets"`
 	UseClientOAuth  bool     `yaml:"useClientOAuth"`
+	QueryTimeout    string   `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -147,8 +150,11 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
+		ResultLimits: r.ResultLimits,
+
 		Name:               r.Name,
 		Kind:               SourceKind,
+		QueryTimeout:       r.QueryTimeout,
 		Project:            r.Project,
 		Location:           r.Location,
 		Client:             client,
@@ -173,9 +179,12 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
+	sources.ResultLimits
+
 	// BigQuery Google SQL struct with client
 	Name                      string `yaml:"name"`
 	Kind                      string `yaml:"kind"`
+	QueryTimeout              string `yaml:"queryTimeout"`
 	Project                   string
 	Location                  string
 	Client                    *bigqueryapi.Client
@@ -205,10 +214,30 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client == nil {
+		return nil
+	}
+	return s.Client.Close()
+}
+
 func (s *Source) BigQueryClient() *bigqueryapi.Client {
 	return s.Client
 }
 
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
 func (s *Source) BigQueryRestService() *bigqueryrestapi.Service {
 	return s.RestService
 }
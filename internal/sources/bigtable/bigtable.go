@@ -82,6 +82,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client == nil {
+		return nil
+	}
+	return s.Client.Close()
+}
+
 func (s *Source) BigtableClient() *bigtable.Client {
 	return s.Client
 }
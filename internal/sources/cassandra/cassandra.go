@@ -91,6 +91,14 @@ func (s Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the session and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Session != nil {
+		s.Session.Close()
+	}
+	return nil
+}
+
 var _ sources.Source = &Source{}
 
 func initCassandraSession(ctx context.Context, tracer trace.Tracer, c Config) (*gocql.Session, error) {
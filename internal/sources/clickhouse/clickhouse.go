@@ -47,15 +47,18 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string `yaml:"name" validate:"required"`
-	Kind     string `yaml:"kind" validate:"required"`
-	Host     string `yaml:"host" validate:"required"`
-	Port     string `yaml:"port" validate:"required"`
-	Database string `yaml:"database" validate:"required"`
-	User     string `yaml:"user" validate:"required"`
-	Password string `yaml:"password"`
-	Protocol string `yaml:"protocol"`
-	Secure   bool   `yaml:"secure"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string `yaml:"name" validate:"required"`
+	Kind         string `yaml:"kind" validate:"required"`
+	Host         string `yaml:"host" validate:"required"`
+	Port         string `yaml:"port" validate:"required"`
+	Database     string `yaml:"database" validate:"required"`
+	User         string `yaml:"user" validate:"required"`
+	Password     string `yaml:"password"`
+	Protocol     string `yaml:"protocol"`
+	Secure       bool   `yaml:"secure"`
+	QueryTimeout string `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -74,9 +77,12 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		QueryTimeout: r.QueryTimeout,
+		Pool:         pool,
 	}
 	return s, nil
 }
@@ -84,19 +90,53 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *sql.DB
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Pool         *sql.DB
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Pool == nil {
+		return nil
+	}
+	return s.Pool.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Pool == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Pool.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.Pool)
+}
+
 func (s *Source) ClickHousePool() *sql.DB {
 	return s.Pool
 }
 
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
 func validateConfig(protocol string) error {
 	validProtocols := map[string]bool{"http": true, "https": true}
 
@@ -131,6 +131,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the idle connections of the HTTP client.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client != nil {
+		s.Client.CloseIdleConnections()
+	}
+	return nil
+}
+
 func (s *Source) GetClient(ctx context.Context, accessToken string) (*http.Client, error) {
 	if s.UseClientOAuth {
 		if accessToken == "" {
@@ -48,17 +48,21 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
+	sources.ResultLimits `yaml:",inline"`
+
 	// Cloud SQL MSSQL configs
-	Name      string         `yaml:"name" validate:"required"`
-	Kind      string         `yaml:"kind" validate:"required"`
-	Project   string         `yaml:"project" validate:"required"`
-	Region    string         `yaml:"region" validate:"required"`
-	Instance  string         `yaml:"instance" validate:"required"`
-	IPAddress string         `yaml:"ipAddress" validate:"required"`
-	IPType    sources.IPType `yaml:"ipType" validate:"required"`
-	User      string         `yaml:"user" validate:"required"`
-	Password  string         `yaml:"password" validate:"required"`
-	Database  string         `yaml:"database" validate:"required"`
+	Name         string         `yaml:"name" validate:"required"`
+	Kind         string         `yaml:"kind" validate:"required"`
+	Project      string         `yaml:"project" validate:"required"`
+	Region       string         `yaml:"region" validate:"required"`
+	Instance     string         `yaml:"instance" validate:"required"`
+	IPAddress    string         `yaml:"ipAddress" validate:"required"`
+	IPType       sources.IPType `yaml:"ipType" validate:"required"`
+	User         string         `yaml:"user" validate:"required"`
+	Password     string         `yaml:"password" validate:"required"`
+	Database     string         `yaml:"database" validate:"required"`
+	ReadOnly     bool           `yaml:"readOnly"`
+	QueryTimeout string         `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -80,9 +84,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Db:   db,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		Db:           db,
 	}
 	return s, nil
 }
@@ -90,10 +98,14 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
+	sources.ResultLimits
+
 	// Cloud SQL MSSQL struct with connection pool
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Db   *sql.DB
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Db           *sql.DB
 }
 
 func (s *Source) SourceKind() string {
@@ -101,11 +113,47 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Db == nil {
+		return nil
+	}
+	return s.Db.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Db == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Db.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.Db)
+}
+
 func (s *Source) MSSQLDB() *sql.DB {
 	// Returns a Cloud SQL MSSQL database connection pool
 	return s.Db
 }
 
+// MSSQLReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) MSSQLReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
 func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipAddress, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
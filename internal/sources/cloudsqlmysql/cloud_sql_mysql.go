@@ -47,15 +47,19 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string         `yaml:"name" validate:"required"`
-	Kind     string         `yaml:"kind" validate:"required"`
-	Project  string         `yaml:"project" validate:"required"`
-	Region   string         `yaml:"region" validate:"required"`
-	Instance string         `yaml:"instance" validate:"required"`
-	IPType   sources.IPType `yaml:"ipType"`
-	User     string         `yaml:"user" validate:"required"`
-	Password string         `yaml:"password" validate:"required"`
-	Database string         `yaml:"database" validate:"required"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string         `yaml:"name" validate:"required"`
+	Kind         string         `yaml:"kind" validate:"required"`
+	Project      string         `yaml:"project" validate:"required"`
+	Region       string         `yaml:"region" validate:"required"`
+	Instance     string         `yaml:"instance" validate:"required"`
+	IPType       sources.IPType `yaml:"ipType"`
+	User         string         `yaml:"user" validate:"required"`
+	Password     string         `yaml:"password" validate:"required"`
+	Database     string         `yaml:"database" validate:"required"`
+	ReadOnly     bool           `yaml:"readOnly"`
+	QueryTimeout string         `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -74,9 +78,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		Pool:         pool,
 	}
 	return s, nil
 }
@@ -84,19 +92,59 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *sql.DB
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Pool         *sql.DB
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Pool == nil {
+		return nil
+	}
+	return s.Pool.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Pool == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Pool.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.Pool)
+}
+
 func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+// MySQLReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) MySQLReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
 func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
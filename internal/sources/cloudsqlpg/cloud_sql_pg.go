@@ -47,15 +47,19 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string         `yaml:"name" validate:"required"`
-	Kind     string         `yaml:"kind" validate:"required"`
-	Project  string         `yaml:"project" validate:"required"`
-	Region   string         `yaml:"region" validate:"required"`
-	Instance string         `yaml:"instance" validate:"required"`
-	IPType   sources.IPType `yaml:"ipType" validate:"required"`
-	Database string         `yaml:"database" validate:"required"`
-	User     string         `yaml:"user"`
-	Password string         `yaml:"password"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string         `yaml:"name" validate:"required"`
+	Kind         string         `yaml:"kind" validate:"required"`
+	Project      string         `yaml:"project" validate:"required"`
+	Region       string         `yaml:"region" validate:"required"`
+	Instance     string         `yaml:"instance" validate:"required"`
+	IPType       sources.IPType `yaml:"ipType" validate:"required"`
+	Database     string         `yaml:"database" validate:"required"`
+	User         string         `yaml:"user"`
+	Password     string         `yaml:"password"`
+	ReadOnly     bool           `yaml:"readOnly"`
+	QueryTimeout string         `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -63,7 +67,7 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initCloudSQLPgConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Instance, r.IPType.String(), r.User, r.Password, r.Database)
+	pool, dialer, err := initCloudSQLPgConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Instance, r.IPType.String(), r.User, r.Password, r.Database)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
@@ -74,9 +78,14 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		Pool:         pool,
+		Dialer:       dialer,
 	}
 	return s, nil
 }
@@ -84,19 +93,64 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *pgxpool.Pool
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Pool         *pgxpool.Pool
+	// Dialer creates the connections of the pool
+	Dialer *cloudsqlconn.Dialer
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes all the connections of the pool and stops the dialer.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Pool != nil {
+		s.Pool.Close()
+	}
+	if s.Dialer == nil {
+		return nil
+	}
+	return s.Dialer.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Pool == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Pool.Ping(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.PgxPoolStats(s.Pool)
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// PostgresReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) PostgresReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
 func getConnectionConfig(ctx context.Context, user, pass, dbname string) (string, bool, error) {
 	userAgent, err := util.UserAgentFromContext(ctx)
 	if err != nil {
@@ -130,7 +184,7 @@ func getConnectionConfig(ctx context.Context, user, pass, dbname string) (string
 	return dsn, useIAM, nil
 }
 
-func initCloudSQLPgConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*pgxpool.Pool, error) {
+func initCloudSQLPgConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*pgxpool.Pool, *cloudsqlconn.Dialer, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -138,26 +192,26 @@ func initCloudSQLPgConnectionPool(ctx context.Context, tracer trace.Tracer, name
 	// Configure the driver to connect to the database
 	dsn, useIAM, err := getConnectionConfig(ctx, user, pass, dbname)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get Cloud SQL connection config: %w", err)
+		return nil, nil, fmt.Errorf("unable to get Cloud SQL connection config: %w", err)
 	}
 
 	config, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse connection uri: %w", err)
+		return nil, nil, fmt.Errorf("unable to parse connection uri: %w", err)
 	}
 
 	// Create a new dialer with options
 	userAgent, err := util.UserAgentFromContext(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	opts, err := sources.GetCloudSQLOpts(ipType, userAgent, useIAM)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	d, err := cloudsqlconn.NewDialer(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse connection uri: %w", err)
+		return nil, nil, fmt.Errorf("unable to parse connection uri: %w", err)
 	}
 
 	// Tell the driver to use the Cloud SQL Go Connector to create connections
@@ -166,10 +220,14 @@ func initCloudSQLPgConnectionPool(ctx context.Context, tracer trace.Tracer, name
 		return d.Dial(ctx, i)
 	}
 
+	// Cancel the queries on the server when their context is done
+	sources.SetPgxCancelRequests(config)
+
 	// Interact with the driver directly as you normally would
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
-		return nil, err
+		_ = d.Close()
+		return nil, nil, err
 	}
-	return pool, nil
+	return pool, d, nil
 }
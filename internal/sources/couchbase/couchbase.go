@@ -85,6 +85,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		Kind:                 SourceKind,
 		QueryScanConsistency: r.QueryScanConsistency,
 		Scope:                scope,
+		Cluster:              cluster,
 	}
 	return s, nil
 }
@@ -96,12 +97,21 @@ type Source struct {
 	Kind                 string `yaml:"kind"`
 	QueryScanConsistency uint   `yaml:"queryScanConsistency"`
 	Scope                *gocb.Scope
+	Cluster              *gocb.Cluster
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections to the cluster.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Cluster == nil {
+		return nil
+	}
+	return s.Cluster.Close(nil)
+}
+
 func (s *Source) CouchbaseScope() *gocb.Scope {
 	return s.Scope
 }
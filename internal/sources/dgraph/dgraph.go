@@ -108,6 +108,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the idle connections of the HTTP client.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client != nil && s.Client.httpClient != nil {
+		s.Client.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
 func (s *Source) DgraphClient() *DgraphClient {
 	return s.Client
 }
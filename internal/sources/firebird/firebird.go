@@ -44,13 +44,16 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string `yaml:"name" validate:"required"`
-	Kind     string `yaml:"kind" validate:"required"`
-	Host     string `yaml:"host" validate:"required"`
-	Port     string `yaml:"port" validate:"required"`
-	User     string `yaml:"user" validate:"required"`
-	Password string `yaml:"password" validate:"required"`
-	Database string `yaml:"database" validate:"required"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string `yaml:"name" validate:"required"`
+	Kind         string `yaml:"kind" validate:"required"`
+	Host         string `yaml:"host" validate:"required"`
+	Port         string `yaml:"port" validate:"required"`
+	User         string `yaml:"user" validate:"required"`
+	Password     string `yaml:"password" validate:"required"`
+	Database     string `yaml:"database" validate:"required"`
+	QueryTimeout string `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -69,9 +72,12 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Db:   pool,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		QueryTimeout: r.QueryTimeout,
+		Db:           pool,
 	}
 	return s, nil
 }
@@ -79,19 +85,53 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Db   *sql.DB
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Db           *sql.DB
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Db == nil {
+		return nil
+	}
+	return s.Db.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Db == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Db.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.Db)
+}
+
 func (s *Source) FirebirdDB() *sql.DB {
 	return s.Db
 }
 
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
 func initFirebirdConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string) (*sql.DB, error) {
 	_, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
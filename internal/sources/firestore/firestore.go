@@ -100,6 +100,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client == nil {
+		return nil
+	}
+	return s.Client.Close()
+}
+
 func (s *Source) FirestoreClient() *firestore.Client {
 	return s.Client
 }
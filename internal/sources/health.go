@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HealthChecker is an optional interface for sources that are able to check
+// that their connections are usable.
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+// PoolStatsProvider is an optional interface for sources that expose the
+// statistics of their connection pool.
+type PoolStatsProvider interface {
+	PoolStats() PoolStats
+}
+
+// PoolStats are the statistics of the connection pool of a source.
+type PoolStats struct {
+	// MaxConns is the maximum number of connections, or 0 if unlimited.
+	MaxConns int `json:"maxConns"`
+	// OpenConns is the number of established connections.
+	OpenConns int `json:"openConns"`
+	// InUseConns is the number of connections currently in use.
+	InUseConns int `json:"inUseConns"`
+	// IdleConns is the number of idle connections.
+	IdleConns int `json:"idleConns"`
+	// WaitCount is the total number of times a connection had to be waited for.
+	WaitCount int64 `json:"waitCount"`
+}
+
+// PgxPoolStats returns the statistics of a pgx connection pool.
+func PgxPoolStats(pool *pgxpool.Pool) PoolStats {
+	if pool == nil {
+		return PoolStats{}
+	}
+	stat := pool.Stat()
+	return PoolStats{
+		MaxConns:   int(stat.MaxConns()),
+		OpenConns:  int(stat.TotalConns()),
+		InUseConns: int(stat.AcquiredConns()),
+		IdleConns:  int(stat.IdleConns()),
+		WaitCount:  stat.EmptyAcquireCount(),
+	}
+}
+
+// SQLDBPoolStats returns the statistics of a database/sql connection pool.
+func SQLDBPoolStats(db *sql.DB) PoolStats {
+	if db == nil {
+		return PoolStats{}
+	}
+	stats := db.Stats()
+	return PoolStats{
+		MaxConns:   stats.MaxOpenConnections,
+		OpenConns:  stats.OpenConnections,
+		InUseConns: stats.InUse,
+		IdleConns:  stats.Idle,
+		WaitCount:  stats.WaitCount,
+	}
+}
@@ -131,3 +131,11 @@ type Source struct {
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
+
+// Close closes the idle connections of the HTTP client.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client != nil {
+		s.Client.CloseIdleConnections()
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+// ResultLimits caps the size of the results returned by SQL tools, declared
+// through the `maxRows` and `maxResultBytes` fields of a tool or a source. A
+// limit of 0 means no limit.
+type ResultLimits struct {
+	// MaxRows is the maximum number of rows returned.
+	MaxRows int `yaml:"maxRows" validate:"gte=0"`
+	// MaxResultBytes is the maximum size of the rows returned, encoded as
+	// JSON.
+	MaxResultBytes int `yaml:"maxResultBytes" validate:"gte=0"`
+}
+
+// Or returns the limits, with the unset ones taken from defaults.
+func (l ResultLimits) Or(defaults ResultLimits) ResultLimits {
+	if l.MaxRows == 0 {
+		l.MaxRows = defaults.MaxRows
+	}
+	if l.MaxResultBytes == 0 {
+		l.MaxResultBytes = defaults.MaxResultBytes
+	}
+	return l
+}
+
+// ResultLimiter is an optional interface for sources that declare default
+// result limits for their tools.
+type ResultLimiter interface {
+	SourceResultLimits() ResultLimits
+}
+
+// QueryTimeouter is an optional interface for sources that declare a default
+// query timeout for their tools, through their `queryTimeout` field.
+type QueryTimeouter interface {
+	SourceQueryTimeout() string
+}
@@ -162,6 +162,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close is a no-op since the source doesn't hold any connections.
+func (s *Source) Close(ctx context.Context) error {
+	return nil
+}
+
 func (s *Source) GetApiSettings() *rtl.ApiSettings {
 	return s.ApiSettings
 }
@@ -87,6 +87,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close disconnects the client from the deployment.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client == nil {
+		return nil
+	}
+	return s.Client.Disconnect(ctx)
+}
+
 func (s *Source) MongoClient() *mongo.Client {
 	return s.Client
 }
@@ -47,15 +47,19 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
+	sources.ResultLimits `yaml:",inline"`
+
 	// Cloud SQL MSSQL configs
-	Name     string `yaml:"name" validate:"required"`
-	Kind     string `yaml:"kind" validate:"required"`
-	Host     string `yaml:"host" validate:"required"`
-	Port     string `yaml:"port" validate:"required"`
-	User     string `yaml:"user" validate:"required"`
-	Password string `yaml:"password" validate:"required"`
-	Database string `yaml:"database" validate:"required"`
-	Encrypt  string `yaml:"encrypt"`
+	Name         string `yaml:"name" validate:"required"`
+	Kind         string `yaml:"kind" validate:"required"`
+	Host         string `yaml:"host" validate:"required"`
+	Port         string `yaml:"port" validate:"required"`
+	User         string `yaml:"user" validate:"required"`
+	Password     string `yaml:"password" validate:"required"`
+	Database     string `yaml:"database" validate:"required"`
+	Encrypt      string `yaml:"encrypt"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -77,9 +81,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Db:   db,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		Db:           db,
 	}
 	return s, nil
 }
@@ -87,10 +95,14 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
+	sources.ResultLimits
+
 	// Cloud SQL MSSQL struct with connection pool
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Db   *sql.DB
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Db           *sql.DB
 }
 
 func (s *Source) SourceKind() string {
@@ -98,11 +110,76 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Db == nil {
+		return nil
+	}
+	return s.Db.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Db == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Db.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.Db)
+}
+
 func (s *Source) MSSQLDB() *sql.DB {
 	// Returns a Cloud SQL MSSQL database connection pool
 	return s.Db
 }
 
+// MSSQLReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) MSSQLReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+var _ sources.SchemaProvider = &Source{}
+
+const listSchemaObjectsStatement = `
+	SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE
+	FROM INFORMATION_SCHEMA.TABLES
+	WHERE TABLE_SCHEMA NOT IN ('sys', 'INFORMATION_SCHEMA')
+	ORDER BY TABLE_SCHEMA, TABLE_NAME`
+
+const getSchemaObjectStatement = `
+	SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE
+	FROM INFORMATION_SCHEMA.TABLES
+	WHERE TABLE_SCHEMA = @p1 AND TABLE_NAME = @p2`
+
+const listSchemaColumnsStatement = `
+	SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE
+	FROM INFORMATION_SCHEMA.COLUMNS
+	WHERE TABLE_SCHEMA = @p1 AND TABLE_NAME = @p2
+	ORDER BY ORDINAL_POSITION`
+
+// ListSchemaObjects lists the tables and views of the connected database.
+func (s *Source) ListSchemaObjects(ctx context.Context) ([]sources.SchemaObject, error) {
+	return sources.QuerySchemaObjects(ctx, s.Db, listSchemaObjectsStatement)
+}
+
+// DescribeSchemaObject returns the columns of a table or view.
+func (s *Source) DescribeSchemaObject(ctx context.Context, schema, name string) (sources.SchemaDescription, error) {
+	return sources.QuerySchemaDescription(ctx, s.Db, getSchemaObjectStatement, listSchemaColumnsStatement, schema, name)
+}
+
 func initMssqlConnection(
 	ctx context.Context,
 	tracer trace.Tracer,
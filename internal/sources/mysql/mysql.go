@@ -48,6 +48,8 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
+	sources.ResultLimits `yaml:",inline"`
+
 	Name         string            `yaml:"name" validate:"required"`
 	Kind         string            `yaml:"kind" validate:"required"`
 	Host         string            `yaml:"host" validate:"required"`
@@ -57,6 +59,7 @@ type Config struct {
 	Database     string            `yaml:"database" validate:"required"`
 	QueryTimeout string            `yaml:"queryTimeout"`
 	QueryParams  map[string]string `yaml:"queryParams"`
+	ReadOnly     bool              `yaml:"readOnly"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -75,9 +78,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		Pool:         pool,
 	}
 	return s, nil
 }
@@ -85,19 +92,88 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *sql.DB
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Pool         *sql.DB
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Pool == nil {
+		return nil
+	}
+	return s.Pool.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Pool == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Pool.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.Pool)
+}
+
 func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+// MySQLReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) MySQLReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+var _ sources.SchemaProvider = &Source{}
+
+const listSchemaObjectsStatement = `
+	SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE
+	FROM information_schema.TABLES
+	WHERE TABLE_SCHEMA = DATABASE()
+	ORDER BY TABLE_NAME`
+
+const getSchemaObjectStatement = `
+	SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE
+	FROM information_schema.TABLES
+	WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`
+
+const listSchemaColumnsStatement = `
+	SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE
+	FROM information_schema.COLUMNS
+	WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+	ORDER BY ORDINAL_POSITION`
+
+// ListSchemaObjects lists the tables and views of the connected database.
+func (s *Source) ListSchemaObjects(ctx context.Context) ([]sources.SchemaObject, error) {
+	return sources.QuerySchemaObjects(ctx, s.Pool, listSchemaObjectsStatement)
+}
+
+// DescribeSchemaObject returns the columns of a table or view.
+func (s *Source) DescribeSchemaObject(ctx context.Context, schema, name string) (sources.SchemaDescription, error) {
+	return sources.QuerySchemaDescription(ctx, s.Pool, getSchemaObjectStatement, listSchemaColumnsStatement, schema, name)
+}
+
 func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, queryTimeout string, queryParams map[string]string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -92,6 +92,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the driver and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Driver == nil {
+		return nil
+	}
+	return s.Driver.Close(ctx)
+}
+
 func (s *Source) Neo4jDriver() neo4j.DriverWithContext {
 	return s.Driver
 }
@@ -41,6 +41,8 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
+	sources.ResultLimits `yaml:",inline"`
+
 	Name             string `yaml:"name" validate:"required"`
 	Kind             string `yaml:"kind" validate:"required"`
 	ConnectionString string `yaml:"connectionString,omitempty"` // Direct connection string (hostname[:port]/servicename)
@@ -51,6 +53,8 @@ type Config struct {
 	User             string `yaml:"user" validate:"required"`
 	Password         string `yaml:"password" validate:"required"`
 	TnsAdmin         string `yaml:"tnsAdmin,omitempty"` // Optional: override TNS_ADMIN environment variable
+	ReadOnly         bool   `yaml:"readOnly"`
+	QueryTimeout     string `yaml:"queryTimeout"`
 }
 
 // validate ensures we have one of: tns_alias, connection_string, or host+service_name
@@ -97,9 +101,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		DB:   db,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		DB:           db,
 	}
 	return s, nil
 }
@@ -107,19 +115,59 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	DB   *sql.DB
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	DB           *sql.DB
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.DB == nil {
+		return nil
+	}
+	return s.DB.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.DB == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.DB.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.DB)
+}
+
 func (s *Source) OracleDB() *sql.DB {
 	return s.DB
 }
 
+// OracleReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) OracleReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
 func initOracleConnection(ctx context.Context, tracer trace.Tracer, config Config) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, config.Name)
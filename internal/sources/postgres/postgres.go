@@ -16,6 +16,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -23,6 +24,7 @@ import (
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -47,14 +49,18 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name        string            `yaml:"name" validate:"required"`
-	Kind        string            `yaml:"kind" validate:"required"`
-	Host        string            `yaml:"host" validate:"required"`
-	Port        string            `yaml:"port" validate:"required"`
-	User        string            `yaml:"user" validate:"required"`
-	Password    string            `yaml:"password" validate:"required"`
-	Database    string            `yaml:"database" validate:"required"`
-	QueryParams map[string]string `yaml:"queryParams"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string            `yaml:"name" validate:"required"`
+	Kind         string            `yaml:"kind" validate:"required"`
+	Host         string            `yaml:"host" validate:"required"`
+	Port         string            `yaml:"port" validate:"required"`
+	User         string            `yaml:"user" validate:"required"`
+	Password     string            `yaml:"password" validate:"required"`
+	Database     string            `yaml:"database" validate:"required"`
+	QueryParams  map[string]string `yaml:"queryParams"`
+	ReadOnly     bool              `yaml:"readOnly"`
+	QueryTimeout string            `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -73,9 +79,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		Pool:         pool,
 	}
 	return s, nil
 }
@@ -83,19 +93,135 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *pgxpool.Pool
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Pool         *pgxpool.Pool
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes all the connections of the pool.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Pool != nil {
+		s.Pool.Close()
+	}
+	return nil
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Pool == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Pool.Ping(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.PgxPoolStats(s.Pool)
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// PostgresReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) PostgresReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+var _ sources.SchemaProvider = &Source{}
+
+const listSchemaObjectsStatement = `
+	SELECT table_schema, table_name, table_type
+	FROM information_schema.tables
+	WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY table_schema, table_name`
+
+const getSchemaObjectStatement = `
+	SELECT table_schema, table_name, table_type
+	FROM information_schema.tables
+	WHERE table_schema = $1 AND table_name = $2`
+
+const listSchemaColumnsStatement = `
+	SELECT column_name, data_type, is_nullable
+	FROM information_schema.columns
+	WHERE table_schema = $1 AND table_name = $2
+	ORDER BY ordinal_position`
+
+// ListSchemaObjects lists the tables and views outside of the system schemas.
+func (s *Source) ListSchemaObjects(ctx context.Context) ([]sources.SchemaObject, error) {
+	rows, err := s.Pool.Query(ctx, listSchemaObjectsStatement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tables and views: %w", err)
+	}
+	defer rows.Close()
+
+	objects := make([]sources.SchemaObject, 0)
+	for rows.Next() {
+		var o sources.SchemaObject
+		if err := rows.Scan(&o.Schema, &o.Name, &o.Type); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		o.Type = sources.NormalizeSchemaObjectType(o.Type)
+		objects = append(objects, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	return objects, nil
+}
+
+// DescribeSchemaObject returns the columns of a table or view.
+func (s *Source) DescribeSchemaObject(ctx context.Context, schema, name string) (sources.SchemaDescription, error) {
+	var o sources.SchemaObject
+	err := s.Pool.QueryRow(ctx, getSchemaObjectStatement, schema, name).Scan(&o.Schema, &o.Name, &o.Type)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sources.SchemaDescription{}, sources.ErrSchemaObjectNotFound
+	}
+	if err != nil {
+		return sources.SchemaDescription{}, fmt.Errorf("unable to describe table or view: %w", err)
+	}
+	o.Type = sources.NormalizeSchemaObjectType(o.Type)
+
+	rows, err := s.Pool.Query(ctx, listSchemaColumnsStatement, schema, name)
+	if err != nil {
+		return sources.SchemaDescription{}, fmt.Errorf("unable to describe columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make([]sources.SchemaColumn, 0)
+	for rows.Next() {
+		var c sources.SchemaColumn
+		var nullable string
+		if err := rows.Scan(&c.Name, &c.DataType, &nullable); err != nil {
+			return sources.SchemaDescription{}, fmt.Errorf("unable to parse row: %w", err)
+		}
+		c.Nullable = nullable == "YES"
+		columns = append(columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return sources.SchemaDescription{}, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	return sources.SchemaDescription{SchemaObject: o, Columns: columns}, nil
+}
+
 func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string, queryParams map[string]string) (*pgxpool.Pool, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -120,7 +246,14 @@ func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name,
 		Path:     dbname,
 		RawQuery: ConvertParamMapToRawQuery(queryParams),
 	}
-	pool, err := pgxpool.New(ctx, url.String())
+	config, err := pgxpool.ParseConfig(url.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection uri: %w", err)
+	}
+	// Cancel the queries on the server when their context is done
+	sources.SetPgxCancelRequests(config)
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
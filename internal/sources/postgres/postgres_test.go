@@ -56,6 +56,32 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "read-only example",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					readOnly: true
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:     "my-pg-instance",
+					Kind:     postgres.SourceKind,
+					Host:     "my-host",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					ReadOnly: true,
+				},
+			},
+		},
 		{
 			desc: "example with query params",
 			in: `
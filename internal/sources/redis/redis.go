@@ -147,6 +147,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if c, ok := s.Client.(interface{ Close() error }); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 func (s *Source) RedisClient() RedisClient {
 	return s.Client
 }
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrSchemaObjectNotFound is returned when a requested table or view does not exist.
+var ErrSchemaObjectNotFound = errors.New("table or view not found")
+
+// SchemaObject identifies a table or view exposed by a source.
+type SchemaObject struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	// Type is either "table" or "view".
+	Type string `json:"type"`
+}
+
+// SchemaColumn describes a single column of a table or view.
+type SchemaColumn struct {
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+	Nullable bool   `json:"nullable"`
+}
+
+// SchemaDescription is the detailed description of a table or view.
+type SchemaDescription struct {
+	SchemaObject
+	Columns []SchemaColumn `json:"columns"`
+}
+
+// SchemaProvider is an optional interface for sources that are able to list
+// and describe their tables and views. It is used to serve MCP resources.
+type SchemaProvider interface {
+	ListSchemaObjects(ctx context.Context) ([]SchemaObject, error)
+	DescribeSchemaObject(ctx context.Context, schema, name string) (SchemaDescription, error)
+}
+
+// QuerySchemaObjects runs a statement that returns (schema, name, type) rows
+// and collects them as SchemaObjects.
+func QuerySchemaObjects(ctx context.Context, db *sql.DB, statement string, args ...any) ([]SchemaObject, error) {
+	rows, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tables and views: %w", err)
+	}
+	defer rows.Close()
+
+	objects := make([]SchemaObject, 0)
+	for rows.Next() {
+		var o SchemaObject
+		if err := rows.Scan(&o.Schema, &o.Name, &o.Type); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		o.Type = NormalizeSchemaObjectType(o.Type)
+		objects = append(objects, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	return objects, nil
+}
+
+// QuerySchemaColumns runs a statement that returns (name, data type, nullable)
+// rows and collects them as SchemaColumns. Nullable is expected to be either
+// "YES" or "NO", as reported by information_schema.
+func QuerySchemaColumns(ctx context.Context, db *sql.DB, statement string, args ...any) ([]SchemaColumn, error) {
+	rows, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make([]SchemaColumn, 0)
+	for rows.Next() {
+		var c SchemaColumn
+		var nullable string
+		if err := rows.Scan(&c.Name, &c.DataType, &nullable); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		c.Nullable = nullable == "YES"
+		columns = append(columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	return columns, nil
+}
+
+// QuerySchemaDescription describes a single table or view. Both statements
+// are given the same args, typically the schema and object name.
+func QuerySchemaDescription(ctx context.Context, db *sql.DB, objectStatement, columnsStatement string, args ...any) (SchemaDescription, error) {
+	objects, err := QuerySchemaObjects(ctx, db, objectStatement, args...)
+	if err != nil {
+		return SchemaDescription{}, err
+	}
+	if len(objects) == 0 {
+		return SchemaDescription{}, ErrSchemaObjectNotFound
+	}
+	columns, err := QuerySchemaColumns(ctx, db, columnsStatement, args...)
+	if err != nil {
+		return SchemaDescription{}, err
+	}
+	return SchemaDescription{SchemaObject: objects[0], Columns: columns}, nil
+}
+
+// NormalizeSchemaObjectType maps database specific table types (e.g. "BASE
+// TABLE") to either "table" or "view".
+func NormalizeSchemaObjectType(t string) string {
+	switch t {
+	case "VIEW", "view", "v", "m":
+		return "view"
+	default:
+		return "table"
+	}
+}
@@ -64,6 +64,12 @@ type Source interface {
 	SourceKind() string
 }
 
+// Closer is an optional interface for sources that hold connections or
+// clients, which must be released once the source is no longer used.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
 // InitConnectionSpan adds a span for database pool connection initialization
 func InitConnectionSpan(ctx context.Context, tracer trace.Tracer, sourceKind, sourceName string) (context.Context, trace.Span) {
 	ctx, span := tracer.Start(
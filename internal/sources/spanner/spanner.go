@@ -45,12 +45,15 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string          `yaml:"name" validate:"required"`
-	Kind     string          `yaml:"kind" validate:"required"`
-	Project  string          `yaml:"project" validate:"required"`
-	Instance string          `yaml:"instance" validate:"required"`
-	Dialect  sources.Dialect `yaml:"dialect" validate:"required"`
-	Database string          `yaml:"database" validate:"required"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string          `yaml:"name" validate:"required"`
+	Kind         string          `yaml:"kind" validate:"required"`
+	Project      string          `yaml:"project" validate:"required"`
+	Instance     string          `yaml:"instance" validate:"required"`
+	Dialect      sources.Dialect `yaml:"dialect" validate:"required"`
+	Database     string          `yaml:"database" validate:"required"`
+	QueryTimeout string          `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -64,10 +67,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name:    r.Name,
-		Kind:    SourceKind,
-		Client:  client,
-		Dialect: r.Dialect.String(),
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		QueryTimeout: r.QueryTimeout,
+		Client:       client,
+		Dialect:      r.Dialect.String(),
 	}
 	return s, nil
 }
@@ -75,20 +81,43 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name    string `yaml:"name"`
-	Kind    string `yaml:"kind"`
-	Client  *spanner.Client
-	Dialect string
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Client       *spanner.Client
+	Dialect      string
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client != nil {
+		s.Client.Close()
+	}
+	return nil
+}
+
 func (s *Source) SpannerClient() *spanner.Client {
 	return s.Client
 }
 
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
 func (s *Source) DatabaseDialect() string {
 	return s.Dialect
 }
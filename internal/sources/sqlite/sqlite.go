@@ -45,9 +45,13 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string `yaml:"name" validate:"required"`
-	Kind     string `yaml:"kind" validate:"required"`
-	Database string `yaml:"database" validate:"required"` // Path to SQLite database file
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string `yaml:"name" validate:"required"`
+	Kind         string `yaml:"kind" validate:"required"`
+	Database     string `yaml:"database" validate:"required"` // Path to SQLite database file
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -66,9 +70,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Db:   db,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		ReadOnly:     r.ReadOnly,
+		QueryTimeout: r.QueryTimeout,
+		Db:           db,
 	}
 	return s, nil
 }
@@ -76,19 +84,89 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Db   *sql.DB
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	ReadOnly     bool   `yaml:"readOnly"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Db           *sql.DB
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the database and its idle connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Db == nil {
+		return nil
+	}
+	return s.Db.Close()
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Db == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Db.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLDBPoolStats(s.Db)
+}
+
 func (s *Source) SQLiteDB() *sql.DB {
 	return s.Db
 }
 
+// SQLiteReadOnly returns whether the execute-sql tools of the source are read-only.
+func (s *Source) SQLiteReadOnly() bool {
+	return s.ReadOnly
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+var _ sources.SchemaProvider = &Source{}
+
+// SQLite only exposes the "main" schema for the opened database file.
+const listSchemaObjectsStatement = `
+	SELECT 'main', name, type
+	FROM sqlite_master
+	WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
+	ORDER BY name`
+
+const getSchemaObjectStatement = `
+	SELECT ?1, name, type
+	FROM sqlite_master
+	WHERE ?1 = 'main' AND type IN ('table', 'view') AND name = ?2`
+
+const listSchemaColumnsStatement = `
+	SELECT name, type, CASE WHEN "notnull" = 0 THEN 'YES' ELSE 'NO' END
+	FROM pragma_table_info(?2)
+	WHERE ?1 = 'main'
+	ORDER BY cid`
+
+// ListSchemaObjects lists the tables and views of the database file.
+func (s *Source) ListSchemaObjects(ctx context.Context) ([]sources.SchemaObject, error) {
+	return sources.QuerySchemaObjects(ctx, s.Db, listSchemaObjectsStatement)
+}
+
+// DescribeSchemaObject returns the columns of a table or view.
+func (s *Source) DescribeSchemaObject(ctx context.Context, schema, name string) (sources.SchemaDescription, error) {
+	return sources.QuerySchemaDescription(ctx, s.Db, getSchemaObjectStatement, listSchemaColumnsStatement, schema, name)
+}
+
 func initSQLiteConnection(ctx context.Context, tracer trace.Tracer, name, dbPath string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -15,6 +15,9 @@
 package sqlite_test
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
@@ -23,6 +26,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlSQLite(t *testing.T) {
@@ -47,6 +51,61 @@ func TestParseFromYamlSQLite(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "read-only example",
+			in: `
+            sources:
+                my-sqlite-db:
+                    kind: sqlite
+                    database: /path/to/database.db
+                    readOnly: true
+            `,
+			want: map[string]sources.SourceConfig{
+				"my-sqlite-db": sqlite.Config{
+					Name:     "my-sqlite-db",
+					Kind:     sqlite.SourceKind,
+					Database: "/path/to/database.db",
+					ReadOnly: true,
+				},
+			},
+		},
+		{
+			desc: "result limits example",
+			in: `
+            sources:
+                my-sqlite-db:
+                    kind: sqlite
+                    database: /path/to/database.db
+                    maxRows: 1000
+                    maxResultBytes: 1048576
+            `,
+			want: map[string]sources.SourceConfig{
+				"my-sqlite-db": sqlite.Config{
+					ResultLimits: sources.ResultLimits{MaxRows: 1000, MaxResultBytes: 1048576},
+					Name:         "my-sqlite-db",
+					Kind:         sqlite.SourceKind,
+					Database:     "/path/to/database.db",
+				},
+			},
+		},
+		{
+			desc: "query timeout example",
+			in: `
+            sources:
+                my-sqlite-db:
+                    kind: sqlite
+                    database: /path/to/database.db
+                    queryTimeout: 30s
+            `,
+			want: map[string]sources.SourceConfig{
+				"my-sqlite-db": sqlite.Config{
+					Name:         "my-sqlite-db",
+					Kind:         sqlite.SourceKind,
+					Database:     "/path/to/database.db",
+					QueryTimeout: "30s",
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -64,3 +123,114 @@ func TestParseFromYamlSQLite(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaProviderSQLite(t *testing.T) {
+	ctx := context.Background()
+	cfg := sqlite.Config{
+		Name:     "my-sqlite-db",
+		Kind:     sqlite.SourceKind,
+		Database: filepath.Join(t.TempDir(), "test.db"),
+	}
+	s, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	db := s.(*sqlite.Source).SQLiteDB()
+	for _, stmt := range []string{
+		"CREATE TABLE users (id INTEGER NOT NULL PRIMARY KEY, name TEXT)",
+		"CREATE VIEW named_users AS SELECT id, name FROM users WHERE name IS NOT NULL",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("unable to set up database: %s", err)
+		}
+	}
+
+	provider, ok := s.(sources.SchemaProvider)
+	if !ok {
+		t.Fatalf("sqlite source does not implement sources.SchemaProvider")
+	}
+
+	gotObjects, err := provider.ListSchemaObjects(ctx)
+	if err != nil {
+		t.Fatalf("unable to list schema objects: %s", err)
+	}
+	wantObjects := []sources.SchemaObject{
+		{Schema: "main", Name: "named_users", Type: "view"},
+		{Schema: "main", Name: "users", Type: "table"},
+	}
+	if diff := cmp.Diff(wantObjects, gotObjects); diff != "" {
+		t.Fatalf("incorrect schema objects (-want +got):\n%s", diff)
+	}
+
+	gotDesc, err := provider.DescribeSchemaObject(ctx, "main", "users")
+	if err != nil {
+		t.Fatalf("unable to describe schema object: %s", err)
+	}
+	wantDesc := sources.SchemaDescription{
+		SchemaObject: sources.SchemaObject{Schema: "main", Name: "users", Type: "table"},
+		Columns: []sources.SchemaColumn{
+			{Name: "id", DataType: "INTEGER", Nullable: false},
+			{Name: "name", DataType: "TEXT", Nullable: true},
+		},
+	}
+	if diff := cmp.Diff(wantDesc, gotDesc); diff != "" {
+		t.Fatalf("incorrect schema description (-want +got):\n%s", diff)
+	}
+
+	_, err = provider.DescribeSchemaObject(ctx, "main", "orders")
+	if !errors.Is(err, sources.ErrSchemaObjectNotFound) {
+		t.Fatalf("expected ErrSchemaObjectNotFound, got %v", err)
+	}
+}
+
+func TestCloseSQLite(t *testing.T) {
+	ctx := context.Background()
+	cfg := sqlite.Config{
+		Name:     "my-sqlite-db",
+		Kind:     sqlite.SourceKind,
+		Database: filepath.Join(t.TempDir(), "test.db"),
+	}
+	s, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	closer, ok := s.(sources.Closer)
+	if !ok {
+		t.Fatalf("sqlite source does not implement sources.Closer")
+	}
+	if err := closer.Close(ctx); err != nil {
+		t.Fatalf("unable to close source: %s", err)
+	}
+	if err := s.(*sqlite.Source).SQLiteDB().PingContext(ctx); err == nil {
+		t.Fatalf("expected the database to be closed")
+	}
+}
+
+func TestPingSQLite(t *testing.T) {
+	ctx := context.Background()
+	cfg := sqlite.Config{
+		Name:     "my-sqlite-db",
+		Kind:     sqlite.SourceKind,
+		Database: filepath.Join(t.TempDir(), "test.db"),
+	}
+	s, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	checker, ok := s.(sources.HealthChecker)
+	if !ok {
+		t.Fatalf("sqlite source does not implement sources.HealthChecker")
+	}
+	if err := checker.Ping(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if stats := s.(sources.PoolStatsProvider).PoolStats(); stats.OpenConns != 1 {
+		t.Fatalf("unexpected pool stats: %+v", stats)
+	}
+	if err := s.(sources.Closer).Close(ctx); err != nil {
+		t.Fatalf("unable to close source: %s", err)
+	}
+	if err := checker.Ping(ctx); err == nil {
+		t.Fatalf("expected the ping of a closed source to fail")
+	}
+}
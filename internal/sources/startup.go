@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import "slices"
+
+// StartupPolicy defines when a source is initialized, and how a failure to
+// initialize it is handled.
+type StartupPolicy string
+
+const (
+	// StartupEager sources are initialized when Toolbox starts, and a failure
+	// prevents Toolbox from starting.
+	StartupEager StartupPolicy = "eager"
+	// StartupLazy sources are initialized the first time one of their tools
+	// is used.
+	StartupLazy StartupPolicy = "lazy"
+	// StartupOptional sources are initialized when Toolbox starts, but a
+	// failure only makes their tools unavailable.
+	StartupOptional StartupPolicy = "optional"
+)
+
+// StartupPolicies are the supported startup policies.
+var StartupPolicies = []StartupPolicy{StartupEager, StartupLazy, StartupOptional}
+
+// Valid reports whether the policy is supported.
+func (p StartupPolicy) Valid() bool {
+	return slices.Contains(StartupPolicies, p)
+}
+
+var _ SourceConfig = StartupConfig{}
+
+// StartupConfig sets the startup policy of a SourceConfig, declared through
+// the `startup` field of the source.
+type StartupConfig struct {
+	SourceConfig
+	Startup StartupPolicy
+}
+
+// GetStartupPolicy returns the startup policy of a source config. Sources are
+// eager unless a policy is declared.
+func GetStartupPolicy(sc SourceConfig) StartupPolicy {
+	if c, ok := sc.(StartupConfig); ok {
+		return c.Startup
+	}
+	return StartupEager
+}
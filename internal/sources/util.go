@@ -21,8 +21,12 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/cloudsqlconn"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/oauth2/google"
 )
 
@@ -104,3 +108,13 @@ func GetIAMAccessToken(ctx context.Context) (string, error) {
 	}
 	return token.AccessToken, nil
 }
+
+// SetPgxCancelRequests makes a pgx pool send a cancel request to the server
+// when the context of a query is done, so that the server stops running the
+// query, like pg_cancel_backend. The connection is closed if the query does
+// not stop within a few seconds.
+func SetPgxCancelRequests(config *pgxpool.Config) {
+	config.ConnConfig.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: pgConn, DeadlineDelay: 5 * time.Second}
+	}
+}
@@ -120,6 +120,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client and its connections.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Client != nil {
+		s.Client.Close()
+	}
+	return nil
+}
+
 func (s *Source) ValkeyClient() valkey.Client {
 	return s.Client
 }
@@ -44,6 +44,8 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
+	sources.ResultLimits `yaml:",inline"`
+
 	Name                            string `yaml:"name" validate:"required"`
 	Kind                            string `yaml:"kind" validate:"required"`
 	Host                            string `yaml:"host" validate:"required"`
@@ -56,6 +58,7 @@ type Config struct {
 	YBServersRefreshInterval        string `yaml:"ybServersRefreshInterval"`
 	FallBackToTopologyKeysOnly      string `yaml:"fallbackToTopologyKeysOnly"`
 	FailedHostReconnectDelaySeconds string `yaml:"failedHostReconnectDelaySecs"`
+	QueryTimeout                    string `yaml:"queryTimeout"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -74,9 +77,12 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		ResultLimits: r.ResultLimits,
+
+		Name:         r.Name,
+		Kind:         SourceKind,
+		QueryTimeout: r.QueryTimeout,
+		Pool:         pool,
 	}
 	return s, nil
 }
@@ -84,19 +90,63 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *pgxpool.Pool
+	sources.ResultLimits
+
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	QueryTimeout string `yaml:"queryTimeout"`
+	Pool         *pgxpool.Pool
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes all the connections of the pool.
+func (s *Source) Close(ctx context.Context) error {
+	if s.Pool != nil {
+		s.Pool.Close()
+	}
+	return nil
+}
+
+func (s *Source) Ping(ctx context.Context) error {
+	if s.Pool == nil {
+		return fmt.Errorf("source %q is not connected", s.Name)
+	}
+	return s.Pool.Ping(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	if s.Pool == nil {
+		return sources.PoolStats{}
+	}
+	stat := s.Pool.Stat()
+	return sources.PoolStats{
+		MaxConns:   int(stat.MaxConns()),
+		OpenConns:  int(stat.TotalConns()),
+		InUseConns: int(stat.AcquiredConns()),
+		IdleConns:  int(stat.IdleConns()),
+		WaitCount:  stat.EmptyAcquireCount(),
+	}
+}
+
 func (s *Source) YugabyteDBPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// SourceQueryTimeout returns the default query timeout of the tools of the
+// source.
+func (s *Source) SourceQueryTimeout() string {
+	return s.QueryTimeout
+}
+
+// SourceResultLimits returns the default result limits of the tools of the
+// source.
+func (s *Source) SourceResultLimits() sources.ResultLimits {
+	return s.ResultLimits
+}
+
 func initYugabyteDBConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, loadBalance, topologyKeys, refreshInterval, explicitFallback, failedHostTTL string) (*pgxpool.Pool, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
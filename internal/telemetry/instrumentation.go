@@ -29,19 +29,25 @@ const (
 	toolsetGetCountName = "toolbox.server.toolset.get.count"
 	toolGetCountName    = "toolbox.server.tool.get.count"
 	toolInvokeCountName = "toolbox.server.tool.invoke.count"
+	toolDeniedCountName = "toolbox.server.tool.policy.denied.count"
 	mcpSseCountName     = "toolbox.server.mcp.sse.count"
 	mcpPostCountName    = "toolbox.server.mcp.post.count"
+	reloadCountName     = "toolbox.server.reload.count"
+	reloadChangesName   = "toolbox.server.reload.changes.count"
 )
 
 // Instrumentation defines the telemetry instrumentation for toolbox
 type Instrumentation struct {
-	Tracer     trace.Tracer
-	meter      metric.Meter
-	ToolsetGet metric.Int64Counter
-	ToolGet    metric.Int64Counter
-	ToolInvoke metric.Int64Counter
-	McpSse     metric.Int64Counter
-	McpPost    metric.Int64Counter
+	Tracer        trace.Tracer
+	meter         metric.Meter
+	ToolsetGet    metric.Int64Counter
+	ToolGet       metric.Int64Counter
+	ToolInvoke    metric.Int64Counter
+	ToolDenied    metric.Int64Counter
+	McpSse        metric.Int64Counter
+	McpPost       metric.Int64Counter
+	Reload        metric.Int64Counter
+	ReloadChanges metric.Int64Counter
 }
 
 func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, error) {
@@ -78,6 +84,15 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", toolInvokeCountName, err)
 	}
 
+	toolDenied, err := meter.Int64Counter(
+		toolDeniedCountName,
+		metric.WithDescription("Number of tool invocations denied by an authorization policy."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolDeniedCountName, err)
+	}
+
 	mcpSse, err := meter.Int64Counter(
 		mcpSseCountName,
 		metric.WithDescription("Number of MCP SSE connection requests."),
@@ -96,14 +111,35 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", mcpPostCountName, err)
 	}
 
+	reload, err := meter.Int64Counter(
+		reloadCountName,
+		metric.WithDescription("Number of reloads of the tools file."),
+		metric.WithUnit("{reload}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", reloadCountName, err)
+	}
+
+	reloadChanges, err := meter.Int64Counter(
+		reloadChangesName,
+		metric.WithDescription("Number of resources added, changed or removed by reloads of the tools file."),
+		metric.WithUnit("{resource}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", reloadChangesName, err)
+	}
+
 	instrumentation := &Instrumentation{
-		Tracer:     tracer,
-		meter:      meter,
-		ToolsetGet: toolsetGet,
-		ToolGet:    toolGet,
-		ToolInvoke: toolInvoke,
-		McpSse:     mcpSse,
-		McpPost:    mcpPost,
+		Tracer:        tracer,
+		meter:         meter,
+		ToolsetGet:    toolsetGet,
+		ToolGet:       toolGet,
+		ToolInvoke:    toolInvoke,
+		ToolDenied:    toolDenied,
+		McpSse:        mcpSse,
+		McpPost:       mcpPost,
+		Reload:        reload,
+		ReloadChanges: reloadChanges,
 	}
 	return instrumentation, nil
 }
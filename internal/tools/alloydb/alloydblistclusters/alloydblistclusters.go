@@ -51,13 +51,21 @@ type Config struct {
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 // ToolConfigKind returns the kind of the tool.
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+	}
+}
+
 // Initialize initializes the tool from the configuration.
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
@@ -62,12 +62,20 @@ type Config struct {
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -22,6 +22,7 @@ import (
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
 )
 
@@ -118,3 +119,23 @@ func InitializeDatasetParameters(
 
 	return projectParam, datasetParam
 }
+
+// ReadJob waits for a query job to complete and returns an iterator over its
+// results. Progress is reported to the client while waiting, and the job is
+// cancelled if the request is cancelled before the job completes.
+func ReadJob(ctx context.Context, job *bigqueryapi.Job) (*bigqueryapi.RowIterator, error) {
+	util.ReportProgress(ctx, 1, 2, fmt.Sprintf("BigQuery job %s is running", job.ID()))
+	status, err := job.Wait(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			// best effort, the request is no longer interested in the results
+			_ = job.Cancel(context.WithoutCancel(ctx))
+		}
+		return nil, fmt.Errorf("unable to wait for query job: %w", err)
+	}
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("query job failed: %w", err)
+	}
+	util.ReportProgress(ctx, 2, 2, fmt.Sprintf("BigQuery job %s is done", job.ID()))
+	return job.Read(ctx)
+}
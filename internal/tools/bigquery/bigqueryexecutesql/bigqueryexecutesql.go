@@ -16,9 +16,11 @@ package bigqueryexecutesql
 
 import (
 	"context"
+	dbsql "database/sql"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
@@ -64,20 +66,34 @@ var _ compatibleSource = &bigqueryds.Source{}
 var compatibleSources = [...]string{bigqueryds.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string             `yaml:"name" validate:"required"`
+	Kind         string             `yaml:"kind" validate:"required"`
+	Source       string             `yaml:"source" validate:"required"`
+	Description  string             `yaml:"description" validate:"required"`
+	Timeout      string             `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired []string           `yaml:"authRequired"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		PageSize:     cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -129,13 +145,23 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		"If set to true, the query will be validated and information about the execution will be returned "+
 			"without running the query. Defaults to false.",
 	)
-	parameters := tools.Parameters{sqlParameter, dryRunParameter}
+	parameters := tools.WithPageTokenParameter(tools.Parameters{sqlParameter, dryRunParameter}, cfg.PageSize)
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:             cfg.Name,
 		Kind:             kind,
+		Timeout:          timeout,
+		PageSize:         cfg.PageSize,
+		ResultFormat:     cfg.ResultFormat,
 		Parameters:       parameters,
 		AuthRequired:     cfg.AuthRequired,
 		UseClientOAuth:   s.UseClientAuthorization(),
@@ -156,11 +182,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name           string           `yaml:"name"`
-	Kind           string           `yaml:"kind"`
-	AuthRequired   []string         `yaml:"authRequired"`
-	UseClientOAuth bool             `yaml:"useClientOAuth"`
-	Parameters     tools.Parameters `yaml:"parameters"`
+	sources.ResultLimits
+
+	Name           string             `yaml:"name"`
+	Kind           string             `yaml:"kind"`
+	AuthRequired   []string           `yaml:"authRequired"`
+	Timeout        time.Duration      `yaml:"timeout"`
+	PageSize       int                `yaml:"pageSize"`
+	ResultFormat   tools.ResultFormat `yaml:"resultFormat"`
+	UseClientOAuth bool               `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters   `yaml:"parameters"`
 
 	Client           *bigqueryapi.Client
 	RestService      *bigqueryrestapi.Service
@@ -183,11 +214,14 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("unable to cast dry_run parameter %s", paramsMap["dry_run"])
 	}
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, sql)
+	if err != nil {
+		return nil, err
+	}
 
 	bqClient := t.Client
 	restService := t.RestService
 
-	var err error
 	// Initialize new client if using user OAuth token
 	if t.UseClientOAuth {
 		tokenStr, err := accessToken.ParseBearerToken()
@@ -299,8 +333,17 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return "Dry run was requested, but no job information was returned.", nil
 	}
 
-	query := bqClient.Query(sql)
+	pagedSQL, args := page.Apply(sql, nil, tools.BigQueryDialect)
+	query := bqClient.Query(pagedSQL)
+	for _, arg := range args {
+		if a, ok := arg.(dbsql.NamedArg); ok {
+			query.Parameters = append(query.Parameters, bigqueryapi.QueryParameter{Name: a.Name, Value: a.Value})
+		}
+	}
 	query.Location = bqClient.Location
+	// BigQuery cancels the job once the timeout elapses, as it keeps running
+	// when the context is cancelled.
+	query.JobTimeout = t.Timeout
 
 	query.ConnectionProperties = connProps
 
@@ -314,12 +357,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	// This block handles SELECT statements, which return a row set.
 	// We iterate through the results, convert each row into a map of
 	// column names to values, and return the collection of rows.
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page)
 	job, err := query.Run(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
-	it, err := job.Read(ctx)
+	it, err := bqutil.ReadJob(ctx, job)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read query results: %w", err)
 	}
@@ -336,11 +379,18 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		for key, value := range row {
 			vMap[key] = value
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading the pages of rows once the limits are exceeded
+			break
+		}
 	}
-	// If the query returned any rows, return them directly.
-	if len(out) > 0 {
-		return out, nil
+	// If the query returned any rows, or is paginated, return them directly.
+	if len(rc.Rows()) > 0 || rc.Truncated() || page.Size > 0 {
+		cols := make([]string, len(it.Schema))
+		for i, f := range it.Schema {
+			cols[i] = f.Name
+		}
+		return rc.WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols).Result(), nil
 	}
 
 	// This handles the standard case for a SELECT query that successfully
@@ -374,3 +424,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return t.UseClientOAuth
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
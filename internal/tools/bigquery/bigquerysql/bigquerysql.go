@@ -16,9 +16,11 @@ package bigquerysql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
@@ -62,23 +64,39 @@ var _ compatibleSource = &bigqueryds.Source{}
 var compatibleSources = [...]string{bigqueryds.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name               string             `yaml:"name" validate:"required"`
+	Kind               string             `yaml:"kind" validate:"required"`
+	Source             string             `yaml:"source" validate:"required"`
+	Description        string             `yaml:"description" validate:"required"`
+	Statement          string             `yaml:"statement" validate:"required"`
+	Timeout            string             `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:             cfg.Source,
+		Description:        cfg.Description,
+		AuthRequired:       cfg.AuthRequired,
+		Parameters:         cfg.Parameters,
+		TemplateParameters: cfg.TemplateParameters,
+		PageSize:           cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -92,17 +110,31 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	if err := tools.CheckPageSize(cfg.PageSize, cfg.Statement); err != nil {
+		return nil, err
+	}
+
+	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, tools.WithPageTokenParameter(cfg.Parameters, cfg.PageSize))
 	if err != nil {
 		return nil, err
 	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:               cfg.Name,
 		Kind:               kind,
+		Timeout:            timeout,
+		PageSize:           cfg.PageSize,
+		ResultFormat:       cfg.ResultFormat,
 		AuthRequired:       cfg.AuthRequired,
 		Parameters:         cfg.Parameters,
 		TemplateParameters: cfg.TemplateParameters,
@@ -124,13 +156,18 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	UseClientOAuth     bool             `yaml:"useClientOAuth"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	sources.ResultLimits
+
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Timeout            time.Duration      `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat"`
+	UseClientOAuth     bool               `yaml:"useClientOAuth"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
 
 	Statement       string
 	Client          *bigqueryapi.Client
@@ -151,6 +188,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, newStatement)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, p := range t.Parameters {
 		name := p.GetName()
 		value := paramsMap[name]
@@ -233,9 +275,13 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 	}
 
-	query := bqClient.Query(newStatement)
+	pagedStatement, highLevelParams := applyPage(page, newStatement, highLevelParams)
+	query := bqClient.Query(pagedStatement)
 	query.Parameters = highLevelParams
 	query.Location = bqClient.Location
+	// BigQuery cancels the job once the timeout elapses, as it keeps running
+	// when the context is cancelled.
+	query.JobTimeout = t.Timeout
 
 	connProps := []*bigqueryapi.ConnectionProperty{}
 	if t.SessionProvider != nil {
@@ -263,12 +309,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
-	it, err := job.Read(ctx)
+	it, err := bqutil.ReadJob(ctx, job)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read query results: %w", err)
 	}
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page)
 	for {
 		var row map[string]bigqueryapi.Value
 		err = it.Next(&row)
@@ -282,11 +328,18 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		for key, value := range row {
 			vMap[key] = value
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading the pages of rows once the limits are exceeded
+			break
+		}
 	}
-	// If the query returned any rows, return them directly.
-	if len(out) > 0 {
-		return out, nil
+	// If the query returned any rows, or is paginated, return them directly.
+	if len(rc.Rows()) > 0 || rc.Truncated() || page.Size > 0 {
+		cols := make([]string, len(it.Schema))
+		for i, f := range it.Schema {
+			cols[i] = f.Name
+		}
+		return rc.WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols).Result(), nil
 	}
 
 	// This handles the standard case for a SELECT query that successfully
@@ -301,6 +354,29 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return "Query executed successfully and returned no content.", nil
 }
 
+// applyPage restricts the statement to the page, and adds the parameters that
+// bind the keys of the page to params. The keys are bound to positional
+// parameters if the statement has any, as BigQuery does not allow a query to
+// mix named and positional parameters.
+func applyPage(page tools.Page, statement string, params []bigqueryapi.QueryParameter) (string, []bigqueryapi.QueryParameter) {
+	d := tools.BigQueryDialect
+	for _, p := range params {
+		if p.Name == "" {
+			d.Bind = tools.PositionalArg
+			break
+		}
+	}
+	query, args := page.Apply(statement, nil, d)
+	for _, arg := range args {
+		if a, ok := arg.(sql.NamedArg); ok {
+			params = append(params, bigqueryapi.QueryParameter{Name: a.Name, Value: a.Value})
+		} else {
+			params = append(params, bigqueryapi.QueryParameter{Value: arg})
+		}
+	}
+	return query, params
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
@@ -320,3 +396,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return t.UseClientOAuth
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -101,7 +101,17 @@ func (c Config) ToolConfigKind() string {
 	return kind
 }
 
-var _ tools.ToolConfig = Config{}
+func (c Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:             c.Source,
+		Description:        c.Description,
+		AuthRequired:       c.AuthRequired,
+		Parameters:         c.Parameters,
+		TemplateParameters: c.TemplateParameters,
+	}
+}
+
+var _ tools.SourceToolConfig = Config{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
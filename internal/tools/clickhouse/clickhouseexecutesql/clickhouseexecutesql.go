@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -47,19 +48,33 @@ func newExecuteSQLConfig(ctx context.Context, name string, decoder *yaml.Decoder
 }
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string             `yaml:"name" validate:"required"`
+	Kind         string             `yaml:"kind" validate:"required"`
+	Source       string             `yaml:"source" validate:"required"`
+	Description  string             `yaml:"description" validate:"required"`
+	Timeout      string             `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired []string           `yaml:"authRequired"`
 }
 
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return executeSQLKind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		PageSize:     cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -72,13 +87,23 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParameter := tools.NewStringParameter("sql", "The SQL statement to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	parameters := tools.WithPageTokenParameter(tools.Parameters{sqlParameter}, cfg.PageSize)
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	t := ExecuteSQLTool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:         cfg.Name,
 		Kind:         executeSQLKind,
+		Timeout:      timeout,
+		PageSize:     cfg.PageSize,
+		ResultFormat: cfg.ResultFormat,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
 		Pool:         s.ClickHousePool(),
@@ -91,10 +116,15 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = ExecuteSQLTool{}
 
 type ExecuteSQLTool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	sources.ResultLimits
+
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Timeout      time.Duration      `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
 
 	Pool        *sql.DB
 	manifest    tools.Manifest
@@ -107,8 +137,17 @@ func (t ExecuteSQLTool) Invoke(ctx context.Context, params tools.ParamValues, to
 	if !ok {
 		return nil, fmt.Errorf("unable to cast sql parameter %s", paramsMap["sql"])
 	}
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, sql)
+	if err != nil {
+		return nil, err
+	}
 
-	results, err := t.Pool.QueryContext(ctx, sql)
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(sql, nil, tools.ClickHouseDialect)
+	results, err := t.Pool.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -131,7 +170,7 @@ func (t ExecuteSQLTool) Invoke(ctx context.Context, params tools.ParamValues, to
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
@@ -156,14 +195,18 @@ func (t ExecuteSQLTool) Invoke(ctx context.Context, params tools.ParamValues, to
 				vMap[name] = rawValues[i]
 			}
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading rows instead of draining them
+			cancel()
+			break
+		}
 	}
 
-	if err := results.Err(); err != nil {
+	if err := results.Err(); err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("errors encountered by results.Scan: %w", err)
 	}
 
-	return out, nil
+	return rc.Result(), nil
 }
 
 func (t ExecuteSQLTool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -185,3 +228,8 @@ func (t ExecuteSQLTool) Authorized(verifiedAuthServices []string) bool {
 func (t ExecuteSQLTool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t ExecuteSQLTool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -51,16 +52,26 @@ type Config struct {
 	Kind         string           `yaml:"kind" validate:"required"`
 	Source       string           `yaml:"source" validate:"required"`
 	Description  string           `yaml:"description" validate:"required"`
+	Timeout      string           `yaml:"timeout"`
 	AuthRequired []string         `yaml:"authRequired"`
 	Parameters   tools.Parameters `yaml:"parameters"`
 }
 
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return listDatabasesKind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		Parameters:   cfg.Parameters,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -75,12 +86,18 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, _ := tools.ProcessParameters(nil, cfg.Parameters)
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	t := Tool{
 		Name:         cfg.Name,
 		Kind:         listDatabasesKind,
 		Parameters:   cfg.Parameters,
 		AllParams:    allParameters,
 		AuthRequired: cfg.AuthRequired,
+		Timeout:      timeout,
 		Pool:         s.ClickHousePool(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -94,6 +111,7 @@ type Tool struct {
 	Name         string           `yaml:"name"`
 	Kind         string           `yaml:"kind"`
 	AuthRequired []string         `yaml:"authRequired"`
+	Timeout      time.Duration    `yaml:"timeout"`
 	Parameters   tools.Parameters `yaml:"parameters"`
 	AllParams    tools.Parameters `yaml:"allParams"`
 
@@ -150,3 +168,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -52,16 +53,26 @@ type Config struct {
 	Kind         string           `yaml:"kind" validate:"required"`
 	Source       string           `yaml:"source" validate:"required"`
 	Description  string           `yaml:"description" validate:"required"`
+	Timeout      string           `yaml:"timeout"`
 	AuthRequired []string         `yaml:"authRequired"`
 	Parameters   tools.Parameters `yaml:"parameters"`
 }
 
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return listTablesKind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		Parameters:   cfg.Parameters,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -79,12 +90,18 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, _ := tools.ProcessParameters(nil, parameters)
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	t := Tool{
 		Name:         cfg.Name,
 		Kind:         listTablesKind,
 		Parameters:   parameters,
 		AllParams:    allParameters,
 		AuthRequired: cfg.AuthRequired,
+		Timeout:      timeout,
 		Pool:         s.ClickHousePool(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -98,6 +115,7 @@ type Tool struct {
 	Name         string           `yaml:"name"`
 	Kind         string           `yaml:"kind"`
 	AuthRequired []string         `yaml:"authRequired"`
+	Timeout      time.Duration    `yaml:"timeout"`
 	Parameters   tools.Parameters `yaml:"parameters"`
 	AllParams    tools.Parameters `yaml:"allParams"`
 
@@ -161,3 +179,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -76,6 +76,27 @@ func TestParseFromYamlClickHouseListTables(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with timeout",
+			in: `
+			tools:
+				example_tool:
+					kind: clickhouse-list-tables
+					source: my-instance
+					description: some description
+					timeout: 30s
+			`,
+			want: server.ToolConfigs{
+				"example_tool": Config{
+					Name:         "example_tool",
+					Kind:         "clickhouse-list-tables",
+					Source:       "my-instance",
+					Description:  "some description",
+					Timeout:      "30s",
+					AuthRequired: []string{},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
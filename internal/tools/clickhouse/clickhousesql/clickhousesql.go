@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -47,22 +48,38 @@ func newSQLConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tool
 }
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name               string             `yaml:"name" validate:"required"`
+	Kind               string             `yaml:"kind" validate:"required"`
+	Source             string             `yaml:"source" validate:"required"`
+	Description        string             `yaml:"description" validate:"required"`
+	Statement          string             `yaml:"statement" validate:"required"`
+	Timeout            string             `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
 }
 
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return sqlKind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:             cfg.Source,
+		Description:        cfg.Description,
+		AuthRequired:       cfg.AuthRequired,
+		Parameters:         cfg.Parameters,
+		TemplateParameters: cfg.TemplateParameters,
+		PageSize:           cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -74,12 +91,26 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", sqlKind, compatibleSources)
 	}
 
-	allParameters, paramManifest, _ := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	if err := tools.CheckPageSize(cfg.PageSize, cfg.Statement); err != nil {
+		return nil, err
+	}
+
+	allParameters, paramManifest, _ := tools.ProcessParameters(cfg.TemplateParameters, tools.WithPageTokenParameter(cfg.Parameters, cfg.PageSize))
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:               cfg.Name,
 		Kind:               sqlKind,
+		Timeout:            timeout,
+		PageSize:           cfg.PageSize,
+		ResultFormat:       cfg.ResultFormat,
 		Parameters:         cfg.Parameters,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
@@ -95,12 +126,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	sources.ResultLimits
+
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Timeout            time.Duration      `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
 
 	Pool        *sql.DB
 	Statement   string
@@ -115,13 +151,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, token tools.
 		return nil, fmt.Errorf("unable to extract template params: %w", err)
 	}
 
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, newStatement)
+	if err != nil {
+		return nil, err
+	}
+
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params: %w", err)
 	}
 
 	sliceParams := newParams.AsSlice()
-	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(newStatement, sliceParams, tools.ClickHouseDialect)
+	results, err := t.Pool.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -142,7 +188,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, token tools.
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
@@ -166,19 +212,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, token tools.
 				vMap[name] = rawValues[i]
 			}
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading rows instead of draining them
+			cancel()
+			break
+		}
 	}
 
 	err = results.Close()
-	if err != nil {
+	if err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("unable to close rows: %w", err)
 	}
 
-	if err := results.Err(); err != nil {
+	if err := results.Err(); err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("errors encountered by results.Scan: %w", err)
 	}
 
-	return out, nil
+	return rc.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -200,3 +250,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqladmin"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 const kind string = "cloud-sql-wait-for-operation"
@@ -103,13 +104,21 @@ type Config struct {
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 // ToolConfigKind returns the kind of the tool.
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+	}
+}
+
 // Initialize initializes the tool from the configuration.
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
@@ -235,7 +244,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		default:
 		}
 
-		op, err := service.Operations.Get(project, operationID).Do()
+		op, err := service.Operations.Get(project, operationID).Context(ctx).Do()
 		if err != nil {
 			fmt.Printf("error getting operation: %s, retrying in %v\n", err, delay)
 		} else {
@@ -267,8 +276,13 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			}
 			fmt.Printf("Operation not complete, retrying in %v\n", delay)
 		}
+		util.ReportProgress(ctx, float64(retries+1), float64(maxRetries), fmt.Sprintf("Operation not complete, retrying in %v", delay))
 
-		time.Sleep(delay)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for operation: %w", ctx.Err())
+		case <-time.After(delay):
+		}
 		delay = time.Duration(float64(delay) * multiplier)
 		if delay > maxDelay {
 			delay = maxDelay
@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -51,19 +52,33 @@ var _ compatibleSource = &firebird.Source{}
 var compatibleSources = [...]string{firebird.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string             `yaml:"name" validate:"required"`
+	Kind         string             `yaml:"kind" validate:"required"`
+	Source       string             `yaml:"source" validate:"required"`
+	Description  string             `yaml:"description" validate:"required"`
+	Timeout      string             `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired []string           `yaml:"authRequired"`
 }
 
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		PageSize:     cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -76,14 +91,24 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	parameters := tools.WithPageTokenParameter(tools.Parameters{sqlParameter}, cfg.PageSize)
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	t := &Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:         cfg.Name,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
+		Timeout:      timeout,
+		PageSize:     cfg.PageSize,
+		ResultFormat: cfg.ResultFormat,
 		Db:           s.FirebirdDB(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -94,10 +119,15 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = &Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	sources.ResultLimits
+
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Timeout      time.Duration      `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
 
 	Db          *sql.DB
 	manifest    tools.Manifest
@@ -110,6 +140,10 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, sql)
+	if err != nil {
+		return nil, err
+	}
 
 	// Log the query executed for debugging.
 	logger, err := util.LoggerFromContext(ctx)
@@ -118,7 +152,12 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
 
-	rows, err := t.Db.QueryContext(ctx, sql)
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(sql, nil, tools.FirebirdDialect)
+	rows, err := t.Db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -126,7 +165,7 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	cols, err := rows.Columns()
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	if err == nil && len(cols) > 0 {
 		values := make([]any, len(cols))
 		scanArgs := make([]any, len(values))
@@ -148,18 +187,22 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 					vMap[colName] = values[i]
 				}
 			}
-			out = append(out, vMap)
+			if !rc.Add(vMap) {
+				// stop reading rows instead of draining them
+				cancel()
+				break
+			}
 		}
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := rows.Err(); err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
 	// In most cases, DML/DDL statements like INSERT, UPDATE, CREATE, etc. might return no rows
 	// However, it is also possible that this was a query that was expected to return rows
 	// but returned none, a case that we cannot distinguish here.
-	return out, nil
+	return rc.Result(), nil
 }
 
 func (t *Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -181,3 +224,8 @@ func (t *Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -52,23 +53,39 @@ var _ compatibleSource = &firebird.Source{}
 var compatibleSources = [...]string{firebird.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name               string             `yaml:"name" validate:"required"`
+	Kind               string             `yaml:"kind" validate:"required"`
+	Source             string             `yaml:"source" validate:"required"`
+	Description        string             `yaml:"description" validate:"required"`
+	Statement          string             `yaml:"statement" validate:"required"`
+	Timeout            string             `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:             cfg.Source,
+		Description:        cfg.Description,
+		AuthRequired:       cfg.AuthRequired,
+		Parameters:         cfg.Parameters,
+		TemplateParameters: cfg.TemplateParameters,
+		PageSize:           cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -82,17 +99,31 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	if err := tools.CheckPageSize(cfg.PageSize, cfg.Statement); err != nil {
+		return nil, err
+	}
+
+	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, tools.WithPageTokenParameter(cfg.Parameters, cfg.PageSize))
 	if err != nil {
 		return nil, err
 	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := &Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:               cfg.Name,
 		Kind:               kind,
+		Timeout:            timeout,
+		PageSize:           cfg.PageSize,
+		ResultFormat:       cfg.ResultFormat,
 		Parameters:         cfg.Parameters,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
@@ -109,12 +140,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = &Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	sources.ResultLimits
+
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Timeout            time.Duration      `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
 
 	Db          *sql.DB
 	Statement   string
@@ -129,6 +165,11 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to extract template params: %w", err)
 	}
 
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, statement)
+	if err != nil {
+		return nil, err
+	}
+
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params: %w", err)
@@ -147,7 +188,12 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 	}
 
-	rows, err := t.Db.QueryContext(ctx, statement, namedArgs...)
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(statement, namedArgs, tools.FirebirdDialect)
+	rows, err := t.Db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -164,7 +210,7 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		scanArgs[i] = &values[i]
 	}
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	for rows.Next() {
 
 		err = rows.Scan(scanArgs...)
@@ -180,17 +226,21 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				vMap[col] = values[i]
 			}
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading rows instead of draining them
+			cancel()
+			break
+		}
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := rows.Err(); err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
 	// In most cases, DML/DDL statements like INSERT, UPDATE, CREATE, etc. might return no rows
 	// However, it is also possible that this was a query that was expected to return rows
 	// but returned none, a case that we cannot distinguish here.
-	return out, nil
+	return rc.Result(), nil
 }
 
 func (t *Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -212,3 +262,8 @@ func (t *Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
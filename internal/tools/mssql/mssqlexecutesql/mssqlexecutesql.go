@@ -18,12 +18,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmssql"
 	"github.com/googleapis/genai-toolbox/internal/sources/mssql"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlclassifier"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
@@ -45,6 +47,7 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	MSSQLDB() *sql.DB
+	MSSQLReadOnly() bool
 }
 
 // validate compatible sources are still compatible
@@ -54,20 +57,35 @@ var _ compatibleSource = &mssql.Source{}
 var compatibleSources = [...]string{cloudsqlmssql.SourceKind, mssql.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string             `yaml:"name" validate:"required"`
+	Kind         string             `yaml:"kind" validate:"required"`
+	Source       string             `yaml:"source" validate:"required"`
+	Description  string             `yaml:"description" validate:"required"`
+	ReadOnly     bool               `yaml:"readOnly"`
+	Timeout      string             `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired []string           `yaml:"authRequired"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		PageSize:     cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -82,16 +100,27 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	parameters := tools.WithPageTokenParameter(tools.Parameters{sqlParameter}, cfg.PageSize)
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:         cfg.Name,
 		Kind:         kind,
+		Timeout:      timeout,
+		PageSize:     cfg.PageSize,
+		ResultFormat: cfg.ResultFormat,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
+		ReadOnly:     cfg.ReadOnly || s.MSSQLReadOnly(),
 		Pool:         s.MSSQLDB(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -103,10 +132,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	sources.ResultLimits
+
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Timeout      time.Duration      `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
+	ReadOnly     bool               `yaml:"readOnly"`
 
 	Pool        *sql.DB
 	manifest    tools.Manifest
@@ -120,6 +155,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
 
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, sql)
+	if err != nil {
+		return nil, err
+	}
+
 	// Log the query executed for debugging.
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -127,7 +167,20 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
 
-	results, err := t.Pool.QueryContext(ctx, sql)
+	if t.ReadOnly {
+		// SQL Server has no read-only transactions, so read-only tools rely
+		// on the classifier.
+		if err := sqlclassifier.CheckReadOnly(sql); err != nil {
+			return nil, err
+		}
+	}
+
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(sql, nil, tools.SQLServerDialect)
+	results, err := t.Pool.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -138,7 +191,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	// We proceed, and results.Err() will catch actual query execution errors.
 	// 'out' will remain nil if cols is empty or err is not nil here.
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	if err == nil && len(cols) > 0 {
 		// create an array of values for each column, which can be re-used to scan each row
 		rawValues := make([]any, len(cols))
@@ -156,17 +209,21 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			for i, name := range cols {
 				vMap[name] = rawValues[i]
 			}
-			out = append(out, vMap)
+			if !rc.Add(vMap) {
+				// stop reading rows instead of draining them
+				cancel()
+				break
+			}
 		}
 	}
 
 	// Check for errors from iterating over rows or from the query execution itself.
 	// results.Close() is handled by defer.
-	if err := results.Err(); err != nil {
+	if err := results.Err(); err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("errors encountered during query execution or row processing: %w", err)
 	}
 
-	return out, nil
+	return rc.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -188,3 +245,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -54,23 +55,40 @@ var _ compatibleSource = &mssql.Source{}
 var compatibleSources = [...]string{cloudsqlmssql.SourceKind, mssql.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name               string             `yaml:"name" validate:"required"`
+	Kind               string             `yaml:"kind" validate:"required"`
+	Source             string             `yaml:"source" validate:"required"`
+	Description        string             `yaml:"description" validate:"required"`
+	Statement          string             `yaml:"statement" validate:"required"`
+	Timeout            string             `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	OutputSchema       tools.Parameters   `yaml:"outputSchema"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:             cfg.Source,
+		Description:        cfg.Description,
+		AuthRequired:       cfg.AuthRequired,
+		Parameters:         cfg.Parameters,
+		TemplateParameters: cfg.TemplateParameters,
+		PageSize:           cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -84,17 +102,35 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	if err := tools.CheckPageSize(cfg.PageSize, cfg.Statement); err != nil {
+		return nil, err
+	}
+
+	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, tools.WithPageTokenParameter(cfg.Parameters, cfg.PageSize))
 	if err != nil {
 		return nil, err
 	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
+	mcpManifest.OutputSchema, err = tools.GetRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
 
 	// finish tool setup
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:               cfg.Name,
 		Kind:               kind,
+		Timeout:            timeout,
+		PageSize:           cfg.PageSize,
+		ResultFormat:       cfg.ResultFormat,
 		Parameters:         cfg.Parameters,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
@@ -111,12 +147,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	sources.ResultLimits
+
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Timeout            time.Duration      `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
 
 	Db          *sql.DB
 	Statement   string
@@ -131,6 +172,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, newStatement)
+	if err != nil {
+		return nil, err
+	}
+
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
@@ -149,7 +195,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 	}
 
-	rows, err := t.Db.QueryContext(ctx, newStatement, namedArgs...)
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(newStatement, namedArgs, tools.SQLServerDialect)
+	rows, err := t.Db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -166,7 +217,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		values[i] = &rawValues[i]
 	}
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	for rows.Next() {
 		err = rows.Scan(values...)
 		if err != nil {
@@ -176,19 +227,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		for i, name := range cols {
 			vMap[name] = rawValues[i]
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading rows instead of draining them
+			cancel()
+			break
+		}
 	}
 	err = rows.Close()
-	if err != nil {
+	if err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("unable to close rows: %w", err)
 	}
 
 	// Check if error occurred during iteration
-	if err := rows.Err(); err != nil {
+	if err := rows.Err(); err != nil && !rc.Truncated() {
 		return nil, err
 	}
 
-	return out, nil
+	return rc.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -210,3 +265,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
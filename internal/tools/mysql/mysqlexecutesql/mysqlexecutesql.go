@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -25,6 +26,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlclassifier"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
@@ -46,6 +48,7 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	MySQLPool() *sql.DB
+	MySQLReadOnly() bool
 }
 
 // validate compatible sources are still compatible
@@ -55,20 +58,35 @@ var _ compatibleSource = &mysql.Source{}
 var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string             `yaml:"name" validate:"required"`
+	Kind         string             `yaml:"kind" validate:"required"`
+	Source       string             `yaml:"source" validate:"required"`
+	Description  string             `yaml:"description" validate:"required"`
+	ReadOnly     bool               `yaml:"readOnly"`
+	Timeout      string             `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired []string           `yaml:"authRequired"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		PageSize:     cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -83,16 +101,27 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	parameters := tools.WithPageTokenParameter(tools.Parameters{sqlParameter}, cfg.PageSize)
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:         cfg.Name,
 		Kind:         kind,
+		Timeout:      timeout,
+		PageSize:     cfg.PageSize,
+		ResultFormat: cfg.ResultFormat,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
+		ReadOnly:     cfg.ReadOnly || s.MySQLReadOnly(),
 		Pool:         s.MySQLPool(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -104,10 +133,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	sources.ResultLimits
+
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Timeout      time.Duration      `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
+	ReadOnly     bool               `yaml:"readOnly"`
 
 	Pool        *sql.DB
 	manifest    tools.Manifest
@@ -121,6 +156,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
 
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, sql)
+	if err != nil {
+		return nil, err
+	}
+
 	// Log the query executed for debugging.
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -128,7 +168,18 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
 
-	results, err := t.Pool.QueryContext(ctx, sql)
+	q, done, err := t.querier(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(sql, nil, tools.MySQLDialect)
+	results, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -151,7 +202,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
@@ -170,14 +221,39 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading rows instead of draining them
+			cancel()
+			break
+		}
 	}
 
-	if err := results.Err(); err != nil {
+	if err := results.Err(); err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
 	}
 
-	return out, nil
+	return rc.Result(), nil
+}
+
+// querier is implemented by both pools and transactions.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// querier returns the querier to run the query with. Read-only tools run
+// queries in a read-only transaction, which is rolled back by done.
+func (t Tool) querier(ctx context.Context, query string) (q querier, done func(), err error) {
+	if !t.ReadOnly {
+		return t.Pool, func() {}, nil
+	}
+	if err := sqlclassifier.CheckReadOnly(query); err != nil {
+		return nil, nil, err
+	}
+	tx, err := t.Pool.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to begin read-only transaction: %w", err)
+	}
+	return tx, func() { _ = tx.Rollback() }, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -199,3 +275,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -54,23 +55,40 @@ var _ compatibleSource = &mysql.Source{}
 var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name               string             `yaml:"name" validate:"required"`
+	Kind               string             `yaml:"kind" validate:"required"`
+	Source             string             `yaml:"source" validate:"required"`
+	Description        string             `yaml:"description" validate:"required"`
+	Statement          string             `yaml:"statement" validate:"required"`
+	Timeout            string             `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	OutputSchema       tools.Parameters   `yaml:"outputSchema"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:             cfg.Source,
+		Description:        cfg.Description,
+		AuthRequired:       cfg.AuthRequired,
+		Parameters:         cfg.Parameters,
+		TemplateParameters: cfg.TemplateParameters,
+		PageSize:           cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -84,17 +102,35 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	if err := tools.CheckPageSize(cfg.PageSize, cfg.Statement); err != nil {
+		return nil, err
+	}
+
+	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, tools.WithPageTokenParameter(cfg.Parameters, cfg.PageSize))
 	if err != nil {
 		return nil, err
 	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
+	mcpManifest.OutputSchema, err = tools.GetRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
 
 	// finish tool setup
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:               cfg.Name,
 		Kind:               kind,
+		Timeout:            timeout,
+		PageSize:           cfg.PageSize,
+		ResultFormat:       cfg.ResultFormat,
 		Parameters:         cfg.Parameters,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
@@ -111,12 +147,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	sources.ResultLimits
+
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Timeout            time.Duration      `yaml:"timeout"`
+	PageSize           int                `yaml:"pageSize"`
+	ResultFormat       tools.ResultFormat `yaml:"resultFormat"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
 
 	Pool        *sql.DB
 	Statement   string
@@ -131,13 +172,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, newStatement)
+	if err != nil {
+		return nil, err
+	}
+
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
 	}
 
 	sliceParams := newParams.AsSlice()
-	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
+	// cancel the query once the result limits are exceeded
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query, args := page.Apply(newStatement, sliceParams, tools.MySQLDialect)
+	results, err := t.Pool.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -160,7 +211,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
+	rc := tools.NewRowCollector(t.ResultLimits).WithPage(page).WithFormat(tools.GetResultFormat(ctx, t.ResultFormat), cols)
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
@@ -179,14 +230,18 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			// stop reading rows instead of draining them
+			cancel()
+			break
+		}
 	}
 
-	if err := results.Err(); err != nil {
+	if err := results.Err(); err != nil && !rc.Truncated() {
 		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
 	}
 
-	return out, nil
+	return rc.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -208,3 +263,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
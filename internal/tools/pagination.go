@@ -0,0 +1,569 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlclassifier"
+)
+
+// PageTokenParameter is the name of the parameter of paginated tools that
+// takes the token of the page to return.
+const PageTokenParameter = "pageToken"
+
+// DefaultPageTokenTTL is how long page tokens are valid for by default.
+const DefaultPageTokenTTL = time.Hour
+
+// ErrInvalidPageToken is returned when a page token was not issued by the
+// server, was issued for another statement, other parameters or another
+// caller, or has expired.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageTokenSigner signs and verifies the page tokens issued by a server.
+type PageTokenSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewPageTokenSigner creates a PageTokenSigner with the given key, or with a
+// random key if it is empty, in which case page tokens are only valid on the
+// server that issued them, until it restarts. Page tokens expire after ttl,
+// or DefaultPageTokenTTL if it is 0.
+func NewPageTokenSigner(key []byte, ttl time.Duration) (PageTokenSigner, error) {
+	if ttl < 0 {
+		return PageTokenSigner{}, fmt.Errorf("invalid page token ttl %s: must not be negative", ttl)
+	}
+	if ttl == 0 {
+		ttl = DefaultPageTokenTTL
+	}
+	if len(key) == 0 {
+		key = make([]byte, sha256.Size)
+		if _, err := rand.Read(key); err != nil {
+			return PageTokenSigner{}, fmt.Errorf("unable to generate page token key: %w", err)
+		}
+	}
+	return PageTokenSigner{key: slices.Clone(key), ttl: ttl}, nil
+}
+
+func (s PageTokenSigner) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+type pageTokenSignerKey struct{}
+
+// WithPageTokenSigner returns a context with the signer of the page tokens of
+// tool invocations.
+func WithPageTokenSigner(ctx context.Context, s PageTokenSigner) context.Context {
+	return context.WithValue(ctx, pageTokenSignerKey{}, s)
+}
+
+type pageTokenCallerKey struct{}
+
+// WithPageTokenCaller returns a context with the identity of the caller of a
+// tool, from the claims of its verified auth services. Page tokens are only
+// valid for the caller they were issued to.
+func WithPageTokenCaller(ctx context.Context, claimsFromAuth map[string]map[string]any) context.Context {
+	// the claims that identify the caller, which unlike the expiry of the ID
+	// token do not change when it is refreshed
+	identity := make(map[string][]any, len(claimsFromAuth))
+	for name, claims := range claimsFromAuth {
+		identity[name] = []any{claims["iss"], claims["sub"], claims["email"]}
+	}
+	b, _ := json.Marshal(identity)
+	return context.WithValue(ctx, pageTokenCallerKey{}, string(b))
+}
+
+// WithPageTokenParameter returns the parameters of a tool, along with the
+// pageToken parameter if the tool is paginated.
+func WithPageTokenParameter(params Parameters, pageSize int) Parameters {
+	if pageSize <= 0 {
+		return params
+	}
+	desc := "Token of the page of rows to return, from the nextPageToken of the previous page. Leave empty to get the first page."
+	return append(slices.Clone(params), NewStringParameterWithRequired(PageTokenParameter, desc, false))
+}
+
+// CheckPageSize checks that the statement of a tool with a page size can be
+// paginated.
+func CheckPageSize(pageSize int, statement string) error {
+	if pageSize <= 0 {
+		return nil
+	}
+	ok, err := sqlclassifier.Pageable(statement)
+	if err != nil {
+		return fmt.Errorf("invalid pageSize: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("invalid pageSize: only a single SELECT statement without a LIMIT, OFFSET or FETCH clause can be paginated")
+	}
+	return nil
+}
+
+// PageClause is the clause that restricts a query to a page, in the dialect
+// of a database.
+type PageClause string
+
+const (
+	// LimitOffset is the page clause of PostgreSQL, MySQL, SQLite, BigQuery,
+	// ClickHouse and Spanner.
+	LimitOffset PageClause = "LIMIT %[1]d OFFSET %[2]d"
+	// OffsetFetch is the page clause of SQL Server, Oracle, Trino and Firebird.
+	OffsetFetch PageClause = "OFFSET %[2]d ROWS FETCH NEXT %[1]d ROWS ONLY"
+)
+
+// NullOrder is where a database sorts NULL values.
+type NullOrder int
+
+const (
+	// NullsLow sorts NULL values before other values in ascending order, and
+	// after them in descending order.
+	NullsLow NullOrder = iota
+	// NullsHigh sorts NULL values after other values in ascending order, and
+	// before them in descending order.
+	NullsHigh
+	// NullsLast sorts NULL values after other values in either order.
+	NullsLast
+)
+
+// PageDialect is the SQL dialect of a database that pages are read from.
+type PageDialect struct {
+	// Clause is the clause that restricts a query to a page.
+	Clause PageClause
+	// Bind returns the placeholder of the nth argument of a query, counting
+	// from 1, along with the argument that binds a value to it.
+	Bind func(n int, v any) (placeholder string, arg any)
+	// Nulls is where the database sorts NULL values.
+	Nulls NullOrder
+	// NoNestedWith is set if the database does not allow WITH clauses in
+	// subqueries.
+	NoNestedWith bool
+}
+
+// PositionalArg binds a value to a `?` placeholder.
+func PositionalArg(_ int, v any) (string, any) {
+	return "?", v
+}
+
+// DollarArg binds a value to a `$n` placeholder.
+func DollarArg(n int, v any) (string, any) {
+	return fmt.Sprintf("$%d", n), v
+}
+
+// ColonArg binds a value to a `:n` placeholder.
+func ColonArg(n int, v any) (string, any) {
+	return fmt.Sprintf(":%d", n), v
+}
+
+// NamedArg binds a value to an `@page_key_n` placeholder, with a
+// sql.NamedArg.
+func NamedArg(n int, v any) (string, any) {
+	name := fmt.Sprintf("page_key_%d", n)
+	return "@" + name, sql.Named(name, v)
+}
+
+// spannerPostgresArg binds a value to a `$n` placeholder, with a sql.NamedArg
+// named `pn` as the Spanner client expects.
+func spannerPostgresArg(n int, v any) (string, any) {
+	return fmt.Sprintf("$%d", n), sql.Named(fmt.Sprintf("p%d", n), v)
+}
+
+var (
+	// PostgresDialect is the page dialect of PostgreSQL.
+	PostgresDialect = PageDialect{Clause: LimitOffset, Bind: DollarArg, Nulls: NullsHigh}
+	// MySQLDialect is the page dialect of MySQL.
+	MySQLDialect = PageDialect{Clause: LimitOffset, Bind: PositionalArg, Nulls: NullsLow}
+	// SQLiteDialect is the page dialect of SQLite.
+	SQLiteDialect = PageDialect{Clause: LimitOffset, Bind: PositionalArg, Nulls: NullsLow}
+	// SQLServerDialect is the page dialect of SQL Server.
+	SQLServerDialect = PageDialect{Clause: OffsetFetch, Bind: NamedArg, Nulls: NullsLow, NoNestedWith: true}
+	// OracleDialect is the page dialect of Oracle.
+	OracleDialect = PageDialect{Clause: OffsetFetch, Bind: ColonArg, Nulls: NullsHigh}
+	// BigQueryDialect is the page dialect of BigQuery.
+	BigQueryDialect = PageDialect{Clause: LimitOffset, Bind: NamedArg, Nulls: NullsLow}
+	// ClickHouseDialect is the page dialect of ClickHouse.
+	ClickHouseDialect = PageDialect{Clause: LimitOffset, Bind: PositionalArg, Nulls: NullsLast}
+	// SpannerDialect is the page dialect of Spanner with GoogleSQL.
+	SpannerDialect = PageDialect{Clause: LimitOffset, Bind: NamedArg, Nulls: NullsLow}
+	// SpannerPostgresDialect is the page dialect of Spanner with PostgreSQL.
+	SpannerPostgresDialect = PageDialect{Clause: LimitOffset, Bind: spannerPostgresArg, Nulls: NullsHigh}
+	// TrinoDialect is the page dialect of Trino.
+	TrinoDialect = PageDialect{Clause: OffsetFetch, Bind: PositionalArg, Nulls: NullsLast}
+	// FirebirdDialect is the page dialect of Firebird.
+	FirebirdDialect = PageDialect{Clause: OffsetFetch, Bind: PositionalArg, Nulls: NullsLow}
+)
+
+// nullsAfter reports whether NULL values are sorted after other values in
+// the given order.
+func (d PageDialect) nullsAfter(desc bool) bool {
+	switch d.Nulls {
+	case NullsHigh:
+		return !desc
+	case NullsLow:
+		return desc
+	}
+	return true
+}
+
+// Page is a page of the rows of a query.
+//
+// Pages are read by keyset pagination when the ORDER BY clause of the query
+// only sorts by result columns given by their name, see
+// sqlclassifier.OrderBy: the page token holds the values of these columns in
+// the last row of the previous page, and the page is read from the rows that
+// follow them. Pages of other queries, and pages that follow a row with NULL
+// values or values shared with the next row, are read by skipping the rows
+// that precede the page instead.
+type Page struct {
+	// Size is the maximum number of rows of the page. Pagination is disabled
+	// if it is 0.
+	Size int
+	// Offset is the number of rows of the query that precede the page.
+	Offset int
+	// keys are the columns the rows of the query are sorted by, if the pages
+	// can be read by keyset pagination.
+	keys []sqlclassifier.OrderKey
+	// after are the values of the keys in the last row of the previous page,
+	// if the page is read by keyset pagination.
+	after []any
+	// binding is the hash of the statement and the parameters of the query,
+	// and of the caller.
+	binding []byte
+	signer  PageTokenSigner
+}
+
+// GetPage returns the page requested by the pageToken parameter of a tool
+// invocation. The page token must have been issued for the same tool,
+// statement, parameters and caller. A page size of 0 disables pagination.
+//
+// Only single SELECT statements are paginated, see sqlclassifier.Pageable.
+// Other statements are not paginated, except for statements without an ORDER
+// BY clause, which return an error.
+func GetPage(ctx context.Context, pageSize int, params ParamValues, name, statement string) (Page, error) {
+	if pageSize <= 0 {
+		return Page{}, nil
+	}
+	var token string
+	query := make(ParamValues, 0, len(params))
+	for _, p := range params {
+		if p.Name == PageTokenParameter {
+			token, _ = p.Value.(string)
+			continue
+		}
+		query = append(query, p)
+	}
+	ok, err := sqlclassifier.Pageable(statement)
+	if err != nil {
+		return Page{}, err
+	}
+	if !ok {
+		if token != "" {
+			return Page{}, ErrInvalidPageToken
+		}
+		return Page{}, nil
+	}
+	signer, _ := ctx.Value(pageTokenSignerKey{}).(PageTokenSigner)
+	if len(signer.key) == 0 {
+		return Page{}, fmt.Errorf("unable to paginate: no page token signer")
+	}
+	caller, _ := ctx.Value(pageTokenCallerKey{}).(string)
+	b, err := json.Marshal([]any{name, statement, query, caller})
+	if err != nil {
+		return Page{}, fmt.Errorf("unable to encode query of page: %w", err)
+	}
+	binding := sha256.Sum256(b)
+	page := Page{Size: pageSize, binding: binding[:], signer: signer}
+	if ordering, ok := sqlclassifier.OrderBy(statement); ok {
+		page.keys = ordering.Keys
+	}
+	if token == "" {
+		return page, nil
+	}
+
+	// the token is the offset of the page, the time it was issued at, the
+	// binding of the page and the values of the keys of the previous row if
+	// any, followed by their signature
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < 16+2*sha256.Size {
+		return Page{}, ErrInvalidPageToken
+	}
+	payload, sig := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	if !hmac.Equal(sig, signer.sign(payload)) || !hmac.Equal(payload[16:16+sha256.Size], page.binding) {
+		return Page{}, ErrInvalidPageToken
+	}
+	issued := time.Unix(int64(binary.BigEndian.Uint64(payload[8:16])), 0)
+	if time.Since(issued) > signer.ttl {
+		return Page{}, fmt.Errorf("%w: the token has expired", ErrInvalidPageToken)
+	}
+	offset := binary.BigEndian.Uint64(payload[:8])
+	if offset > uint64(maxPageOffset) {
+		return Page{}, ErrInvalidPageToken
+	}
+	page.Offset = int(offset)
+	if after := payload[16+sha256.Size:]; len(after) > 0 {
+		page.after, err = decodeKeyValues(after, len(page.keys))
+		if err != nil {
+			return Page{}, ErrInvalidPageToken
+		}
+	}
+	return page, nil
+}
+
+// maxPageOffset is the largest offset of a page.
+const maxPageOffset = 1<<31 - 1
+
+// keysetAlias is the alias of the query that pages are read from by keyset
+// pagination.
+const keysetAlias = "toolbox_page"
+
+// Apply restricts a statement with the given arguments to the rows of the
+// page, and to one more row that tells whether there is a next page. It
+// returns the statement of the page, along with its arguments.
+//
+// Pages read by keyset pagination select the rows of the statement that
+// follow the values of the keys of the previous page, which are bound to
+// arguments added after the given ones.
+func (p Page) Apply(statement string, args []any, d PageDialect) (string, []any) {
+	if p.Size <= 0 {
+		return statement, args
+	}
+	if p.after != nil {
+		if s, a, ok := p.applyKeyset(statement, args, d); ok {
+			return s, a
+		}
+	}
+	statement = strings.TrimRight(statement, "; \t\r\n")
+	// the clause starts on a new line, in case the statement ends with a
+	// comment
+	return statement + "\n" + fmt.Sprintf(string(d.Clause), p.Size+1, p.Offset), args
+}
+
+// applyKeyset restricts a statement to the rows of the page that follow the
+// values of the keys of the previous page. It returns false if the statement
+// cannot be read by keyset pagination in the dialect.
+func (p Page) applyKeyset(statement string, args []any, d PageDialect) (string, []any, bool) {
+	ordering, ok := sqlclassifier.OrderBy(statement)
+	if !ok || len(ordering.Keys) != len(p.after) || (ordering.With && d.NoNestedWith) {
+		return "", nil, false
+	}
+	args = slices.Clone(args)
+	bind := func(v any) string {
+		placeholder, arg := d.Bind(len(args)+1, v)
+		args = append(args, arg)
+		return placeholder
+	}
+	// the rows whose keys are equal to the previous row up to a key that
+	// follows it, in the order of the rows
+	var follow, order []string
+	for i, key := range ordering.Keys {
+		var terms []string
+		for j, prev := range ordering.Keys[:i] {
+			terms = append(terms, fmt.Sprintf("%s = %s", prev.Column, bind(p.after[j])))
+		}
+		op, dir := ">", ""
+		if key.Desc {
+			op, dir = "<", " DESC"
+		}
+		term := fmt.Sprintf("%s %s %s", key.Column, op, bind(p.after[i]))
+		if d.nullsAfter(key.Desc) {
+			term = fmt.Sprintf("(%s OR %s IS NULL)", term, key.Column)
+		}
+		follow = append(follow, "("+strings.Join(append(terms, term), " AND ")+")")
+		order = append(order, key.Column+dir)
+	}
+	// the statement is a subquery without its ORDER BY clause, which starts
+	// on a new line in case the statement ends with a comment
+	var b strings.Builder
+	fmt.Fprintf(&b, "SELECT * FROM (\n%s\n) %s\n", strings.TrimRight(statement[:ordering.Start], " \t\r\n"), keysetAlias)
+	fmt.Fprintf(&b, "WHERE %s\n", strings.Join(follow, " OR "))
+	fmt.Fprintf(&b, "ORDER BY %s\n", strings.Join(order, ", "))
+	fmt.Fprintf(&b, string(d.Clause), p.Size+1, 0)
+	return b.String(), args, true
+}
+
+// nextToken returns the token of the page that follows the given rows of the
+// page, of which next is the first row of the next page. The token holds the
+// values of the keys of the last row, if the next page can be read by keyset
+// pagination.
+func (p Page) nextToken(rows []any, next any, columns []string) string {
+	payload := binary.BigEndian.AppendUint64(nil, uint64(p.Offset+len(rows)))
+	payload = binary.BigEndian.AppendUint64(payload, uint64(time.Now().Unix()))
+	payload = append(payload, p.binding...)
+	if len(rows) > 0 {
+		if after, ok := p.keyValues(rows[len(rows)-1], next, columns); ok {
+			payload = append(payload, after...)
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(append(payload, p.signer.sign(payload)...))
+}
+
+// keyValues returns the encoded values of the keys of the last row of a page.
+// It returns false if the rows that follow it cannot be told apart by these
+// values: if a value is NULL or of an unknown type, if the columns of the
+// rows are not unique, or if the first row of the next page has the same
+// values.
+func (p Page) keyValues(last, next any, columns []string) ([]byte, bool) {
+	lastRow, ok := last.(map[string]any)
+	if !ok || len(p.keys) == 0 {
+		return nil, false
+	}
+	nextRow, _ := next.(map[string]any)
+	seen := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		if c == "" || seen[strings.ToLower(c)] {
+			return nil, false
+		}
+		seen[strings.ToLower(c)] = true
+	}
+	values := make([]keyValue, len(p.keys))
+	tie := true
+	for i, key := range p.keys {
+		// unquoted names match the columns regardless of their case
+		j := slices.IndexFunc(columns, func(c string) bool { return strings.EqualFold(c, key.Column) })
+		if j < 0 {
+			return nil, false
+		}
+		v, ok := encodeKeyValue(lastRow[columns[j]])
+		if !ok {
+			return nil, false
+		}
+		values[i] = v
+		w, ok := encodeKeyValue(nextRow[columns[j]])
+		tie = tie && ok && v.Type == w.Type && bytes.Equal(v.Value, w.Value)
+	}
+	if tie {
+		return nil, false
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// keyValue is a value of a key of a row in a page token, along with its Go
+// type, which is lost in JSON.
+type keyValue struct {
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v"`
+}
+
+// keyDecoders decode the values of keys by their type.
+var keyDecoders = map[string]func([]byte) (any, error){
+	"string": decodeKey[string],
+	"bool":   decodeKey[bool],
+	"int":    decodeKey[int64],
+	"uint":   decodeKey[uint64],
+	"float":  decodeKey[float64],
+	"time":   decodeKey[time.Time],
+	"bytes":  decodeKey[[]byte],
+}
+
+func decodeKey[T any](b []byte) (any, error) {
+	var v T
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// encodeKeyValue encodes the value of a key of a row. It returns false for
+// NULL values and values of other types than strings, booleans, numbers,
+// times and bytes.
+func encodeKeyValue(v any) (keyValue, bool) {
+	var typ string
+	switch x := v.(type) {
+	case string:
+		typ = "string"
+	case bool:
+		typ = "bool"
+	case int, int8, int16, int32, int64:
+		typ = "int"
+	case uint, uint8, uint16, uint32, uint64:
+		typ = "uint"
+	case float32:
+		// the exact value of the float, which is compared to the column
+		typ, v = "float", float64(x)
+	case float64:
+		typ = "float"
+	case time.Time:
+		typ = "time"
+	case []byte:
+		typ = "bytes"
+	default:
+		return keyValue{}, false
+	}
+	// NaN and infinite floats, and times outside of years 0-9999, fail
+	b, err := json.Marshal(v)
+	if err != nil {
+		return keyValue{}, false
+	}
+	return keyValue{Type: typ, Value: b}, true
+}
+
+// decodeKeyValues decodes the n values of the keys of a row in a page token.
+func decodeKeyValues(b []byte, n int) ([]any, error) {
+	var values []keyValue
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	if len(values) != n {
+		return nil, fmt.Errorf("got %d key values, want %d", len(values), n)
+	}
+	after := make([]any, n)
+	for i, v := range values {
+		decode, ok := keyDecoders[v.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown key type %q", v.Type)
+		}
+		var err error
+		if after[i], err = decode(v.Value); err != nil {
+			return nil, err
+		}
+	}
+	return after, nil
+}
+
+// PagedRows is a page of the rows of a paginated tool.
+type PagedRows struct {
+	// Rows are the rows of the page.
+	Rows []any `json:"rows"`
+	// NextPageToken is the token of the next page, or empty if this is the
+	// last page.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// Items returns the rows, followed by an item with the token of the next
+// page if there is one.
+func (p PagedRows) Items() []any {
+	items := append(make([]any, 0, len(p.Rows)+1), p.Rows...)
+	if p.NextPageToken != "" {
+		items = append(items, map[string]any{"nextPageToken": p.NextPageToken})
+	}
+	return items
+}
@@ -0,0 +1,467 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const pageStatement = "SELECT id FROM t WHERE a = $1 ORDER BY id"
+
+// pageContext returns a context with a page token signer with the given ttl,
+// and a caller.
+func pageContext(t *testing.T, ttl time.Duration, email string) context.Context {
+	t.Helper()
+	signer, err := tools.NewPageTokenSigner([]byte("secret"), ttl)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := tools.WithPageTokenSigner(context.Background(), signer)
+	return tools.WithPageTokenCaller(ctx, map[string]map[string]any{"google": {"email": email}})
+}
+
+// collectPage collects the page of a query of n rows with the given limits,
+// as restricted by the page clause, and returns the result along with the
+// token of the next page.
+func collectPage(t *testing.T, page tools.Page, limits sources.ResultLimits, n int) (tools.PagedRows, string) {
+	t.Helper()
+	rc := tools.NewRowCollector(limits).WithPage(page)
+	for i := page.Offset; i < n && i <= page.Offset+page.Size; i++ {
+		if !rc.Add(map[string]any{"id": i}) {
+			break
+		}
+	}
+	res, ok := rc.Result().(tools.PagedRows)
+	if !ok {
+		t.Fatalf("unexpected result type %T", rc.Result())
+	}
+	return res, res.NextPageToken
+}
+
+func withToken(params tools.ParamValues, token string) tools.ParamValues {
+	return append(params, tools.ParamValue{Name: tools.PageTokenParameter, Value: token})
+}
+
+func TestGetPage(t *testing.T) {
+	ctx := pageContext(t, time.Hour, "alice@example.com")
+	params := tools.ParamValues{{Name: "a", Value: 1}}
+
+	t.Run("pagination disabled", func(t *testing.T) {
+		page, err := tools.GetPage(ctx, 0, withToken(params, "garbage"), "tool", pageStatement)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if page.Size != 0 || page.Offset != 0 {
+			t.Fatalf("unexpected page: %+v", page)
+		}
+	})
+
+	t.Run("first page", func(t *testing.T) {
+		page, err := tools.GetPage(ctx, 2, params, "tool", pageStatement)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if page.Size != 2 || page.Offset != 0 {
+			t.Fatalf("unexpected page: %+v", page)
+		}
+	})
+
+	t.Run("next pages", func(t *testing.T) {
+		page, err := tools.GetPage(ctx, 2, params, "tool", pageStatement)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var got []any
+		for i := 0; i < 3; i++ {
+			res, token := collectPage(t, page, sources.ResultLimits{}, 5)
+			got = append(got, res.Rows...)
+			if token == "" {
+				break
+			}
+			page, err = tools.GetPage(ctx, 2, withToken(params, token), "tool", pageStatement)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+		want := []any{
+			map[string]any{"id": 0}, map[string]any{"id": 1}, map[string]any{"id": 2},
+			map[string]any{"id": 3}, map[string]any{"id": 4},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+		}
+	})
+
+	_, token := collectPage(t, mustGetPage(t, ctx, params), sources.ResultLimits{}, 5)
+	// change the offset of the token
+	tampered := "B" + token[1:]
+	if token[0] == 'B' {
+		tampered = "A" + token[1:]
+	}
+	invalid := []struct {
+		desc      string
+		ctx       context.Context
+		params    tools.ParamValues
+		name      string
+		statement string
+	}{
+		{
+			desc:      "garbage token",
+			ctx:       ctx,
+			params:    withToken(params, "garbage"),
+			name:      "tool",
+			statement: pageStatement,
+		},
+		{
+			desc:      "tampered token",
+			ctx:       ctx,
+			params:    withToken(params, tampered),
+			name:      "tool",
+			statement: pageStatement,
+		},
+		{
+			desc:      "other parameters",
+			ctx:       ctx,
+			params:    withToken(tools.ParamValues{{Name: "a", Value: 2}}, token),
+			name:      "tool",
+			statement: pageStatement,
+		},
+		{
+			desc:      "other tool",
+			ctx:       ctx,
+			params:    withToken(params, token),
+			name:      "other",
+			statement: pageStatement,
+		},
+		{
+			desc:      "other statement",
+			ctx:       ctx,
+			params:    withToken(params, token),
+			name:      "tool",
+			statement: "SELECT id FROM u WHERE a = $1 ORDER BY id",
+		},
+		{
+			desc:      "other caller",
+			ctx:       pageContext(t, time.Hour, "mallory@example.com"),
+			params:    withToken(params, token),
+			name:      "tool",
+			statement: pageStatement,
+		},
+		{
+			desc:      "expired token",
+			ctx:       pageContext(t, time.Nanosecond, "alice@example.com"),
+			params:    withToken(params, token),
+			name:      "tool",
+			statement: pageStatement,
+		},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.GetPage(tc.ctx, 2, tc.params, tc.name, tc.statement)
+			if !errors.Is(err, tools.ErrInvalidPageToken) {
+				t.Fatalf("expected ErrInvalidPageToken, got %v", err)
+			}
+		})
+	}
+
+	t.Run("statement that cannot be paginated", func(t *testing.T) {
+		for _, statement := range []string{"DELETE FROM t RETURNING id", "SELECT id FROM t ORDER BY id LIMIT 5"} {
+			page, err := tools.GetPage(ctx, 2, params, "tool", statement)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if page.Size != 0 {
+				t.Fatalf("statement %q must not be paginated, got %+v", statement, page)
+			}
+		}
+	})
+
+	t.Run("statement without order by", func(t *testing.T) {
+		_, err := tools.GetPage(ctx, 2, params, "tool", "SELECT id FROM t")
+		if err == nil {
+			t.Fatalf("expected an error for a statement without ORDER BY")
+		}
+	})
+
+	t.Run("no signer", func(t *testing.T) {
+		_, err := tools.GetPage(context.Background(), 2, params, "tool", pageStatement)
+		if err == nil {
+			t.Fatalf("expected an error without a page token signer")
+		}
+	})
+}
+
+func mustGetPage(t *testing.T, ctx context.Context, params tools.ParamValues) tools.Page {
+	t.Helper()
+	page, err := tools.GetPage(ctx, 2, params, "tool", pageStatement)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return page
+}
+
+func TestCheckPageSize(t *testing.T) {
+	if err := tools.CheckPageSize(2, pageStatement); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := tools.CheckPageSize(0, "SELECT id FROM t"); err != nil {
+		t.Fatalf("unexpected error without a page size: %s", err)
+	}
+	for _, statement := range []string{"SELECT id FROM t", "SELECT id FROM t ORDER BY id LIMIT 5", "DELETE FROM t"} {
+		if err := tools.CheckPageSize(2, statement); err == nil {
+			t.Fatalf("expected an error for %q", statement)
+		}
+	}
+}
+
+func TestPageApply(t *testing.T) {
+	ctx := pageContext(t, time.Hour, "alice@example.com")
+	params := tools.ParamValues{{Name: "a", Value: 1}}
+	_, token := collectPage(t, mustGetPage(t, ctx, params), sources.ResultLimits{}, 5)
+	page, err := tools.GetPage(ctx, 2, withToken(params, token), "tool", pageStatement)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, _ := page.Apply(pageStatement+"; -- the ids\n", nil, tools.PostgresDialect)
+	if want := pageStatement + "; -- the ids\nLIMIT 3 OFFSET 2"; got != want {
+		t.Fatalf("incorrect statement: got %q, want %q", got, want)
+	}
+	got, _ = page.Apply(pageStatement+";\n", nil, tools.OracleDialect)
+	if want := pageStatement + "\nOFFSET 2 ROWS FETCH NEXT 3 ROWS ONLY"; got != want {
+		t.Fatalf("incorrect statement: got %q, want %q", got, want)
+	}
+	if got, _ := (tools.Page{}).Apply(pageStatement, nil, tools.PostgresDialect); got != pageStatement {
+		t.Fatalf("statements must not change without pagination, got %q", got)
+	}
+}
+
+// keysetPage returns the page that follows the first two of the given rows
+// of the statement, and the statement and arguments it applies to the
+// statement in the dialect.
+func keysetPage(t *testing.T, statement string, rows []map[string]any, d tools.PageDialect) (tools.Page, string, []any) {
+	t.Helper()
+	ctx := pageContext(t, time.Hour, "alice@example.com")
+	params := tools.ParamValues{{Name: "a", Value: 1}}
+	page, err := tools.GetPage(ctx, 2, params, "tool", statement)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rc := tools.NewRowCollector(sources.ResultLimits{}).WithPage(page).WithFormat(tools.RowsJSON, []string{"id", "name"})
+	for _, row := range rows {
+		if !rc.Add(row) {
+			break
+		}
+	}
+	token := rc.Result().(tools.PagedRows).NextPageToken
+	page, err = tools.GetPage(ctx, 2, withToken(params, token), "tool", statement)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, args := page.Apply(statement, []any{1}, d)
+	return page, got, args
+}
+
+func TestPageApplyKeyset(t *testing.T) {
+	statement := "SELECT id, name FROM t WHERE a = $1 ORDER BY name DESC, ID"
+	rows := []map[string]any{
+		{"id": int32(1), "name": "b"},
+		{"id": int32(3), "name": "a"},
+		{"id": int32(2), "name": "a"},
+	}
+
+	t.Run("keyset", func(t *testing.T) {
+		page, got, args := keysetPage(t, statement, rows, tools.PostgresDialect)
+		want := "SELECT * FROM (\nSELECT id, name FROM t WHERE a = $1\n) toolbox_page\n" +
+			"WHERE (name < $2) OR (name = $3 AND (ID > $4 OR ID IS NULL))\n" +
+			"ORDER BY name DESC, ID\n" +
+			"LIMIT 3 OFFSET 0"
+		if got != want {
+			t.Fatalf("incorrect statement: got %q, want %q", got, want)
+		}
+		if diff := cmp.Diff([]any{1, "a", "a", int64(3)}, args); diff != "" {
+			t.Fatalf("incorrect arguments (-want +got):\n%s", diff)
+		}
+		if page.Offset != 2 {
+			t.Fatalf("incorrect offset: got %d, want 2", page.Offset)
+		}
+	})
+
+	t.Run("named arguments and nulls first", func(t *testing.T) {
+		_, got, args := keysetPage(t, statement, rows, tools.SQLServerDialect)
+		want := "SELECT * FROM (\nSELECT id, name FROM t WHERE a = $1\n) toolbox_page\n" +
+			"WHERE ((name < @page_key_2 OR name IS NULL)) OR (name = @page_key_3 AND ID > @page_key_4)\n" +
+			"ORDER BY name DESC, ID\n" +
+			"OFFSET 0 ROWS FETCH NEXT 3 ROWS ONLY"
+		if got != want {
+			t.Fatalf("incorrect statement: got %q, want %q", got, want)
+		}
+		wantArgs := []any{1, sql.Named("page_key_2", "a"), sql.Named("page_key_3", "a"), sql.Named("page_key_4", int64(3))}
+		if diff := cmp.Diff(wantArgs, args, cmpopts.IgnoreUnexported(sql.NamedArg{})); diff != "" {
+			t.Fatalf("incorrect arguments (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("nulls last", func(t *testing.T) {
+		_, got, args := keysetPage(t, statement, rows, tools.TrinoDialect)
+		want := "SELECT * FROM (\nSELECT id, name FROM t WHERE a = $1\n) toolbox_page\n" +
+			"WHERE ((name < ? OR name IS NULL)) OR (name = ? AND (ID > ? OR ID IS NULL))\n" +
+			"ORDER BY name DESC, ID\n" +
+			"OFFSET 0 ROWS FETCH NEXT 3 ROWS ONLY"
+		if got != want {
+			t.Fatalf("incorrect statement: got %q, want %q", got, want)
+		}
+		if diff := cmp.Diff([]any{1, "a", "a", int64(3)}, args); diff != "" {
+			t.Fatalf("incorrect arguments (-want +got):\n%s", diff)
+		}
+	})
+
+	offsetTCs := []struct {
+		desc      string
+		statement string
+		rows      []map[string]any
+		dialect   tools.PageDialect
+	}{
+		{
+			desc:      "tie with the next page",
+			statement: "SELECT id, name FROM t WHERE a = $1 ORDER BY name",
+			rows:      rows,
+			dialect:   tools.PostgresDialect,
+		},
+		{
+			desc:      "null key",
+			statement: statement,
+			rows:      []map[string]any{{"id": 1, "name": "b"}, {"id": nil, "name": "a"}, {"id": 2, "name": "a"}},
+			dialect:   tools.PostgresDialect,
+		},
+		{
+			desc:      "key of unknown type",
+			statement: statement,
+			rows:      []map[string]any{{"id": 1, "name": "b"}, {"id": []int{3}, "name": "a"}, {"id": 2, "name": "a"}},
+			dialect:   tools.PostgresDialect,
+		},
+		{
+			desc:      "key not in the result columns",
+			statement: "SELECT id, name FROM t WHERE a = $1 ORDER BY created",
+			rows:      rows,
+			dialect:   tools.PostgresDialect,
+		},
+		{
+			desc:      "expression",
+			statement: "SELECT id, name FROM t WHERE a = $1 ORDER BY lower(name), id",
+			rows:      rows,
+			dialect:   tools.PostgresDialect,
+		},
+		{
+			desc:      "cte in a dialect without nested with clauses",
+			statement: "WITH u AS (SELECT id, name FROM t WHERE a = $1) SELECT * FROM u ORDER BY name DESC, id",
+			rows:      rows,
+			dialect:   tools.SQLServerDialect,
+		},
+	}
+	for _, tc := range offsetTCs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, got, args := keysetPage(t, tc.statement, tc.rows, tc.dialect)
+			want := tc.statement + "\n" + fmt.Sprintf(string(tc.dialect.Clause), 3, 2)
+			if got != want {
+				t.Fatalf("incorrect statement: got %q, want %q", got, want)
+			}
+			if diff := cmp.Diff([]any{1}, args); diff != "" {
+				t.Fatalf("incorrect arguments (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRowCollectorWithPage(t *testing.T) {
+	row := func(id int) any { return map[string]any{"id": id} }
+	ctx := pageContext(t, time.Hour, "alice@example.com")
+	params := tools.ParamValues{{Name: "a", Value: 1}}
+
+	t.Run("full page", func(t *testing.T) {
+		res, token := collectPage(t, mustGetPage(t, ctx, params), sources.ResultLimits{}, 3)
+		if diff := cmp.Diff([]any{row(0), row(1)}, res.Rows); diff != "" {
+			t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+		}
+		if token == "" {
+			t.Fatalf("expected the token of the next page")
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		res, token := collectPage(t, mustGetPage(t, ctx, params), sources.ResultLimits{}, 2)
+		if diff := cmp.Diff([]any{row(0), row(1)}, res.Rows); diff != "" {
+			t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+		}
+		if token != "" {
+			t.Fatalf("unexpected token of the next page: %q", token)
+		}
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		res, token := collectPage(t, mustGetPage(t, ctx, params), sources.ResultLimits{}, 0)
+		if diff := cmp.Diff([]any{}, res.Rows); diff != "" {
+			t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+		}
+		if token != "" {
+			t.Fatalf("unexpected token of the next page: %q", token)
+		}
+	})
+
+	t.Run("page shortened by limits", func(t *testing.T) {
+		res, token := collectPage(t, mustGetPage(t, ctx, params), sources.ResultLimits{MaxRows: 1}, 3)
+		if diff := cmp.Diff([]any{row(0)}, res.Rows); diff != "" {
+			t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+		}
+		page, err := tools.GetPage(ctx, 2, withToken(params, token), "tool", pageStatement)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if page.Offset != 1 {
+			t.Fatalf("the next page should start after the rows returned, got offset %d", page.Offset)
+		}
+	})
+
+	t.Run("first row over limits", func(t *testing.T) {
+		rc := tools.NewRowCollector(sources.ResultLimits{MaxResultBytes: 4}).WithPage(mustGetPage(t, ctx, params))
+		rc.Add(row(0))
+		want := tools.TruncatedRows{Rows: []any{}, Truncated: true, RowCount: 0}
+		if diff := cmp.Diff(want, rc.Result()); diff != "" {
+			t.Fatalf("incorrect result (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestPagedRowsItems(t *testing.T) {
+	rows := []any{map[string]any{"id": 0}}
+	got := tools.PagedRows{Rows: rows, NextPageToken: "next"}.Items()
+	want := []any{map[string]any{"id": 0}, map[string]any{"nextPageToken": "next"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect items (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(rows, tools.PagedRows{Rows: rows}.Items()); diff != "" {
+		t.Fatalf("incorrect items of the last page (-want +got):\n%s", diff)
+	}
+}
@@ -19,13 +19,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/mail"
+	"regexp"
 	"slices"
 	"strings"
 	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
 
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
+// templateFuncs are the functions available to the templates of statements.
+var templateFuncs = template.FuncMap{
+	"array": ConvertArrayParamToString,
+}
+
 const (
 	typeString = "string"
 	typeInt    = "integer"
@@ -33,8 +43,19 @@ const (
 	typeBool   = "boolean"
 	typeArray  = "array"
 	typeMap    = "map"
+	typeObject = "object"
+)
+
+// Formats supported by the "format" field of a StringParameter.
+const (
+	formatDate     = "date"
+	formatDateTime = "date-time"
+	formatEmail    = "email"
+	formatUUID     = "uuid"
 )
 
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 // ParamValues is an ordered list of ParamValue
 type ParamValues []ParamValue
 
@@ -191,10 +212,7 @@ func ResolveTemplateParams(templateParams Parameters, originalStatement string,
 		return "", fmt.Errorf("error getting template params %s", err)
 	}
 
-	funcMap := template.FuncMap{
-		"array": ConvertArrayParamToString,
-	}
-	t, err := template.New("statement").Funcs(funcMap).Parse(originalStatement)
+	t, err := template.New("statement").Funcs(templateFuncs).Parse(originalStatement)
 	if err != nil {
 		return "", fmt.Errorf("error creating go template %s", err)
 	}
@@ -208,6 +226,59 @@ func ResolveTemplateParams(templateParams Parameters, originalStatement string,
 	return modifiedStatement, nil
 }
 
+// TemplateParamReferences returns the names of the template parameters
+// referenced by a statement, in order of first appearance.
+func TemplateParamReferences(statement string) ([]string, error) {
+	t, err := template.New("statement").Funcs(templateFuncs).Parse(statement)
+	if err != nil {
+		return nil, fmt.Errorf("error creating go template %s", err)
+	}
+	var names []string
+	var walk func(parse.Node)
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Cmds {
+				walk(c)
+			}
+		case *parse.CommandNode:
+			for _, a := range n.Args {
+				walk(a)
+			}
+		case *parse.FieldNode:
+			if !slices.Contains(names, n.Ident[0]) {
+				names = append(names, n.Ident[0])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		}
+	}
+	walk(t.Tree.Root)
+	return names, nil
+}
+
 // ProcessParameters concatenate templateParameters and parameters from a tool.
 // It returns a list of concatenated parameters, concatenated Toolbox manifest, and concatenated MCP Manifest.
 func ProcessParameters(templateParams Parameters, params Parameters) (Parameters, []ParameterManifest, error) {
@@ -295,6 +366,9 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.validateConstraints(); err != nil {
+			return nil, fmt.Errorf("invalid constraints for parameter %q: %w", a.Name, err)
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
@@ -306,6 +380,9 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.validateConstraints(); err != nil {
+			return nil, fmt.Errorf("invalid constraints for parameter %q: %w", a.Name, err)
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
@@ -317,6 +394,9 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.validateConstraints(); err != nil {
+			return nil, fmt.Errorf("invalid constraints for parameter %q: %w", a.Name, err)
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
@@ -339,6 +419,20 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.validateConstraints(); err != nil {
+			return nil, fmt.Errorf("invalid constraints for parameter %q: %w", a.Name, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
+	case typeObject:
+		a := &ObjectParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
@@ -394,21 +488,42 @@ func (ps Parameters) McpManifest() (McpToolsSchema, map[string][]string) {
 
 // ParameterManifest represents parameters when served as part of a ToolManifest.
 type ParameterManifest struct {
-	Name                 string             `json:"name"`
-	Type                 string             `json:"type"`
-	Required             bool               `json:"required"`
-	Description          string             `json:"description"`
-	AuthServices         []string           `json:"authSources"`
-	Items                *ParameterManifest `json:"items,omitempty"`
-	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Name                 string              `json:"name"`
+	Type                 string              `json:"type"`
+	Required             bool                `json:"required"`
+	Description          string              `json:"description"`
+	AuthServices         []string            `json:"authSources"`
+	Items                *ParameterManifest  `json:"items,omitempty"`
+	Properties           []ParameterManifest `json:"properties,omitempty"`
+	AdditionalProperties any                 `json:"additionalProperties,omitempty"`
+	Enum                 []any               `json:"enum,omitempty"`
+	Minimum              *float64            `json:"minimum,omitempty"`
+	Maximum              *float64            `json:"maximum,omitempty"`
+	Pattern              string              `json:"pattern,omitempty"`
+	MinLength            *int                `json:"minLength,omitempty"`
+	MaxLength            *int                `json:"maxLength,omitempty"`
+	Format               string              `json:"format,omitempty"`
+	MinItems             *int                `json:"minItems,omitempty"`
+	MaxItems             *int                `json:"maxItems,omitempty"`
 }
 
 // ParameterMcpManifest represents properties when served as part of a ToolMcpManifest.
 type ParameterMcpManifest struct {
-	Type                 string                `json:"type"`
-	Description          string                `json:"description"`
-	Items                *ParameterMcpManifest `json:"items,omitempty"`
-	AdditionalProperties any                   `json:"additionalProperties,omitempty"`
+	Type                 string                          `json:"type"`
+	Description          string                          `json:"description"`
+	Items                *ParameterMcpManifest           `json:"items,omitempty"`
+	Properties           map[string]ParameterMcpManifest `json:"properties,omitempty"`
+	Required             []string                        `json:"required,omitempty"`
+	AdditionalProperties any                             `json:"additionalProperties,omitempty"`
+	Enum                 []any                           `json:"enum,omitempty"`
+	Minimum              *float64                        `json:"minimum,omitempty"`
+	Maximum              *float64                        `json:"maximum,omitempty"`
+	Pattern              string                          `json:"pattern,omitempty"`
+	MinLength            *int                            `json:"minLength,omitempty"`
+	MaxLength            *int                            `json:"maxLength,omitempty"`
+	Format               string                          `json:"format,omitempty"`
+	MinItems             *int                            `json:"minItems,omitempty"`
+	MaxItems             *int                            `json:"maxItems,omitempty"`
 }
 
 // CommonParameter are default fields that are emebdding in most Parameter implementations. Embedding this stuct will give the object Name() and Type() functions.
@@ -469,6 +584,70 @@ func (e ParseTypeError) Error() string {
 	return fmt.Sprintf("%q not type %q", e.Value, e.Type)
 }
 
+// enumAsAny converts the allowed values of a Parameter to a list suitable for a manifest.
+func enumAsAny[T any](enum []T) []any {
+	if len(enum) == 0 {
+		return nil
+	}
+	rtn := make([]any, 0, len(enum))
+	for _, e := range enum {
+		rtn = append(rtn, e)
+	}
+	return rtn
+}
+
+// intAsFloat converts an optional integer bound to the numeric type used in manifests.
+func intAsFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// checkBounds verifies that an optional lower bound does not exceed an optional upper bound.
+func checkBounds[T int | float64](minField, maxField string, minV, maxV *T) error {
+	if minV != nil && maxV != nil && *minV > *maxV {
+		return fmt.Errorf("%q (%v) is greater than %q (%v)", minField, *minV, maxField, *maxV)
+	}
+	return nil
+}
+
+// checkLengths verifies that optional length constraints are non-negative and consistent.
+func checkLengths(minField, maxField string, minV, maxV *int) error {
+	if minV != nil && *minV < 0 {
+		return fmt.Errorf("%q must not be negative", minField)
+	}
+	if maxV != nil && *maxV < 0 {
+		return fmt.Errorf("%q must not be negative", maxField)
+	}
+	return checkBounds(minField, maxField, minV, maxV)
+}
+
+// checkFormat verifies that a string value matches one of the supported formats.
+func checkFormat(format, v string) error {
+	var ok bool
+	switch format {
+	case formatDate:
+		_, err := time.Parse(time.DateOnly, v)
+		ok = err == nil
+	case formatDateTime:
+		_, err := time.Parse(time.RFC3339, v)
+		ok = err == nil
+	case formatEmail:
+		addr, err := mail.ParseAddress(v)
+		ok = err == nil && addr.Address == v
+	case formatUUID:
+		ok = uuidRegex.MatchString(v)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+	if !ok {
+		return fmt.Errorf("%q is not a valid %q", v, format)
+	}
+	return nil
+}
+
 type ParamAuthService struct {
 	Name  string `yaml:"name"`
 	Field string `yaml:"field"`
@@ -529,7 +708,26 @@ var _ Parameter = &StringParameter{}
 // StringParameter is a parameter representing the "string" type.
 type StringParameter struct {
 	CommonParameter `yaml:",inline"`
-	Default         *string `yaml:"default"`
+	Default         *string  `yaml:"default"`
+	Enum            []string `yaml:"enum"`
+	Pattern         string   `yaml:"pattern"`
+	MinLength       *int     `yaml:"minLength"`
+	MaxLength       *int     `yaml:"maxLength"`
+	Format          string   `yaml:"format"`
+	// CompiledPattern is the Pattern compiled when the parameter is validated.
+	CompiledPattern compiledPattern `yaml:"-" json:"-"`
+}
+
+// compiledPattern is a compiled Pattern of a StringParameter.
+type compiledPattern struct {
+	re *regexp.Regexp
+}
+
+// Equal reports whether two compiled patterns are equal. They always are, as
+// they are compiled from the Pattern of the parameter, which is compared
+// instead.
+func (compiledPattern) Equal(compiledPattern) bool {
+	return true
 }
 
 // Parse casts the value "v" as a "string".
@@ -538,9 +736,69 @@ func (p *StringParameter) Parse(v any) (any, error) {
 	if !ok {
 		return nil, &ParseTypeError{p.Name, p.Type, v}
 	}
+	if err := p.checkConstraints(newV); err != nil {
+		return nil, err
+	}
 	return newV, nil
 }
 
+// checkConstraints verifies that "v" satisfies the constraints of the StringParameter.
+func (p *StringParameter) checkConstraints(v string) error {
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, v) {
+		return fmt.Errorf("%q is not one of %q", v, p.Enum)
+	}
+	length := utf8.RuneCountInString(v)
+	if p.MinLength != nil && length < *p.MinLength {
+		return fmt.Errorf("%q is shorter than the minimum length of %d", v, *p.MinLength)
+	}
+	if p.MaxLength != nil && length > *p.MaxLength {
+		return fmt.Errorf("%q is longer than the maximum length of %d", v, *p.MaxLength)
+	}
+	if p.Pattern != "" {
+		re := p.CompiledPattern.re
+		if re == nil {
+			// the pattern of a parameter that wasn't validated is compiled on use
+			var err error
+			re, err = regexp.Compile(p.Pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p.Pattern, err)
+			}
+		}
+		if !re.MatchString(v) {
+			return fmt.Errorf("%q does not match pattern %q", v, p.Pattern)
+		}
+	}
+	if p.Format != "" {
+		return checkFormat(p.Format, v)
+	}
+	return nil
+}
+
+// validateConstraints verifies that the constraints of the StringParameter are consistent.
+func (p *StringParameter) validateConstraints() error {
+	if p.Pattern != "" {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p.Pattern, err)
+		}
+		p.CompiledPattern = compiledPattern{re: re}
+	}
+	switch p.Format {
+	case "", formatDate, formatDateTime, formatEmail, formatUUID:
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of %q", p.Format, []string{formatDate, formatDateTime, formatEmail, formatUUID})
+	}
+	if err := checkLengths("minLength", "maxLength", p.MinLength, p.MaxLength); err != nil {
+		return err
+	}
+	if p.Default != nil {
+		if err := p.checkConstraints(*p.Default); err != nil {
+			return fmt.Errorf("invalid default value: %w", err)
+		}
+	}
+	return nil
+}
+
 func (p *StringParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -563,9 +821,28 @@ func (p *StringParameter) Manifest() ParameterManifest {
 		Required:     r,
 		Description:  p.Desc,
 		AuthServices: authServiceNames,
+		Enum:         enumAsAny(p.Enum),
+		Pattern:      p.Pattern,
+		MinLength:    p.MinLength,
+		MaxLength:    p.MaxLength,
+		Format:       p.Format,
 	}
 }
 
+// McpManifest returns the MCP manifest for the StringParameter.
+func (p *StringParameter) McpManifest() (ParameterMcpManifest, []string) {
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	return ParameterMcpManifest{
+		Type:        p.Type,
+		Description: p.Desc,
+		Enum:        enumAsAny(p.Enum),
+		Pattern:     p.Pattern,
+		MinLength:   p.MinLength,
+		MaxLength:   p.MaxLength,
+		Format:      p.Format,
+	}, authServiceNames
+}
+
 // NewIntParameter is a convenience function for initializing a IntParameter.
 func NewIntParameter(name string, desc string) *IntParameter {
 	return &IntParameter{
@@ -621,7 +898,10 @@ var _ Parameter = &IntParameter{}
 // IntParameter is a parameter representing the "int" type.
 type IntParameter struct {
 	CommonParameter `yaml:",inline"`
-	Default         *int `yaml:"default"`
+	Default         *int  `yaml:"default"`
+	Enum            []int `yaml:"enum"`
+	Minimum         *int  `yaml:"minimum"`
+	Maximum         *int  `yaml:"maximum"`
 }
 
 func (p *IntParameter) Parse(v any) (any, error) {
@@ -642,9 +922,39 @@ func (p *IntParameter) Parse(v any) (any, error) {
 		}
 		out = int(newI)
 	}
+	if err := p.checkConstraints(out); err != nil {
+		return nil, err
+	}
 	return out, nil
 }
 
+// checkConstraints verifies that "v" satisfies the constraints of the IntParameter.
+func (p *IntParameter) checkConstraints(v int) error {
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, v) {
+		return fmt.Errorf("%d is not one of %v", v, p.Enum)
+	}
+	if p.Minimum != nil && v < *p.Minimum {
+		return fmt.Errorf("%d is less than the minimum of %d", v, *p.Minimum)
+	}
+	if p.Maximum != nil && v > *p.Maximum {
+		return fmt.Errorf("%d is greater than the maximum of %d", v, *p.Maximum)
+	}
+	return nil
+}
+
+// validateConstraints verifies that the constraints of the IntParameter are consistent.
+func (p *IntParameter) validateConstraints() error {
+	if err := checkBounds("minimum", "maximum", p.Minimum, p.Maximum); err != nil {
+		return err
+	}
+	if p.Default != nil {
+		if err := p.checkConstraints(*p.Default); err != nil {
+			return fmt.Errorf("invalid default value: %w", err)
+		}
+	}
+	return nil
+}
+
 func (p *IntParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -667,9 +977,24 @@ func (p *IntParameter) Manifest() ParameterManifest {
 		Required:     r,
 		Description:  p.Desc,
 		AuthServices: authServiceNames,
+		Enum:         enumAsAny(p.Enum),
+		Minimum:      intAsFloat(p.Minimum),
+		Maximum:      intAsFloat(p.Maximum),
 	}
 }
 
+// McpManifest returns the MCP manifest for the IntParameter.
+func (p *IntParameter) McpManifest() (ParameterMcpManifest, []string) {
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	return ParameterMcpManifest{
+		Type:        p.Type,
+		Description: p.Desc,
+		Enum:        enumAsAny(p.Enum),
+		Minimum:     intAsFloat(p.Minimum),
+		Maximum:     intAsFloat(p.Maximum),
+	}, authServiceNames
+}
+
 // NewFloatParameter is a convenience function for initializing a FloatParameter.
 func NewFloatParameter(name string, desc string) *FloatParameter {
 	return &FloatParameter{
@@ -725,7 +1050,10 @@ var _ Parameter = &FloatParameter{}
 // FloatParameter is a parameter representing the "float" type.
 type FloatParameter struct {
 	CommonParameter `yaml:",inline"`
-	Default         *float64 `yaml:"default"`
+	Default         *float64  `yaml:"default"`
+	Enum            []float64 `yaml:"enum"`
+	Minimum         *float64  `yaml:"minimum"`
+	Maximum         *float64  `yaml:"maximum"`
 }
 
 func (p *FloatParameter) Parse(v any) (any, error) {
@@ -744,9 +1072,39 @@ func (p *FloatParameter) Parse(v any) (any, error) {
 		}
 		out = float64(newI)
 	}
+	if err := p.checkConstraints(out); err != nil {
+		return nil, err
+	}
 	return out, nil
 }
 
+// checkConstraints verifies that "v" satisfies the constraints of the FloatParameter.
+func (p *FloatParameter) checkConstraints(v float64) error {
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, v) {
+		return fmt.Errorf("%v is not one of %v", v, p.Enum)
+	}
+	if p.Minimum != nil && v < *p.Minimum {
+		return fmt.Errorf("%v is less than the minimum of %v", v, *p.Minimum)
+	}
+	if p.Maximum != nil && v > *p.Maximum {
+		return fmt.Errorf("%v is greater than the maximum of %v", v, *p.Maximum)
+	}
+	return nil
+}
+
+// validateConstraints verifies that the constraints of the FloatParameter are consistent.
+func (p *FloatParameter) validateConstraints() error {
+	if err := checkBounds("minimum", "maximum", p.Minimum, p.Maximum); err != nil {
+		return err
+	}
+	if p.Default != nil {
+		if err := p.checkConstraints(*p.Default); err != nil {
+			return fmt.Errorf("invalid default value: %w", err)
+		}
+	}
+	return nil
+}
+
 func (p *FloatParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -769,6 +1127,9 @@ func (p *FloatParameter) Manifest() ParameterManifest {
 		Required:     r,
 		Description:  p.Desc,
 		AuthServices: authServiceNames,
+		Enum:         enumAsAny(p.Enum),
+		Minimum:      p.Minimum,
+		Maximum:      p.Maximum,
 	}
 }
 
@@ -779,6 +1140,9 @@ func (p *FloatParameter) McpManifest() (ParameterMcpManifest, []string) {
 	return ParameterMcpManifest{
 		Type:        "number",
 		Description: p.Desc,
+		Enum:        enumAsAny(p.Enum),
+		Minimum:     p.Minimum,
+		Maximum:     p.Maximum,
 	}, authServiceNames
 }
 
@@ -934,6 +1298,8 @@ type ArrayParameter struct {
 	CommonParameter `yaml:",inline"`
 	Default         *[]any    `yaml:"default"`
 	Items           Parameter `yaml:"items"`
+	MinItems        *int      `yaml:"minItems"`
+	MaxItems        *int      `yaml:"maxItems"`
 }
 
 func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
@@ -941,12 +1307,16 @@ func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(inter
 		CommonParameter `yaml:",inline"`
 		Default         *[]any                  `yaml:"default"`
 		Items           util.DelayedUnmarshaler `yaml:"items"`
+		MinItems        *int                    `yaml:"minItems"`
+		MaxItems        *int                    `yaml:"maxItems"`
 	}
 	if err := unmarshal(&rawItem); err != nil {
 		return err
 	}
 	p.CommonParameter = rawItem.CommonParameter
 	p.Default = rawItem.Default
+	p.MinItems = rawItem.MinItems
+	p.MaxItems = rawItem.MaxItems
 	i, err := parseParamFromDelayedUnmarshaler(ctx, &rawItem.Items)
 	if err != nil {
 		return fmt.Errorf("unable to parse 'items' field: %w", err)
@@ -964,6 +1334,12 @@ func (p *ArrayParameter) Parse(v any) (any, error) {
 	if !ok {
 		return nil, &ParseTypeError{p.Name, p.Type, arrVal}
 	}
+	if p.MinItems != nil && len(arrVal) < *p.MinItems {
+		return nil, fmt.Errorf("array has %d items, fewer than the minimum of %d", len(arrVal), *p.MinItems)
+	}
+	if p.MaxItems != nil && len(arrVal) > *p.MaxItems {
+		return nil, fmt.Errorf("array has %d items, more than the maximum of %d", len(arrVal), *p.MaxItems)
+	}
 	rtn := make([]any, 0, len(arrVal))
 	for idx, val := range arrVal {
 		val, err := p.Items.Parse(val)
@@ -975,6 +1351,22 @@ func (p *ArrayParameter) Parse(v any) (any, error) {
 	return rtn, nil
 }
 
+// validateConstraints verifies that the constraints of the ArrayParameter are consistent.
+func (p *ArrayParameter) validateConstraints() error {
+	if err := checkLengths("minItems", "maxItems", p.MinItems, p.MaxItems); err != nil {
+		return err
+	}
+	if p.Default != nil {
+		if p.MinItems != nil && len(*p.Default) < *p.MinItems {
+			return fmt.Errorf("invalid default value: fewer than %d items", *p.MinItems)
+		}
+		if p.MaxItems != nil && len(*p.Default) > *p.MaxItems {
+			return fmt.Errorf("invalid default value: more than %d items", *p.MaxItems)
+		}
+	}
+	return nil
+}
+
 func (p *ArrayParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -1005,6 +1397,8 @@ func (p *ArrayParameter) Manifest() ParameterManifest {
 		Description:  p.Desc,
 		AuthServices: authServiceNames,
 		Items:        &items,
+		MinItems:     p.MinItems,
+		MaxItems:     p.MaxItems,
 	}
 }
 
@@ -1017,6 +1411,8 @@ func (p *ArrayParameter) McpManifest() (ParameterMcpManifest, []string) {
 		Type:        p.Type,
 		Description: p.Desc,
 		Items:       &items,
+		MinItems:    p.MinItems,
+		MaxItems:    p.MaxItems,
 	}, authServiceNames
 }
 
@@ -1225,3 +1621,183 @@ func (p *MapParameter) McpManifest() (ParameterMcpManifest, []string) {
 		AdditionalProperties: additionalProperties,
 	}, authServiceNames
 }
+
+// NewObjectParameter is a convenience function for initializing a ObjectParameter.
+func NewObjectParameter(name string, desc string, properties Parameters) *ObjectParameter {
+	return &ObjectParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeObject,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Properties: properties,
+	}
+}
+
+// NewObjectParameterWithDefault is a convenience function for initializing a ObjectParameter with default value.
+func NewObjectParameterWithDefault(name string, defaultV map[string]any, desc string, properties Parameters) *ObjectParameter {
+	return &ObjectParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeObject,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Properties: properties,
+		Default:    &defaultV,
+	}
+}
+
+// NewObjectParameterWithRequired is a convenience function for initializing a ObjectParameter.
+func NewObjectParameterWithRequired(name string, desc string, required bool, properties Parameters) *ObjectParameter {
+	return &ObjectParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeObject,
+			Desc:         desc,
+			Required:     &required,
+			AuthServices: nil,
+		},
+		Properties: properties,
+	}
+}
+
+// NewObjectParameterWithAuth is a convenience function for initializing a ObjectParameter with a list of ParamAuthService.
+func NewObjectParameterWithAuth(name string, desc string, properties Parameters, authServices []ParamAuthService) *ObjectParameter {
+	return &ObjectParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeObject,
+			Desc:         desc,
+			AuthServices: authServices,
+		},
+		Properties: properties,
+	}
+}
+
+var _ Parameter = &ObjectParameter{}
+
+// ObjectParameter is a parameter representing an object with a fixed set of
+// named properties. Each property is itself a Parameter, and is required unless
+// it sets `required: false` or has a default value.
+type ObjectParameter struct {
+	CommonParameter `yaml:",inline"`
+	Default         *map[string]any `yaml:"default"`
+	Properties      Parameters      `yaml:"properties"`
+}
+
+// UnmarshalYAML handles parsing the ObjectParameter from YAML input.
+func (p *ObjectParameter) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var rawItem struct {
+		CommonParameter `yaml:",inline"`
+		Default         *map[string]any           `yaml:"default"`
+		Properties      []util.DelayedUnmarshaler `yaml:"properties"`
+	}
+	if err := unmarshal(&rawItem); err != nil {
+		return err
+	}
+	if len(rawItem.Properties) == 0 {
+		return fmt.Errorf("object parameter %q must have at least one property", rawItem.Name)
+	}
+	properties := make(Parameters, 0, len(rawItem.Properties))
+	for _, u := range rawItem.Properties {
+		prop, err := parseParamFromDelayedUnmarshaler(ctx, &u)
+		if err != nil {
+			return fmt.Errorf("unable to parse 'properties' field: %w", err)
+		}
+		if len(prop.GetAuthServices()) != 0 {
+			return fmt.Errorf("nested properties should not have auth services")
+		}
+		properties = append(properties, prop)
+	}
+	if err := CheckDuplicateParameters(properties); err != nil {
+		return fmt.Errorf("unable to parse 'properties' field: %w", err)
+	}
+	p.CommonParameter = rawItem.CommonParameter
+	p.Default = rawItem.Default
+	p.Properties = properties
+	return nil
+}
+
+// Parse validates an incoming object against the properties of the ObjectParameter.
+// Missing optional properties are filled in with their default value, if any.
+func (p *ObjectParameter) Parse(v any) (any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	rtn := make(map[string]any, len(p.Properties))
+	for _, prop := range p.Properties {
+		name := prop.GetName()
+		val, ok := m[name]
+		if !ok || val == nil {
+			val = prop.GetDefault()
+			if val == nil {
+				if CheckParamRequired(prop.GetRequired(), nil) {
+					return nil, fmt.Errorf("property %q is required", name)
+				}
+				continue
+			}
+		}
+		parsedVal, err := prop.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse property %q: %w", name, err)
+		}
+		rtn[name] = parsedVal
+	}
+	for key := range m {
+		if _, ok := rtn[key]; ok {
+			continue
+		}
+		if !slices.ContainsFunc(p.Properties, func(prop Parameter) bool { return prop.GetName() == key }) {
+			return nil, fmt.Errorf("unknown property %q", key)
+		}
+	}
+	return rtn, nil
+}
+
+func (p *ObjectParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+func (p *ObjectParameter) GetDefault() any {
+	if p.Default == nil {
+		return nil
+	}
+	return *p.Default
+}
+
+func (p *ObjectParameter) GetProperties() Parameters {
+	return p.Properties
+}
+
+// Manifest returns the manifest for the ObjectParameter.
+func (p *ObjectParameter) Manifest() ParameterManifest {
+	// only list ParamAuthService names (without fields) in manifest
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	r := CheckParamRequired(p.GetRequired(), p.GetDefault())
+	return ParameterManifest{
+		Name:                 p.Name,
+		Type:                 p.Type,
+		Required:             r,
+		Description:          p.Desc,
+		AuthServices:         authServiceNames,
+		Properties:           p.Properties.Manifest(),
+		AdditionalProperties: false,
+	}
+}
+
+// McpManifest returns the MCP manifest for the ObjectParameter.
+func (p *ObjectParameter) McpManifest() (ParameterMcpManifest, []string) {
+	// only list ParamAuthService names (without fields) in manifest
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	schema, _ := p.Properties.McpManifest()
+	return ParameterMcpManifest{
+		Type:                 p.Type,
+		Description:          p.Desc,
+		Properties:           schema.Properties,
+		Required:             schema.Required,
+		AdditionalProperties: false,
+	}, authServiceNames
+}
@@ -353,6 +353,136 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewMapParameter("my_generic_map", "this param is a generic map", ""),
 			},
 		},
+		{
+			name: "string with constraints",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"enum":        []string{"a@example.com", "b@example.com"},
+					"pattern":     "^[a-z]@",
+					"minLength":   1,
+					"maxLength":   20,
+					"format":      "email",
+				},
+			},
+			want: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Enum:            []string{"a@example.com", "b@example.com"},
+					Pattern:         "^[a-z]@",
+					MinLength:       intPtr(1),
+					MaxLength:       intPtr(20),
+					Format:          "email",
+				},
+			},
+		},
+		{
+			name: "int with constraints",
+			in: []map[string]any{
+				{
+					"name":        "my_integer",
+					"type":        "integer",
+					"description": "this param is an int",
+					"minimum":     1,
+					"maximum":     10,
+					"default":     5,
+				},
+			},
+			want: tools.Parameters{
+				&tools.IntParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_integer", Type: "integer", Desc: "this param is an int"},
+					Default:         intPtr(5),
+					Minimum:         intPtr(1),
+					Maximum:         intPtr(10),
+				},
+			},
+		},
+		{
+			name: "float with enum",
+			in: []map[string]any{
+				{
+					"name":        "my_float",
+					"type":        "float",
+					"description": "this param is a float",
+					"enum":        []float64{0.5, 1.5},
+				},
+			},
+			want: tools.Parameters{
+				&tools.FloatParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_float", Type: "float", Desc: "this param is a float"},
+					Enum:            []float64{0.5, 1.5},
+				},
+			},
+		},
+		{
+			name: "array with item counts",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of strings",
+					"minItems":    1,
+					"maxItems":    3,
+					"items": map[string]string{
+						"name":        "my_string",
+						"type":        "string",
+						"description": "string item",
+						"format":      "uuid",
+					},
+				},
+			},
+			want: tools.Parameters{
+				&tools.ArrayParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_array", Type: "array", Desc: "this param is an array of strings"},
+					Items: &tools.StringParameter{
+						CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "string item"},
+						Format:          "uuid",
+					},
+					MinItems: intPtr(1),
+					MaxItems: intPtr(3),
+				},
+			},
+		},
+		{
+			name: "object",
+			in: []map[string]any{
+				{
+					"name":        "my_object",
+					"type":        "object",
+					"description": "this param is an object",
+					"properties": []map[string]any{
+						{
+							"name":        "street",
+							"type":        "string",
+							"description": "street name",
+						},
+						{
+							"name":        "location",
+							"type":        "object",
+							"description": "coordinates",
+							"required":    false,
+							"properties": []map[string]any{
+								{
+									"name":        "lat",
+									"type":        "float",
+									"description": "latitude",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: tools.Parameters{
+				tools.NewObjectParameter("my_object", "this param is an object", tools.Parameters{
+					tools.NewStringParameter("street", "street name"),
+					tools.NewObjectParameterWithRequired("location", "coordinates", false, tools.Parameters{
+						tools.NewFloatParameter("lat", "latitude"),
+					}),
+				}),
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -903,6 +1033,291 @@ func TestParametersParse(t *testing.T) {
 			in:   map[string]any{},
 			want: tools.ParamValues{tools.ParamValue{Name: "my_map_not_required", Value: nil}},
 		},
+		{
+			name: "string in enum",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Enum:            []string{"asc", "desc"},
+				},
+			},
+			in: map[string]any{
+				"my_string": "asc",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_string", Value: "asc"}},
+		},
+		{
+			name: "string not in enum",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Enum:            []string{"asc", "desc"},
+				},
+			},
+			in: map[string]any{
+				"my_string": "random",
+			},
+		},
+		{
+			name: "string not matching pattern",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Pattern:         "^[A-Z]{2}$",
+				},
+			},
+			in: map[string]any{
+				"my_string": "CYX",
+			},
+		},
+		{
+			name: "string too short",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					MinLength:       intPtr(2),
+				},
+			},
+			in: map[string]any{
+				"my_string": "é",
+			},
+		},
+		{
+			name: "string too long",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					MaxLength:       intPtr(2),
+				},
+			},
+			in: map[string]any{
+				"my_string": "abc",
+			},
+		},
+		{
+			name: "string with date format",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Format:          "date",
+				},
+			},
+			in: map[string]any{
+				"my_string": "2025-01-31",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_string", Value: "2025-01-31"}},
+		},
+		{
+			name: "string with invalid date format",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Format:          "date",
+				},
+			},
+			in: map[string]any{
+				"my_string": "2025-02-31",
+			},
+		},
+		{
+			name: "string with date-time format",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Format:          "date-time",
+				},
+			},
+			in: map[string]any{
+				"my_string": "2025-01-31T10:00:00Z",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_string", Value: "2025-01-31T10:00:00Z"}},
+		},
+		{
+			name: "string with invalid email format",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Format:          "email",
+				},
+			},
+			in: map[string]any{
+				"my_string": "Jane <jane@example.com>",
+			},
+		},
+		{
+			name: "string with uuid format",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Format:          "uuid",
+				},
+			},
+			in: map[string]any{
+				"my_string": "123e4567-e89b-12d3-a456-426614174000",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_string", Value: "123e4567-e89b-12d3-a456-426614174000"}},
+		},
+		{
+			name: "string with invalid uuid format",
+			params: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_string", Type: "string", Desc: "this param is a string"},
+					Format:          "uuid",
+				},
+			},
+			in: map[string]any{
+				"my_string": "123e4567",
+			},
+		},
+		{
+			name: "int within range",
+			params: tools.Parameters{
+				&tools.IntParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_int", Type: "integer", Desc: "this param is an int"},
+					Minimum:         intPtr(1),
+					Maximum:         intPtr(100),
+				},
+			},
+			in: map[string]any{
+				"my_int": 100,
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_int", Value: 100}},
+		},
+		{
+			name: "int below minimum",
+			params: tools.Parameters{
+				&tools.IntParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_int", Type: "integer", Desc: "this param is an int"},
+					Minimum:         intPtr(1),
+				},
+			},
+			in: map[string]any{
+				"my_int": 0,
+			},
+		},
+		{
+			name: "int not in enum",
+			params: tools.Parameters{
+				&tools.IntParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_int", Type: "integer", Desc: "this param is an int"},
+					Enum:            []int{10, 20},
+				},
+			},
+			in: map[string]any{
+				"my_int": 15,
+			},
+		},
+		{
+			name: "float above maximum",
+			params: tools.Parameters{
+				&tools.FloatParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_float", Type: "float", Desc: "this param is a float"},
+					Maximum:         floatPtr(1.5),
+				},
+			},
+			in: map[string]any{
+				"my_float": 1.6,
+			},
+		},
+		{
+			name: "array with too few items",
+			params: tools.Parameters{
+				&tools.ArrayParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_array", Type: "array", Desc: "this param is an array of strings"},
+					Items:           tools.NewStringParameter("my_string", "string item"),
+					MinItems:        intPtr(2),
+				},
+			},
+			in: map[string]any{
+				"my_array": []string{"a"},
+			},
+		},
+		{
+			name: "array with item out of range",
+			params: tools.Parameters{
+				&tools.ArrayParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_array", Type: "array", Desc: "this param is an array of ints"},
+					Items: &tools.IntParameter{
+						CommonParameter: tools.CommonParameter{Name: "my_int", Type: "integer", Desc: "int item"},
+						Maximum:         intPtr(5),
+					},
+					MaxItems: intPtr(3),
+				},
+			},
+			in: map[string]any{
+				"my_array": []int{1, 6},
+			},
+		},
+		{
+			name: "object",
+			params: tools.Parameters{
+				tools.NewObjectParameter("my_object", "this param is an object", tools.Parameters{
+					tools.NewStringParameter("street", "street name"),
+					tools.NewIntParameterWithRequired("number", "house number", false),
+					tools.NewStringParameterWithDefault("country", "US", "country code"),
+					tools.NewObjectParameter("location", "coordinates", tools.Parameters{
+						tools.NewFloatParameter("lat", "latitude"),
+					}),
+				}),
+			},
+			in: map[string]any{
+				"my_object": map[string]any{
+					"street":   "Main St",
+					"location": map[string]any{"lat": 1.5},
+				},
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_object", Value: map[string]any{
+				"street":   "Main St",
+				"country":  "US",
+				"location": map[string]any{"lat": 1.5},
+			}}},
+		},
+		{
+			name: "object missing required property",
+			params: tools.Parameters{
+				tools.NewObjectParameter("my_object", "this param is an object", tools.Parameters{
+					tools.NewStringParameter("street", "street name"),
+				}),
+			},
+			in: map[string]any{
+				"my_object": map[string]any{},
+			},
+		},
+		{
+			name: "object with unknown property",
+			params: tools.Parameters{
+				tools.NewObjectParameter("my_object", "this param is an object", tools.Parameters{
+					tools.NewStringParameter("street", "street name"),
+				}),
+			},
+			in: map[string]any{
+				"my_object": map[string]any{"street": "Main St", "city": "Springfield"},
+			},
+		},
+		{
+			name: "object with invalid nested property",
+			params: tools.Parameters{
+				tools.NewObjectParameter("my_object", "this param is an object", tools.Parameters{
+					tools.NewObjectParameter("location", "coordinates", tools.Parameters{
+						tools.NewFloatParameter("lat", "latitude"),
+					}),
+				}),
+			},
+			in: map[string]any{
+				"my_object": map[string]any{"location": map[string]any{"lat": "north"}},
+			},
+		},
+		{
+			name: "not object",
+			params: tools.Parameters{
+				tools.NewObjectParameter("my_object", "this param is an object", tools.Parameters{
+					tools.NewStringParameter("street", "street name"),
+				}),
+			},
+			in: map[string]any{
+				"my_object": "Main St",
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1299,6 +1714,86 @@ func TestParamManifest(t *testing.T) {
 				AdditionalProperties: true,
 			},
 		},
+		{
+			name: "string with constraints",
+			in: &tools.StringParameter{
+				CommonParameter: tools.CommonParameter{Name: "foo-string", Type: "string", Desc: "bar"},
+				Enum:            []string{"a", "b"},
+				Pattern:         "^[a-z]$",
+				MinLength:       intPtr(1),
+				MaxLength:       intPtr(1),
+				Format:          "uuid",
+			},
+			want: tools.ParameterManifest{
+				Name:         "foo-string",
+				Type:         "string",
+				Required:     true,
+				Description:  "bar",
+				AuthServices: []string{},
+				Enum:         []any{"a", "b"},
+				Pattern:      "^[a-z]$",
+				MinLength:    intPtr(1),
+				MaxLength:    intPtr(1),
+				Format:       "uuid",
+			},
+		},
+		{
+			name: "int with constraints",
+			in: &tools.IntParameter{
+				CommonParameter: tools.CommonParameter{Name: "foo-int", Type: "integer", Desc: "bar"},
+				Enum:            []int{1, 2},
+				Minimum:         intPtr(1),
+				Maximum:         intPtr(2),
+			},
+			want: tools.ParameterManifest{
+				Name:         "foo-int",
+				Type:         "integer",
+				Required:     true,
+				Description:  "bar",
+				AuthServices: []string{},
+				Enum:         []any{1, 2},
+				Minimum:      floatPtr(1),
+				Maximum:      floatPtr(2),
+			},
+		},
+		{
+			name: "array with item counts",
+			in: &tools.ArrayParameter{
+				CommonParameter: tools.CommonParameter{Name: "foo-array", Type: "array", Desc: "bar"},
+				Items:           tools.NewStringParameter("foo-string", "bar"),
+				MinItems:        intPtr(1),
+				MaxItems:        intPtr(2),
+			},
+			want: tools.ParameterManifest{
+				Name:         "foo-array",
+				Type:         "array",
+				Required:     true,
+				Description:  "bar",
+				AuthServices: []string{},
+				Items:        &tools.ParameterManifest{Name: "foo-string", Type: "string", Required: true, Description: "bar", AuthServices: []string{}},
+				MinItems:     intPtr(1),
+				MaxItems:     intPtr(2),
+			},
+		},
+		{
+			name: "object",
+			in: tools.NewObjectParameter("foo-object", "bar", tools.Parameters{
+				tools.NewStringParameter("foo-string", "bar"),
+				tools.NewIntParameterWithRequired("foo-int", "bar", false),
+			}),
+			want: tools.ParameterManifest{
+				Name:         "foo-object",
+				Type:         "object",
+				Required:     true,
+				Description:  "bar",
+				AuthServices: []string{},
+				Properties: []tools.ParameterManifest{
+					{Name: "foo-string", Type: "string", Required: true, Description: "bar", AuthServices: []string{}},
+					{Name: "foo-int", Type: "integer", Required: false, Description: "bar", AuthServices: []string{}},
+				},
+				AdditionalProperties: false,
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1372,6 +1867,85 @@ func TestParamMcpManifest(t *testing.T) {
 			},
 			wantAuthParam: []string{},
 		},
+		{
+			name: "string with constraints",
+			in: &tools.StringParameter{
+				CommonParameter: tools.CommonParameter{Name: "foo-string", Type: "string", Desc: "bar"},
+				Enum:            []string{"a", "b"},
+				Pattern:         "^[a-z]$",
+				MinLength:       intPtr(1),
+				MaxLength:       intPtr(1),
+				Format:          "date",
+			},
+			want: tools.ParameterMcpManifest{
+				Type:        "string",
+				Description: "bar",
+				Enum:        []any{"a", "b"},
+				Pattern:     "^[a-z]$",
+				MinLength:   intPtr(1),
+				MaxLength:   intPtr(1),
+				Format:      "date",
+			},
+			wantAuthParam: []string{},
+		},
+		{
+			name: "float with constraints",
+			in: &tools.FloatParameter{
+				CommonParameter: tools.CommonParameter{Name: "foo-float", Type: "float", Desc: "bar"},
+				Minimum:         floatPtr(0.5),
+				Maximum:         floatPtr(1.5),
+			},
+			want: tools.ParameterMcpManifest{
+				Type:        "number",
+				Description: "bar",
+				Minimum:     floatPtr(0.5),
+				Maximum:     floatPtr(1.5),
+			},
+			wantAuthParam: []string{},
+		},
+		{
+			name: "array with item counts",
+			in: &tools.ArrayParameter{
+				CommonParameter: tools.CommonParameter{Name: "foo-array", Type: "array", Desc: "bar"},
+				Items:           tools.NewIntParameter("foo-int", "bar"),
+				MinItems:        intPtr(1),
+			},
+			want: tools.ParameterMcpManifest{
+				Type:        "array",
+				Description: "bar",
+				Items:       &tools.ParameterMcpManifest{Type: "integer", Description: "bar"},
+				MinItems:    intPtr(1),
+			},
+			wantAuthParam: []string{},
+		},
+		{
+			name: "object",
+			in: tools.NewObjectParameter("foo-object", "bar", tools.Parameters{
+				tools.NewStringParameter("foo-string", "bar"),
+				tools.NewObjectParameterWithRequired("foo-nested", "bar", false, tools.Parameters{
+					tools.NewArrayParameter("foo-array", "bar", tools.NewIntParameter("foo-int", "bar")),
+				}),
+			}),
+			want: tools.ParameterMcpManifest{
+				Type:        "object",
+				Description: "bar",
+				Properties: map[string]tools.ParameterMcpManifest{
+					"foo-string": {Type: "string", Description: "bar"},
+					"foo-nested": {
+						Type:        "object",
+						Description: "bar",
+						Properties: map[string]tools.ParameterMcpManifest{
+							"foo-array": {Type: "array", Description: "bar", Items: &tools.ParameterMcpManifest{Type: "integer", Description: "bar"}},
+						},
+						Required:             []string{"foo-array"},
+						AdditionalProperties: false,
+					},
+				},
+				Required:             []string{"foo-string"},
+				AdditionalProperties: false,
+			},
+			wantAuthParam: []string{},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1548,6 +2122,118 @@ func TestFailParametersUnmarshal(t *testing.T) {
 			},
 			err: "unsupported valueType \"not-a-real-type\" for map parameter",
 		},
+		{
+			name: "string parameter with invalid pattern",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"pattern":     "[a-z",
+				},
+			},
+			err: "invalid constraints for parameter \"my_string\": invalid pattern \"[a-z\"",
+		},
+		{
+			name: "string parameter with unsupported format",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"format":      "ipv4",
+				},
+			},
+			err: "invalid constraints for parameter \"my_string\": unsupported format \"ipv4\"",
+		},
+		{
+			name: "string parameter with default outside enum",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"enum":        []string{"asc", "desc"},
+					"default":     "random",
+				},
+			},
+			err: "invalid constraints for parameter \"my_string\": invalid default value: \"random\" is not one of [\"asc\" \"desc\"]",
+		},
+		{
+			name: "int parameter with minimum greater than maximum",
+			in: []map[string]any{
+				{
+					"name":        "my_integer",
+					"type":        "integer",
+					"description": "this param is an int",
+					"minimum":     10,
+					"maximum":     1,
+				},
+			},
+			err: "invalid constraints for parameter \"my_integer\": \"minimum\" (10) is greater than \"maximum\" (1)",
+		},
+		{
+			name: "array parameter with negative minItems",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of strings",
+					"minItems":    -1,
+					"items": map[string]string{
+						"name":        "my_string",
+						"type":        "string",
+						"description": "string item",
+					},
+				},
+			},
+			err: "invalid constraints for parameter \"my_array\": \"minItems\" must not be negative",
+		},
+		{
+			name: "object parameter missing properties",
+			in: []map[string]any{
+				{
+					"name":        "my_object",
+					"type":        "object",
+					"description": "this param is an object",
+				},
+			},
+			err: "object parameter \"my_object\" must have at least one property",
+		},
+		{
+			name: "object parameter with duplicate properties",
+			in: []map[string]any{
+				{
+					"name":        "my_object",
+					"type":        "object",
+					"description": "this param is an object",
+					"properties": []map[string]any{
+						{"name": "street", "type": "string", "description": "street name"},
+						{"name": "street", "type": "string", "description": "street name"},
+					},
+				},
+			},
+			err: "unable to parse 'properties' field: parameter name must be unique across all parameter fields. Duplicate parameter: street",
+		},
+		{
+			name: "object parameter with authenticated property",
+			in: []map[string]any{
+				{
+					"name":        "my_object",
+					"type":        "object",
+					"description": "this param is an object",
+					"properties": []map[string]any{
+						{
+							"name":         "email",
+							"type":         "string",
+							"description":  "user email",
+							"authServices": []map[string]string{{"name": "my-google-auth", "field": "email"}},
+						},
+					},
+				},
+			},
+			err: "nested properties should not have auth services",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1882,6 +2568,40 @@ func TestFailResolveTemplateParameters(t *testing.T) {
 	}
 }
 
+func TestTemplateParamReferences(t *testing.T) {
+	tcs := []struct {
+		name      string
+		statement string
+		want      []string
+	}{
+		{
+			name:      "no template parameters",
+			statement: "SELECT * FROM hotels WHERE name = $1",
+		},
+		{
+			name:      "multiple template parameters",
+			statement: "SELECT * FROM {{.tableName}} WHERE {{.columnName}} = 'Hilton' ORDER BY {{.columnName}}",
+			want:      []string{"tableName", "columnName"},
+		},
+		{
+			name:      "functions and conditions",
+			statement: "SELECT {{array .columnNames}} FROM hotels{{if .limit}} LIMIT {{.limit}}{{end}}",
+			want:      []string{"columnNames", "limit"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.TemplateParamReferences(tc.statement)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect template parameter references: diff %v", diff)
+			}
+		})
+	}
+}
+
 func TestCheckParamRequired(t *testing.T) {
 	tcs := []struct {
 		name     string
@@ -1923,3 +2643,11 @@ func TestCheckParamRequired(t *testing.T) {
 		})
 	}
 }
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
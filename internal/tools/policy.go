@@ -0,0 +1,579 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// Policy is a claim-based authorization policy, such as
+// `claims.email endsWith "@corp.com" && claims.groups contains "analysts"`.
+//
+// A policy is satisfied if the claims of any verified auth service satisfy its
+// expression. Expressions support the `==`, `!=`, `contains`, `startsWith`,
+// `endsWith`, `in` and `matches` operators, which can be combined with `&&`,
+// `||`, `!` and parentheses. A claim on its own is true if it is present and
+// is not false, zero or empty.
+type Policy struct {
+	// Source describes where the policy was declared, e.g. `tool "my_tool"`.
+	Source string
+	// Expr is the original expression of the policy.
+	Expr string
+	root policyNode
+}
+
+// PolicyDeniedError is returned when the claims of a request do not satisfy a Policy.
+type PolicyDeniedError struct {
+	Tool   string
+	Source string
+	Reason string
+}
+
+func (e *PolicyDeniedError) Error() string {
+	return fmt.Sprintf("tool %q invocation denied by %s policy: %s", e.Tool, e.Source, e.Reason)
+}
+
+// ParsePolicy compiles a policy expression.
+func ParsePolicy(source, expr string) (*Policy, error) {
+	toks, err := tokenizePolicy(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s policy: %w", source, err)
+	}
+	p := &policyParser{toks: toks}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s policy %q: %w", source, expr, err)
+	}
+	return &Policy{Source: source, Expr: expr, root: root}, nil
+}
+
+// Evaluate reports whether the claims of any verified auth service satisfy the
+// policy. If they don't, the reason for the denial is returned.
+func (p *Policy) Evaluate(claimsFromAuth map[string]map[string]any) (string, bool) {
+	if len(claimsFromAuth) == 0 {
+		return "no verified auth service claims", false
+	}
+	names := make([]string, 0, len(claimsFromAuth))
+	for name, claims := range claimsFromAuth {
+		if truthy(p.root.eval(claims)) {
+			return "", true
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return fmt.Sprintf("`%s` is not satisfied by the claims of %q", p.Expr, names), false
+}
+
+// validate interface
+var _ ToolConfig = PolicyConfig{}
+
+// PolicyConfig restricts the tool of a ToolConfig with a Policy declared
+// through the `allow` field of the tool.
+type PolicyConfig struct {
+	ToolConfig
+	Name  string
+	Allow string
+}
+
+// Initialize initializes the underlying tool and applies the policy to it.
+func (c PolicyConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	p, err := ParsePolicy(fmt.Sprintf("tool %q", c.Name), c.Allow)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return WithPolicies(t, p), nil
+}
+
+var _ Tool = policyTool{}
+
+// policyTool is a Tool restricted by authorization policies.
+type policyTool struct {
+	Tool
+	policies []*Policy
+}
+
+// WithPolicies returns a Tool that is restricted by the given policies, in
+// addition to any policies already applied to t.
+func WithPolicies(t Tool, policies ...*Policy) Tool {
+	if pt, ok := t.(policyTool); ok {
+		return policyTool{Tool: pt.Tool, policies: slices.Concat(pt.policies, policies)}
+	}
+	return policyTool{Tool: t, policies: policies}
+}
+
+// ToolTimeout returns the timeout of the restricted tool, so that policies do
+// not disable the timeout of the tool.
+func (t policyTool) ToolTimeout() time.Duration {
+	if tt, ok := t.Tool.(Timeouter); ok {
+		return tt.ToolTimeout()
+	}
+	return 0
+}
+
+// AuthorizePolicies checks the claims of a request against every policy that
+// applies to the tool. It returns a *PolicyDeniedError if any policy is not satisfied.
+func AuthorizePolicies(toolName string, t Tool, claimsFromAuth map[string]map[string]any) error {
+	pt, ok := t.(policyTool)
+	if !ok {
+		return nil
+	}
+	for _, p := range pt.policies {
+		if reason, ok := p.Evaluate(claimsFromAuth); !ok {
+			return &PolicyDeniedError{Tool: toolName, Source: p.Source, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// policyNode is a node of a compiled policy expression.
+type policyNode interface {
+	eval(claims map[string]any) any
+}
+
+type literalNode struct{ v any }
+
+func (n literalNode) eval(map[string]any) any { return n.v }
+
+type listNode []policyNode
+
+func (n listNode) eval(claims map[string]any) any {
+	rtn := make([]any, 0, len(n))
+	for _, e := range n {
+		rtn = append(rtn, e.eval(claims))
+	}
+	return rtn
+}
+
+// claimNode looks up a (possibly nested) claim. Missing claims evaluate to nil.
+type claimNode []string
+
+func (n claimNode) eval(claims map[string]any) any {
+	var v any = claims
+	for _, key := range n {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+type notNode struct{ n policyNode }
+
+func (n notNode) eval(claims map[string]any) any { return !truthy(n.n.eval(claims)) }
+
+type logicalNode struct {
+	and         bool
+	left, right policyNode
+}
+
+func (n logicalNode) eval(claims map[string]any) any {
+	l := truthy(n.left.eval(claims))
+	if n.and {
+		return l && truthy(n.right.eval(claims))
+	}
+	return l || truthy(n.right.eval(claims))
+}
+
+type compareNode struct {
+	op          string
+	left, right policyNode
+	re          *regexp.Regexp
+}
+
+func (n compareNode) eval(claims map[string]any) any {
+	l, r := n.left.eval(claims), n.right.eval(claims)
+	switch n.op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		return !equal(l, r)
+	case "contains":
+		switch lv := l.(type) {
+		case []any:
+			return slices.ContainsFunc(lv, func(e any) bool { return equal(e, r) })
+		case string:
+			rs, ok := r.(string)
+			return ok && strings.Contains(lv, rs)
+		}
+		return false
+	case "startsWith", "endsWith":
+		ls, lok := l.(string)
+		rs, rok := r.(string)
+		if !lok || !rok {
+			return false
+		}
+		if n.op == "startsWith" {
+			return strings.HasPrefix(ls, rs)
+		}
+		return strings.HasSuffix(ls, rs)
+	case "in":
+		rl, ok := r.([]any)
+		if !ok {
+			return false
+		}
+		in := func(e any) bool { return slices.ContainsFunc(rl, func(x any) bool { return equal(e, x) }) }
+		if ll, ok := l.([]any); ok {
+			return slices.ContainsFunc(ll, in)
+		}
+		return in(l)
+	case "matches":
+		ls, ok := l.(string)
+		return ok && n.re.MatchString(ls)
+	}
+	return false
+}
+
+// equal compares claim values, treating all numbers as float64.
+func equal(a, b any) bool {
+	if af, ok := asFloat(a); ok {
+		bf, ok := asFloat(b)
+		return ok && af == bf
+	}
+	switch a.(type) {
+	case string, bool:
+		return a == b
+	}
+	return false
+}
+
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []any:
+		return len(t) > 0
+	case map[string]any:
+		return len(t) > 0
+	}
+	if f, ok := asFloat(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+type policyTokenKind int
+
+const (
+	tokIdent policyTokenKind = iota
+	tokString
+	tokNumber
+	tokSymbol
+)
+
+type policyToken struct {
+	kind policyTokenKind
+	text string
+}
+
+// tokenizePolicy splits a policy expression into identifiers, strings, numbers and symbols.
+func tokenizePolicy(expr string) ([]policyToken, error) {
+	var toks []policyToken
+	rs := []rune(expr)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				if rs[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			text := string(rs[i : j+1])
+			if r == '\'' {
+				text = `"` + strings.ReplaceAll(string(rs[i+1:j]), `"`, `\"`) + `"`
+			}
+			s, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", string(rs[i:j+1]))
+			}
+			toks = append(toks, policyToken{tokString, s})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			toks = append(toks, policyToken{tokNumber, string(rs[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '-') {
+				j++
+			}
+			toks = append(toks, policyToken{tokIdent, string(rs[i:j])})
+			i = j
+		default:
+			if i+1 < len(rs) {
+				if two := string(rs[i : i+2]); slices.Contains([]string{"==", "!=", "&&", "||"}, two) {
+					toks = append(toks, policyToken{tokSymbol, two})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("!()[].,", r) {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+			toks = append(toks, policyToken{tokSymbol, string(r)})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+var policyOperators = []string{"==", "!=", "contains", "startsWith", "endsWith", "in", "matches"}
+
+// policyParser is a recursive descent parser for policy expressions.
+type policyParser struct {
+	toks []policyToken
+	pos  int
+}
+
+func (p *policyParser) peek() (policyToken, bool) {
+	if p.pos >= len(p.toks) {
+		return policyToken{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+// accept consumes the next token if it is one of the given symbols or keywords.
+func (p *policyParser) accept(texts ...string) (string, bool) {
+	t, ok := p.peek()
+	if !ok || t.kind == tokString || t.kind == tokNumber || !slices.Contains(texts, t.text) {
+		return "", false
+	}
+	p.pos++
+	return t.text, true
+}
+
+func (p *policyParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		if t, ok := p.peek(); ok {
+			return fmt.Errorf("expected %q, got %q", text, t.text)
+		}
+		return fmt.Errorf("expected %q at end of expression", text)
+	}
+	return nil
+}
+
+func (p *policyParser) parseOr() (policyNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: false, left: left, right: right}
+	}
+}
+
+func (p *policyParser) parseAnd() (policyNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: true, left: left, right: right}
+	}
+}
+
+func (p *policyParser) parseUnary() (policyNode, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	}
+	if _, ok := p.accept("("); ok {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+func (p *policyParser) parseComparison() (policyNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept(policyOperators...)
+	if !ok {
+		if _, isClaim := left.(claimNode); !isClaim {
+			return nil, fmt.Errorf("expected an operator after a literal")
+		}
+		return left, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	n := compareNode{op: op, left: left, right: right}
+	switch op {
+	case "matches":
+		lit, ok := right.(literalNode)
+		pattern, isString := lit.v.(string)
+		if !ok || !isString {
+			return nil, fmt.Errorf("'matches' requires a string pattern")
+		}
+		if n.re, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	case "in":
+		if _, ok := right.(listNode); !ok {
+			return nil, fmt.Errorf("'in' requires a list, e.g. [\"a\", \"b\"]")
+		}
+	}
+	return n, nil
+}
+
+func (p *policyParser) parseOperand() (policyNode, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	switch t.kind {
+	case tokString:
+		return literalNode{t.text}, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return literalNode{f}, nil
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return literalNode{t.text == "true"}, nil
+		case "claims":
+			return p.parseClaim()
+		}
+	case tokSymbol:
+		if t.text == "[" {
+			return p.parseList()
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q, expected a claim or a literal", t.text)
+}
+
+// parseClaim parses the path of a claim, e.g. `claims.address.country` or `claims["https://example.com/roles"]`.
+func (p *policyParser) parseClaim() (policyNode, error) {
+	var path claimNode
+	for {
+		if _, ok := p.accept("."); ok {
+			t, ok := p.peek()
+			if !ok || t.kind != tokIdent {
+				return nil, fmt.Errorf("expected a claim name after '.'")
+			}
+			p.pos++
+			path = append(path, t.text)
+			continue
+		}
+		if _, ok := p.accept("["); ok {
+			t, ok := p.peek()
+			if !ok || t.kind != tokString {
+				return nil, fmt.Errorf("expected a quoted claim name after '['")
+			}
+			p.pos++
+			path = append(path, t.text)
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("expected a claim name after 'claims'")
+	}
+	return path, nil
+}
+
+func (p *policyParser) parseList() (policyNode, error) {
+	var list listNode
+	if _, ok := p.accept("]"); ok {
+		return list, nil
+	}
+	for {
+		n, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := n.(literalNode); !ok {
+			return nil, fmt.Errorf("lists may only contain literals")
+		}
+		list = append(list, n)
+		if _, ok := p.accept(","); ok {
+			continue
+		}
+		return list, p.expect("]")
+	}
+}
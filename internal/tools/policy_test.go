@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	claims := map[string]any{
+		"email":                     "jane@corp.com",
+		"email_verified":            true,
+		"groups":                    []any{"analysts", "eng"},
+		"level":                     float64(3),
+		"address":                   map[string]any{"country": "US"},
+		"https://example.com/roles": []any{"admin"},
+	}
+	tcs := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{name: "equal", expr: `claims.email == "jane@corp.com"`, want: true},
+		{name: "not equal", expr: `claims.email != "jane@corp.com"`, want: false},
+		{name: "ends with", expr: `claims.email endsWith "@corp.com"`, want: true},
+		{name: "ends with other domain", expr: `claims.email endsWith "@other.com"`, want: false},
+		{name: "starts with", expr: `claims.email startsWith 'jane@'`, want: true},
+		{name: "list contains", expr: `claims.groups contains "analysts"`, want: true},
+		{name: "list does not contain", expr: `claims.groups contains "admins"`, want: false},
+		{name: "string contains", expr: `claims.email contains "@corp"`, want: true},
+		{name: "in list", expr: `claims.address.country in ["US", "CA"]`, want: true},
+		{name: "any of list in list", expr: `claims.groups in ["eng", "ops"]`, want: true},
+		{name: "matches", expr: `claims.email matches "^[a-z]+@corp\\.com$"`, want: true},
+		{name: "number", expr: `claims.level == 3`, want: true},
+		{name: "boolean claim", expr: `claims.email_verified`, want: true},
+		{name: "boolean comparison", expr: `claims.email_verified == false`, want: false},
+		{name: "missing claim", expr: `claims.department == "sales"`, want: false},
+		{name: "missing claim is falsy", expr: `!claims.department`, want: true},
+		{name: "bracket claim", expr: `claims["https://example.com/roles"] contains "admin"`, want: true},
+		{name: "and", expr: `claims.email endsWith "@corp.com" && claims.groups contains "analysts"`, want: true},
+		{name: "or", expr: `claims.groups contains "admins" || claims.level == 3`, want: true},
+		{name: "keywords", expr: `not (claims.groups contains "admins" or claims.level == 2) and claims.email_verified`, want: true},
+		{name: "precedence", expr: `claims.level == 1 && claims.email_verified || claims.groups contains "eng"`, want: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := tools.ParsePolicy(`tool "my_tool"`, tc.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			reason, got := p.Evaluate(map[string]map[string]any{"my-auth": claims})
+			if got != tc.want {
+				t.Fatalf("unexpected result: got %t, want %t (reason: %q)", got, tc.want, reason)
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluateAuthServices(t *testing.T) {
+	p, err := tools.ParsePolicy(`tool "my_tool"`, `claims.email endsWith "@corp.com"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if reason, ok := p.Evaluate(nil); ok || reason != "no verified auth service claims" {
+		t.Fatalf("unexpected result without claims: %t, %q", ok, reason)
+	}
+	claimsFromAuth := map[string]map[string]any{
+		"other-auth": {"email": "jane@other.com"},
+		"my-auth":    {"email": "jane@corp.com"},
+	}
+	if reason, ok := p.Evaluate(claimsFromAuth); !ok {
+		t.Fatalf("expected policy to be satisfied by any auth service: %q", reason)
+	}
+	delete(claimsFromAuth, "my-auth")
+	reason, ok := p.Evaluate(claimsFromAuth)
+	want := "`claims.email endsWith \"@corp.com\"` is not satisfied by the claims of [\"other-auth\"]"
+	if ok || reason != want {
+		t.Fatalf("unexpected result: got %t, %q, want %q", ok, reason, want)
+	}
+}
+
+func TestFailParsePolicy(t *testing.T) {
+	tcs := []struct {
+		name string
+		expr string
+		err  string
+	}{
+		{name: "empty", expr: ``, err: "unexpected end of expression"},
+		{name: "unknown operator", expr: `claims.email like "%@corp.com"`, err: `unexpected "like"`},
+		{name: "missing operand", expr: `claims.email ==`, err: "unexpected end of expression"},
+		{name: "literal without operator", expr: `"admin"`, err: "expected an operator after a literal"},
+		{name: "unterminated string", expr: `claims.email == "jane`, err: "unterminated string"},
+		{name: "unbalanced parentheses", expr: `(claims.email_verified`, err: `expected ")" at end of expression`},
+		{name: "claims without name", expr: `claims == "x"`, err: "expected a claim name after 'claims'"},
+		{name: "unknown identifier", expr: `user.email == "x"`, err: `unexpected "user", expected a claim or a literal`},
+		{name: "in without list", expr: `claims.group in "admins"`, err: "'in' requires a list"},
+		{name: "invalid pattern", expr: `claims.email matches "("`, err: "invalid pattern"},
+		{name: "invalid character", expr: `claims.level > 3`, err: "unexpected character '>'"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tools.ParsePolicy(`tool "my_tool"`, tc.expr)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want to contain %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestAuthorizePolicies(t *testing.T) {
+	toolPolicy, err := tools.ParsePolicy(`tool "my_tool"`, `claims.email_verified`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	toolsetPolicy, err := tools.ParsePolicy(`toolset "analyst_tools"`, `claims.groups contains "analysts"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// policies are evaluated independently of the tool they are applied to
+	var tool tools.Tool
+	restricted := tools.WithPolicies(tools.WithPolicies(tool, toolPolicy), toolsetPolicy)
+
+	if err := tools.AuthorizePolicies("my_tool", tool, nil); err != nil {
+		t.Fatalf("unexpected error for tool without policies: %s", err)
+	}
+	allowed := map[string]map[string]any{"my-auth": {"email_verified": true, "groups": []any{"analysts"}}}
+	if err := tools.AuthorizePolicies("my_tool", restricted, allowed); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	denied := map[string]map[string]any{"my-auth": {"email_verified": true, "groups": []any{"eng"}}}
+	err = tools.AuthorizePolicies("my_tool", restricted, denied)
+	var deniedErr *tools.PolicyDeniedError
+	if !errors.As(err, &deniedErr) {
+		t.Fatalf("expected a PolicyDeniedError, got %v", err)
+	}
+	if deniedErr.Tool != "my_tool" || deniedErr.Source != `toolset "analyst_tools"` {
+		t.Fatalf("unexpected denial: %+v", deniedErr)
+	}
+}
+
+// requiredInterfaces are the interfaces declared in this package that are not
+// optional tool interfaces.
+var requiredInterfaces = map[string]bool{
+	"Tool":             true,
+	"ToolConfig":       true,
+	"SourceToolConfig": true,
+	"Parameter":        true,
+}
+
+// TestPolicyToolForwardsOptionalInterfaces checks that a tool restricted by
+// policies still implements every optional tool interface declared in this
+// package, so that policies don't hide the behavior of the tools they apply to.
+func TestPolicyToolForwardsOptionalInterfaces(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("unable to parse package: %s", err)
+	}
+	policy, err := tools.ParsePolicy(`tool "my_tool"`, `claims.email_verified`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	restricted := reflect.TypeOf(tools.WithPolicies(nil, policy))
+
+	found := 0
+	for _, f := range pkgs["tools"].Files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				it, ok := ts.Type.(*ast.InterfaceType)
+				if !ok || !ts.Name.IsExported() || requiredInterfaces[ts.Name.Name] {
+					continue
+				}
+				found++
+				for _, m := range it.Methods.List {
+					for _, name := range m.Names {
+						if _, ok := restricted.MethodByName(name.Name); !ok {
+							t.Errorf("tools restricted by policies don't implement %s: missing method %s", ts.Name.Name, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatalf("no optional tool interfaces found")
+	}
+}
@@ -74,6 +74,39 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with output schema",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT id, name FROM hotels;
+					outputSchema:
+						- name: id
+						  type: integer
+						  description: hotel id
+						- name: name
+						  type: string
+						  description: hotel name
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT id, name FROM hotels;\n",
+					AuthRequired: []string{},
+					OutputSchema: []tools.Parameter{
+						tools.NewIntParameter("id", "hotel id"),
+						tools.NewStringParameter("name", "hotel name"),
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ResultFormat is the encoding of the rows returned by a tool.
+type ResultFormat string
+
+const (
+	// RowsJSON encodes each row as a JSON object. This is the default.
+	RowsJSON ResultFormat = "rows-json"
+	// ColumnarJSON encodes the rows as a single JSON object, with the names
+	// of the columns and an array of values per row.
+	ColumnarJSON ResultFormat = "columnar-json"
+	// CSV encodes the rows as CSV, with a header of the names of the columns.
+	CSV ResultFormat = "csv"
+	// Markdown encodes the rows as a Markdown table.
+	Markdown ResultFormat = "markdown"
+)
+
+// ResultFormats are the supported result formats.
+var ResultFormats = []ResultFormat{RowsJSON, ColumnarJSON, CSV, Markdown}
+
+// ParseResultFormat parses a result format. An empty string is the default
+// format of the tool.
+func ParseResultFormat(s string) (ResultFormat, error) {
+	f := ResultFormat(s)
+	if f != "" && !slices.Contains(ResultFormats, f) {
+		return "", fmt.Errorf("invalid result format %q: must be one of %q", s, ResultFormats)
+	}
+	return f, nil
+}
+
+type resultFormatKey struct{}
+
+// WithResultFormat returns a context with the result format requested by the
+// caller of a tool, which overrides the result format of the tool.
+func WithResultFormat(ctx context.Context, format ResultFormat) context.Context {
+	return context.WithValue(ctx, resultFormatKey{}, format)
+}
+
+// ResultFormatFromContext returns the result format requested by the caller
+// of a tool, or an empty string if there is none.
+func ResultFormatFromContext(ctx context.Context) ResultFormat {
+	f, _ := ctx.Value(resultFormatKey{}).(ResultFormat)
+	return f
+}
+
+// GetResultFormat returns the result format of an invocation: the format
+// requested by the caller, or else the format of the tool.
+func GetResultFormat(ctx context.Context, toolFormat ResultFormat) ResultFormat {
+	if f := ResultFormatFromContext(ctx); f != "" {
+		return f
+	}
+	return toolFormat
+}
+
+// EncodedRows are the rows of a tool, to be returned in a result format other
+// than rows-json.
+type EncodedRows struct {
+	// Format is the result format of the rows.
+	Format ResultFormat
+	// Columns are the names of the columns, in order.
+	Columns []string
+	// Result is the result of the tool in the rows-json format: a list of
+	// rows, TruncatedRows or PagedRows.
+	Result any
+}
+
+// Rows returns the rows, whether rows were dropped because of the result
+// limits, and the token of the next page if there is one.
+func (e EncodedRows) Rows() (rows []any, truncated bool, nextPageToken string) {
+	switch r := e.Result.(type) {
+	case TruncatedRows:
+		return r.Rows, r.Truncated, ""
+	case PagedRows:
+		return r.Rows, false, r.NextPageToken
+	case []any:
+		return r, false, ""
+	}
+	return nil, false, ""
+}
+
+// values returns the values of the columns of each row.
+func (e EncodedRows) values() [][]any {
+	rows, _, _ := e.Rows()
+	values := make([][]any, 0, len(rows))
+	for _, row := range rows {
+		m, _ := row.(map[string]any)
+		v := make([]any, len(e.Columns))
+		for i, col := range e.Columns {
+			v[i] = m[col]
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// Text returns the rows encoded in their result format. Columnar JSON also
+// has the `truncated`, `rowCount` and `nextPageToken` markers of the rows,
+// which are returned separately by Markers for the other formats.
+func (e EncodedRows) Text() (string, error) {
+	switch e.Format {
+	case ColumnarJSON:
+		rows, truncated, token := e.Rows()
+		b, err := json.Marshal(struct {
+			Columns       []string `json:"columns"`
+			Rows          [][]any  `json:"rows"`
+			Truncated     bool     `json:"truncated,omitempty"`
+			RowCount      int      `json:"rowCount"`
+			NextPageToken string   `json:"nextPageToken,omitempty"`
+		}{e.Columns, e.values(), truncated, len(rows), token})
+		if err != nil {
+			return "", fmt.Errorf("unable to encode rows: %w", err)
+		}
+		return string(b), nil
+	case CSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if len(e.Columns) > 0 {
+			_ = w.Write(e.Columns)
+		}
+		for _, v := range e.values() {
+			record := make([]string, len(v))
+			for i, cell := range v {
+				record[i] = cellText(cell)
+			}
+			_ = w.Write(record)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return "", fmt.Errorf("unable to encode rows: %w", err)
+		}
+		return buf.String(), nil
+	case Markdown:
+		if len(e.Columns) == 0 {
+			return "", nil
+		}
+		var sb strings.Builder
+		writeMarkdownRow(&sb, e.Columns)
+		sb.WriteString("|" + strings.Repeat(" --- |", len(e.Columns)) + "\n")
+		for _, v := range e.values() {
+			cells := make([]string, len(v))
+			for i, cell := range v {
+				cells[i] = cellText(cell)
+			}
+			writeMarkdownRow(&sb, cells)
+		}
+		return sb.String(), nil
+	}
+	return "", fmt.Errorf("unable to encode rows: unsupported result format %q", e.Format)
+}
+
+// Markers returns the items that tell the client that rows were left out, or
+// that there is a next page, for the formats that cannot hold them.
+func (e EncodedRows) Markers() []any {
+	if e.Format == ColumnarJSON {
+		return nil
+	}
+	rows, truncated, token := e.Rows()
+	switch {
+	case token != "":
+		return []any{map[string]any{"nextPageToken": token}}
+	case truncated:
+		return []any{map[string]any{"truncated": true, "rowCount": len(rows)}}
+	}
+	return nil
+}
+
+// cellText returns the text of a value in CSV and Markdown. NULL is an empty
+// cell, and values other than strings are encoded as JSON.
+func cellText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		// e.g. timestamps
+		return s
+	}
+	return string(b)
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func writeMarkdownRow(sb *strings.Builder, cells []string) {
+	sb.WriteString("|")
+	for _, c := range cells {
+		sb.WriteString(" " + markdownEscaper.Replace(c) + " |")
+	}
+	sb.WriteString("\n")
+}
+
+// EncodeResult encodes the result of a tool in a result format, if the
+// result is a list of rows. The columns are sorted by name, since rows do not
+// keep the order of their columns. Results that are already encoded, or that
+// are not rows, are returned as is.
+func EncodeResult(res any, format ResultFormat) any {
+	if format == "" || format == RowsJSON {
+		return res
+	}
+	e := EncodedRows{Format: format, Result: res}
+	switch res.(type) {
+	case []any, TruncatedRows, PagedRows:
+	default:
+		return res
+	}
+	rows, _, _ := e.Rows()
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		m, ok := row.(map[string]any)
+		if !ok {
+			return res
+		}
+		for col := range m {
+			if !seen[col] {
+				seen[col] = true
+				e.Columns = append(e.Columns, col)
+			}
+		}
+	}
+	slices.Sort(e.Columns)
+	return e
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseResultFormat(t *testing.T) {
+	for _, f := range []string{"", "rows-json", "columnar-json", "csv", "markdown"} {
+		if _, err := tools.ParseResultFormat(f); err != nil {
+			t.Fatalf("unexpected error for %q: %s", f, err)
+		}
+	}
+	if _, err := tools.ParseResultFormat("xml"); err == nil {
+		t.Fatalf("expected an error for an unsupported format")
+	}
+}
+
+func TestGetResultFormat(t *testing.T) {
+	ctx := context.Background()
+	if got := tools.GetResultFormat(ctx, tools.CSV); got != tools.CSV {
+		t.Fatalf("expected the format of the tool, got %q", got)
+	}
+	ctx = tools.WithResultFormat(ctx, tools.Markdown)
+	if got := tools.GetResultFormat(ctx, tools.CSV); got != tools.Markdown {
+		t.Fatalf("expected the requested format, got %q", got)
+	}
+}
+
+func TestEncodedRowsText(t *testing.T) {
+	rows := []any{
+		map[string]any{"id": int64(1), "name": "Alice", "note": "a|b\nc"},
+		map[string]any{"id": int64(2), "name": "Bob, Jr.", "note": nil},
+		map[string]any{"id": int64(3), "name": "Carol", "note": time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	columns := []string{"id", "name", "note"}
+	tcs := []struct {
+		desc    string
+		format  tools.ResultFormat
+		result  any
+		want    string
+		markers []any
+	}{
+		{
+			desc:   "columnar json",
+			format: tools.ColumnarJSON,
+			result: rows,
+			want:   `{"columns":["id","name","note"],"rows":[[1,"Alice","a|b\nc"],[2,"Bob, Jr.",null],[3,"Carol","2025-01-02T03:04:05Z"]],"rowCount":3}`,
+		},
+		{
+			desc:   "columnar json of truncated rows",
+			format: tools.ColumnarJSON,
+			result: tools.TruncatedRows{Rows: rows[:1], Truncated: true, RowCount: 1},
+			want:   `{"columns":["id","name","note"],"rows":[[1,"Alice","a|b\nc"]],"truncated":true,"rowCount":1}`,
+		},
+		{
+			desc:   "columnar json of a page",
+			format: tools.ColumnarJSON,
+			result: tools.PagedRows{Rows: rows[:1], NextPageToken: "next"},
+			want:   `{"columns":["id","name","note"],"rows":[[1,"Alice","a|b\nc"]],"rowCount":1,"nextPageToken":"next"}`,
+		},
+		{
+			desc:   "csv",
+			format: tools.CSV,
+			result: rows,
+			want:   "id,name,note\n1,Alice,\"a|b\nc\"\n2,\"Bob, Jr.\",\n3,Carol,2025-01-02T03:04:05Z\n",
+		},
+		{
+			desc:    "csv of truncated rows",
+			format:  tools.CSV,
+			result:  tools.TruncatedRows{Rows: rows[:1], Truncated: true, RowCount: 1},
+			want:    "id,name,note\n1,Alice,\"a|b\nc\"\n",
+			markers: []any{map[string]any{"truncated": true, "rowCount": 1}},
+		},
+		{
+			desc:   "markdown",
+			format: tools.Markdown,
+			result: rows,
+			want: "| id | name | note |\n" +
+				"| --- | --- | --- |\n" +
+				"| 1 | Alice | a\\|b<br>c |\n" +
+				"| 2 | Bob, Jr. |  |\n" +
+				"| 3 | Carol | 2025-01-02T03:04:05Z |\n",
+		},
+		{
+			desc:    "markdown of a page",
+			format:  tools.Markdown,
+			result:  tools.PagedRows{Rows: rows[1:2], NextPageToken: "next"},
+			want:    "| id | name | note |\n| --- | --- | --- |\n| 2 | Bob, Jr. |  |\n",
+			markers: []any{map[string]any{"nextPageToken": "next"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			e := tools.EncodedRows{Format: tc.format, Columns: columns, Result: tc.result}
+			got, err := e.Text()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect text (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.markers, e.Markers()); diff != "" {
+				t.Fatalf("incorrect markers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeResult(t *testing.T) {
+	rows := []any{
+		map[string]any{"b": 1, "a": 2},
+		map[string]any{"c": 3},
+	}
+
+	got := tools.EncodeResult(rows, tools.CSV)
+	want := tools.EncodedRows{Format: tools.CSV, Columns: []string{"a", "b", "c"}, Result: rows}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect result (-want +got):\n%s", diff)
+	}
+
+	for _, res := range []any{"done", []any{"a", "b"}, nil} {
+		if diff := cmp.Diff(res, tools.EncodeResult(res, tools.CSV)); diff != "" {
+			t.Fatalf("results other than rows should not be encoded (-want +got):\n%s", diff)
+		}
+	}
+	if diff := cmp.Diff(any(rows), tools.EncodeResult(rows, tools.RowsJSON)); diff != "" {
+		t.Fatalf("rows-json should not be encoded (-want +got):\n%s", diff)
+	}
+}
+
+func TestRowCollectorWithFormat(t *testing.T) {
+	rc := tools.NewRowCollector(sources.ResultLimits{MaxRows: 1}).WithFormat(tools.CSV, []string{"z", "a"})
+	rc.Add(map[string]any{"z": 1, "a": 2})
+	rc.Add(map[string]any{"z": 3, "a": 4})
+	e, ok := rc.Result().(tools.EncodedRows)
+	if !ok {
+		t.Fatalf("unexpected result type %T", rc.Result())
+	}
+	got, err := e.Text()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "z,a\n1,2\n"; got != want {
+		t.Fatalf("incorrect text: got %q, want %q", got, want)
+	}
+	if len(e.Markers()) != 1 {
+		t.Fatalf("expected the marker of the truncated rows, got %v", e.Markers())
+	}
+}
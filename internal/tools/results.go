@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"encoding/json"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// GetResultLimits returns the result limits of a tool, with the unset ones
+// taken from its source.
+func GetResultLimits(limits sources.ResultLimits, s sources.Source) sources.ResultLimits {
+	if l, ok := s.(sources.ResultLimiter); ok {
+		return limits.Or(l.SourceResultLimits())
+	}
+	return limits
+}
+
+// TruncatedRows is the result of a tool whose rows exceeded its result limits.
+type TruncatedRows struct {
+	// Rows are the rows returned, within the limits.
+	Rows []any `json:"rows"`
+	// Truncated is always true, to mark the result as truncated.
+	Truncated bool `json:"truncated"`
+	// RowCount is the number of rows returned.
+	RowCount int `json:"rowCount"`
+}
+
+// Items returns the rows, followed by an item that marks the result as
+// truncated.
+func (t TruncatedRows) Items() []any {
+	marker := map[string]any{"truncated": t.Truncated, "rowCount": t.RowCount}
+	return append(append(make([]any, 0, len(t.Rows)+1), t.Rows...), marker)
+}
+
+// RowCollector collects the rows of a query, up to the result limits.
+type RowCollector struct {
+	limits    sources.ResultLimits
+	page      Page
+	rows      []any
+	size      int
+	truncated bool
+	// more is set if there are rows after the page
+	more bool
+	// next is the first row that was dropped
+	next    any
+	format  ResultFormat
+	columns []string
+}
+
+// NewRowCollector creates a RowCollector with the given limits.
+func NewRowCollector(limits sources.ResultLimits) *RowCollector {
+	return &RowCollector{limits: limits}
+}
+
+// WithPage makes the collector stop at the end of the page. The query must
+// have been restricted to the page with Page.Apply. Rows that exceed the
+// result limits are left for the next page, which is only read by keyset
+// pagination if the columns of the rows are given with WithFormat.
+func (c *RowCollector) WithPage(page Page) *RowCollector {
+	c.page = page
+	return c
+}
+
+// WithFormat makes the collector return its rows in a result format, with the
+// given columns in order.
+func (c *RowCollector) WithFormat(format ResultFormat, columns []string) *RowCollector {
+	c.format = format
+	c.columns = columns
+	return c
+}
+
+// Add adds a row to the result. It returns false once a limit is exceeded or
+// the page is full, in which case the row is dropped and the caller should
+// stop reading rows.
+func (c *RowCollector) Add(row any) bool {
+	if c.truncated || c.more {
+		return false
+	}
+	if c.page.Size > 0 && len(c.rows) >= c.page.Size {
+		c.more, c.next = true, row
+		return false
+	}
+	if c.limits.MaxRows > 0 && len(c.rows) >= c.limits.MaxRows {
+		c.truncated, c.next = true, row
+		return false
+	}
+	if c.limits.MaxResultBytes > 0 {
+		b, err := json.Marshal(row)
+		if err == nil {
+			if c.size+len(b) > c.limits.MaxResultBytes {
+				c.truncated, c.next = true, row
+				return false
+			}
+			c.size += len(b)
+		}
+	}
+	c.rows = append(c.rows, row)
+	return true
+}
+
+// Truncated reports whether rows were dropped.
+func (c *RowCollector) Truncated() bool {
+	return c.truncated || c.more
+}
+
+// Rows returns the rows collected so far.
+func (c *RowCollector) Rows() []any {
+	return c.rows
+}
+
+// Result returns the collected rows, or TruncatedRows if rows were dropped.
+// Paginated results are returned as PagedRows, with the token of the next
+// page if rows were left for it, unless the first row of the page exceeds the
+// result limits. Rows in a result format other than rows-json are returned as
+// EncodedRows.
+func (c *RowCollector) Result() any {
+	if c.format == "" || c.format == RowsJSON {
+		return c.result()
+	}
+	return EncodedRows{Format: c.format, Columns: c.columns, Result: c.result()}
+}
+
+func (c *RowCollector) result() any {
+	if c.page.Size > 0 && (len(c.rows) > 0 || !c.truncated) {
+		rows := c.rows
+		if rows == nil {
+			rows = []any{}
+		}
+		page := PagedRows{Rows: rows}
+		if c.truncated || c.more {
+			page.NextPageToken = c.page.nextToken(c.rows, c.next, c.columns)
+		}
+		return page
+	}
+	if c.truncated {
+		rows := c.rows
+		if rows == nil {
+			rows = []any{}
+		}
+		return TruncatedRows{Rows: rows, Truncated: true, RowCount: len(c.rows)}
+	}
+	return c.rows
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestRowCollector(t *testing.T) {
+	row := func(id int) any { return map[string]any{"id": id} }
+	tcs := []struct {
+		desc   string
+		limits sources.ResultLimits
+		rows   int
+		want   any
+		added  int
+	}{
+		{
+			desc:  "no limits",
+			rows:  3,
+			want:  []any{row(0), row(1), row(2)},
+			added: 3,
+		},
+		{
+			desc:   "within max rows",
+			limits: sources.ResultLimits{MaxRows: 3},
+			rows:   3,
+			want:   []any{row(0), row(1), row(2)},
+			added:  3,
+		},
+		{
+			desc:   "over max rows",
+			limits: sources.ResultLimits{MaxRows: 2},
+			rows:   5,
+			want:   tools.TruncatedRows{Rows: []any{row(0), row(1)}, Truncated: true, RowCount: 2},
+			added:  2,
+		},
+		{
+			desc: "over max result bytes",
+			// each row is encoded as `{"id":0}`, which is 8 bytes
+			limits: sources.ResultLimits{MaxResultBytes: 20},
+			rows:   5,
+			want:   tools.TruncatedRows{Rows: []any{row(0), row(1)}, Truncated: true, RowCount: 2},
+			added:  2,
+		},
+		{
+			desc:   "first row over max result bytes",
+			limits: sources.ResultLimits{MaxResultBytes: 4},
+			rows:   1,
+			want:   tools.TruncatedRows{Rows: []any{}, Truncated: true, RowCount: 0},
+			added:  0,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			rc := tools.NewRowCollector(tc.limits)
+			added := 0
+			for i := 0; i < tc.rows; i++ {
+				if !rc.Add(row(i)) {
+					break
+				}
+				added++
+			}
+			if added != tc.added {
+				t.Fatalf("incorrect number of rows added: got %d, want %d", added, tc.added)
+			}
+			if diff := cmp.Diff(tc.want, rc.Result()); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestTruncatedRowsItems(t *testing.T) {
+	rows := tools.TruncatedRows{Rows: []any{"a", "b"}, Truncated: true, RowCount: 2}
+	want := []any{"a", "b", map[string]any{"truncated": true, "rowCount": 2}}
+	if diff := cmp.Diff(want, rows.Items()); diff != "" {
+		t.Fatalf("incorrect items: diff %v", diff)
+	}
+}
+
+// limitedSource is a source with default result limits.
+type limitedSource struct {
+	limits sources.ResultLimits
+}
+
+func (s limitedSource) SourceKind() string {
+	return "limited"
+}
+
+func (s limitedSource) SourceResultLimits() sources.ResultLimits {
+	return s.limits
+}
+
+func TestGetResultLimits(t *testing.T) {
+	src := limitedSource{limits: sources.ResultLimits{MaxRows: 100, MaxResultBytes: 1000}}
+	tcs := []struct {
+		desc   string
+		limits sources.ResultLimits
+		source sources.Source
+		want   sources.ResultLimits
+	}{
+		{
+			desc:   "source defaults",
+			source: src,
+			want:   sources.ResultLimits{MaxRows: 100, MaxResultBytes: 1000},
+		},
+		{
+			desc:   "tool overrides",
+			limits: sources.ResultLimits{MaxRows: 10},
+			source: src,
+			want:   sources.ResultLimits{MaxRows: 10, MaxResultBytes: 1000},
+		},
+		{
+			desc:   "source without limits",
+			limits: sources.ResultLimits{MaxRows: 10},
+			source: limitedSource{},
+			want:   sources.ResultLimits{MaxRows: 10},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tools.GetResultLimits(tc.limits, tc.source)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect limits: diff %v", diff)
+			}
+		})
+	}
+}
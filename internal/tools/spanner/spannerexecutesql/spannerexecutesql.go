@@ -16,7 +16,10 @@ package spannerexecutesql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"cloud.google.com/go/spanner"
 	yaml "github.com/goccy/go-yaml"
@@ -54,21 +57,35 @@ var _ compatibleSource = &spannerdb.Source{}
 var compatibleSources = [...]string{spannerdb.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
-	ReadOnly     bool     `yaml:"readOnly"`
+	sources.ResultLimits `yaml:",inline"`
+
+	Name         string             `yaml:"name" validate:"required"`
+	Kind         string             `yaml:"kind" validate:"required"`
+	Source       string             `yaml:"source" validate:"required"`
+	Description  string             `yaml:"description" validate:"required"`
+	Timeout      string             `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize" validate:"gte=0"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat" validate:"omitempty,oneof=rows-json columnar-json csv markdown"`
+	AuthRequired []string           `yaml:"authRequired"`
+	ReadOnly     bool               `yaml:"readOnly"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.SourceToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+func (cfg Config) ToolConfigInfo() tools.ToolConfigInfo {
+	return tools.ToolConfigInfo{
+		Source:       cfg.Source,
+		Description:  cfg.Description,
+		AuthRequired: cfg.AuthRequired,
+		PageSize:     cfg.PageSize,
+	}
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -83,14 +100,24 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	parameters := tools.WithPageTokenParameter(tools.Parameters{sqlParameter}, cfg.PageSize)
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
+	timeout, err := tools.GetTimeout(cfg.Timeout, rawS)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := Tool{
+		ResultLimits: tools.GetResultLimits(cfg.ResultLimits, rawS),
+
 		Name:         cfg.Name,
 		Kind:         kind,
+		Timeout:      timeout,
+		PageSize:     cfg.PageSize,
+		ResultFormat: cfg.ResultFormat,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
 		ReadOnly:     cfg.ReadOnly,
@@ -106,22 +133,46 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
-	ReadOnly     bool             `yaml:"readOnly"`
+	sources.ResultLimits
+
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Timeout      time.Duration      `yaml:"timeout"`
+	PageSize     int                `yaml:"pageSize"`
+	ResultFormat tools.ResultFormat `yaml:"resultFormat"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
+	ReadOnly     bool               `yaml:"readOnly"`
 	Client       *spanner.Client
 	dialect      string
 	manifest     tools.Manifest
 	mcpManifest  tools.McpManifest
 }
 
-// processRows iterates over the spanner.RowIterator and converts each row to a map[string]any.
-func processRows(iter *spanner.RowIterator) ([]any, error) {
-	var out []any
+// applyPage restricts the statement to the page, and adds the arguments that
+// bind the keys of the page to params, after the first n arguments.
+func applyPage(page tools.Page, statement string, params map[string]any, n int, dialect string) string {
+	d := tools.SpannerDialect
+	if strings.ToLower(dialect) == "postgresql" {
+		d = tools.SpannerPostgresDialect
+	}
+	query, args := page.Apply(statement, make([]any, n), d)
+	for _, arg := range args[n:] {
+		if a, ok := arg.(sql.NamedArg); ok {
+			params[a.Name] = a.Value
+		}
+	}
+	return query
+}
+
+// processRows iterates over the spanner.RowIterator and converts each row to a map[string]any,
+// up to the result limits and the size of the page, and returns them in the given result format.
+func processRows(iter *spanner.RowIterator, limits sources.ResultLimits, page tools.Page, format tools.ResultFormat) (any, error) {
+	rc := tools.NewRowCollector(limits).WithPage(page)
+	// stopping the iterator stops reading rows once the limits are exceeded
 	defer iter.Stop()
 
+	var cols []string
 	for {
 		row, err := iter.Next()
 		if err == iterator.Done {
@@ -132,13 +183,15 @@ func processRows(iter *spanner.RowIterator) ([]any, error) {
 		}
 
 		vMap := make(map[string]any)
-		cols := row.ColumnNames()
+		cols = row.ColumnNames()
 		for i, c := range cols {
 			vMap[c] = row.ColumnValue(i)
 		}
-		out = append(out, vMap)
+		if !rc.Add(vMap) {
+			break
+		}
 	}
-	return out, nil
+	return rc.WithFormat(format, cols).Result(), nil
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
@@ -147,6 +200,10 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
+	page, err := tools.GetPage(ctx, t.PageSize, params, t.Name, sql)
+	if err != nil {
+		return nil, err
+	}
 
 	// Log the query executed for debugging.
 	logger, err := util.LoggerFromContext(ctx)
@@ -155,18 +212,22 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
 
-	var results []any
+	var results any
 	var opErr error
-	stmt := spanner.Statement{SQL: sql}
+	pageParams := map[string]any{}
+	stmt := spanner.Statement{
+		SQL:    applyPage(page, sql, pageParams, 0, t.dialect),
+		Params: pageParams,
+	}
 
 	if t.ReadOnly {
 		iter := t.Client.Single().Query(ctx, stmt)
-		results, opErr = processRows(iter)
+		results, opErr = processRows(iter, t.ResultLimits, page, tools.GetResultFormat(ctx, t.ResultFormat))
 	} else {
 		_, opErr = t.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
 			var err error
 			iter := txn.Query(ctx, stmt)
-			results, err = processRows(iter)
+			results, err = processRows(iter, t.ResultLimits, page, tools.GetResultFormat(ctx, t.ResultFormat))
 			if err != nil {
 				return err
 			}
@@ -200,3 +261,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ToolTimeout returns the timeout of the invocations of the tool.
+func (t Tool) ToolTimeout() time.Duration {
+	return t.Timeout
+}
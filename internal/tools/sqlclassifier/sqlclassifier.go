@@ -0,0 +1,508 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package sqlclassifier classifies SQL statements as either read-only or write
+operations.
+
+It is used by the execute-sql tools as a second layer of protection in
+read-only mode, on top of the read-only transactions of the databases. The
+classifier is conservative: a statement is only classified as a read if it
+starts with a known read keyword, and does not contain any keyword that may
+modify data, the schema, the session or the transaction.
+
+It can handle:
+  - Multiple statements separated by semicolons.
+  - Line and block comments, which are ignored.
+  - String literals, quoted identifiers and PostgreSQL dollar-quoted strings,
+    which are ignored.
+  - MySQL backslash escapes, `#` comments and executable comments.
+*/
+package sqlclassifier
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// QueryType represents the classification of a SQL query as either read or write.
+type QueryType int
+
+const (
+	// ReadQuery indicates a query that only reads data.
+	ReadQuery QueryType = iota
+	// WriteQuery indicates a query that may modify data.
+	WriteQuery
+)
+
+// String provides a human-readable representation of the QueryType.
+func (qt QueryType) String() string {
+	if qt == ReadQuery {
+		return "READ"
+	}
+	return "WRITE"
+}
+
+// ErrWriteQuery is returned when a read-only tool is asked to execute a query
+// that may modify data.
+var ErrWriteQuery = errors.New("this tool is read-only and cannot execute write queries")
+
+// QueryClassification represents the result of a query classification.
+type QueryClassification struct {
+	// Type is the overall classification of the query (READ or WRITE).
+	Type QueryType
+	// WriteTokens is a list of keywords found that indicate a write operation.
+	WriteTokens []string
+}
+
+// readKeywords are the keywords a read-only statement may start with.
+var readKeywords = map[string]struct{}{
+	"SELECT": {}, "WITH": {}, "SHOW": {}, "EXPLAIN": {}, "DESCRIBE": {},
+	"DESC": {}, "VALUES": {}, "TABLE": {}, "PRAGMA": {},
+}
+
+// writeKeywords are the keywords that make a statement a write, wherever they
+// appear. This includes `INTO` for `SELECT ... INTO`, and the statements that
+// end or change the read-only transaction.
+var writeKeywords = map[string]struct{}{
+	"INSERT": {}, "UPDATE": {}, "DELETE": {}, "MERGE": {}, "UPSERT": {},
+	"REPLACE": {}, "CREATE": {}, "ALTER": {}, "DROP": {}, "TRUNCATE": {},
+	"RENAME": {}, "COMMENT": {}, "GRANT": {}, "REVOKE": {}, "INTO": {},
+	"CALL": {}, "EXEC": {}, "EXECUTE": {}, "DO": {}, "COPY": {}, "LOCK": {},
+	"SET": {}, "RESET": {}, "BEGIN": {}, "START": {}, "COMMIT": {},
+	"ROLLBACK": {}, "SAVEPOINT": {}, "RELEASE": {}, "ATTACH": {}, "DETACH": {},
+	"VACUUM": {}, "REINDEX": {}, "ANALYZE": {}, "LOAD": {}, "IMPORT": {},
+}
+
+// Classify analyzes a SQL string and returns a QueryClassification result.
+//
+// Each statement of the SQL string is tokenized, ignoring comments, string
+// literals and quoted identifiers. A statement is a read if its first keyword
+// is a known read keyword and none of its keywords is a known write keyword.
+// A SQLite `PRAGMA` is only a read if it does not assign a value.
+//
+// Dialects disagree on what is a comment or a literal, e.g. MySQL treats
+// backslashes in strings as escapes and `#` as a comment, whereas PostgreSQL
+// uses dollar-quoted strings. The SQL string is tokenized with both lexical
+// rules, and is only a read if it is a read under both of them.
+func Classify(sql string) QueryClassification {
+	result := QueryClassification{Type: ReadQuery}
+	seen := make(map[string]struct{})
+	for _, mysql := range []bool{false, true} {
+		for _, tok := range classifyStatements(tokenize(sql, mysql)) {
+			if _, ok := seen[tok]; ok {
+				continue
+			}
+			seen[tok] = struct{}{}
+			result.WriteTokens = append(result.WriteTokens, tok)
+		}
+	}
+	if len(result.WriteTokens) > 0 {
+		result.Type = WriteQuery
+	}
+	return result
+}
+
+// CheckReadOnly returns an error wrapping ErrWriteQuery if the SQL string may
+// modify data.
+func CheckReadOnly(sql string) error {
+	cf := Classify(sql)
+	if cf.Type == WriteQuery {
+		return fmt.Errorf("%w: found %s", ErrWriteQuery, strings.Join(cf.WriteTokens, ", "))
+	}
+	return nil
+}
+
+// ErrNoOrderBy is returned when a query to paginate has no ORDER BY clause.
+var ErrNoOrderBy = errors.New("paginated queries must have an ORDER BY clause that makes the order of the rows deterministic")
+
+// pageKeywords are the keywords of the clauses that already restrict the rows
+// of a query, which cannot be combined with the clause of a page.
+var pageKeywords = map[string]struct{}{
+	"LIMIT": {}, "OFFSET": {}, "FETCH": {}, "TOP": {},
+}
+
+// Pageable reports whether the rows of a SQL string can be paginated by
+// appending a LIMIT and OFFSET clause to it: it must be a single read-only
+// SELECT or WITH statement, without a LIMIT, OFFSET, FETCH or TOP clause of
+// its own outside of subqueries.
+//
+// It returns ErrNoOrderBy if such a statement has no ORDER BY clause outside
+// of subqueries, since the pages of rows in no particular order may overlap
+// or skip rows.
+func Pageable(sql string) (bool, error) {
+	if Classify(sql).Type == WriteQuery {
+		return false, nil
+	}
+	orderBy := true
+	for _, mysql := range []bool{false, true} {
+		var stmts [][]string
+		for _, stmt := range tokenize(sql, mysql) {
+			if len(stmt) > 0 {
+				stmts = append(stmts, stmt)
+			}
+		}
+		if len(stmts) != 1 {
+			return false, nil
+		}
+		stmt := stmts[0]
+		first := 0
+		for first < len(stmt) && stmt[first] == "(" {
+			first++
+		}
+		if first == len(stmt) || (stmt[first] != "SELECT" && stmt[first] != "WITH") {
+			return false, nil
+		}
+		depth, ordered := 0, false
+		for i, tok := range stmt {
+			switch tok {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+			if depth != 0 {
+				continue
+			}
+			if _, ok := pageKeywords[tok]; ok {
+				return false, nil
+			}
+			if tok == "ORDER" && i+1 < len(stmt) && stmt[i+1] == "BY" {
+				ordered = true
+			}
+		}
+		orderBy = orderBy && ordered
+	}
+	if !orderBy {
+		return false, ErrNoOrderBy
+	}
+	return true, nil
+}
+
+// OrderKey is a column of the ORDER BY clause of a query.
+type OrderKey struct {
+	// Column is the name of the column, as written in the query.
+	Column string
+	// Desc is set if the rows are sorted in descending order of the column.
+	Desc bool
+}
+
+// Ordering is the ORDER BY clause that ends a query.
+type Ordering struct {
+	// Keys are the columns the rows are sorted by.
+	Keys []OrderKey
+	// Start is the byte offset of the clause in the query.
+	Start int
+	// With is set if the query starts with a WITH clause.
+	With bool
+}
+
+// valueKeywords are the keywords that an ORDER BY clause may sort by that are
+// values rather than columns.
+var valueKeywords = map[string]struct{}{
+	"NULL": {}, "TRUE": {}, "FALSE": {}, "CURRENT_DATE": {}, "CURRENT_TIME": {},
+	"CURRENT_TIMESTAMP": {}, "CURRENT_USER": {}, "LOCALTIME": {},
+	"LOCALTIMESTAMP": {}, "SESSION_USER": {}, "USER": {}, "ASC": {}, "DESC": {},
+}
+
+// OrderBy returns the ORDER BY clause of a single read-only SELECT or WITH
+// statement, if the clause ends the statement and only sorts by columns given
+// by their unquoted name, each optionally followed by ASC or DESC. ok is false
+// otherwise, e.g. for columns given by their position or an expression,
+// qualified or quoted columns, or NULLS FIRST and NULLS LAST.
+func OrderBy(sql string) (ordering Ordering, ok bool) {
+	if Classify(sql).Type == WriteQuery {
+		return Ordering{}, false
+	}
+	for i, mysql := range []bool{false, true} {
+		o, ok := orderBy(sql, lex(sql, mysql))
+		if !ok {
+			return Ordering{}, false
+		}
+		if i > 0 && (o.Start != ordering.Start || o.With != ordering.With || !slices.Equal(o.Keys, ordering.Keys)) {
+			return Ordering{}, false
+		}
+		ordering = o
+	}
+	return ordering, true
+}
+
+// orderBy returns the ORDER BY clause that ends the single statement of a SQL
+// string, see OrderBy.
+func orderBy(sql string, stmts [][]token) (Ordering, bool) {
+	var stmt []token
+	for _, s := range stmts {
+		if len(s) == 0 {
+			continue
+		}
+		if stmt != nil {
+			return Ordering{}, false
+		}
+		stmt = s
+	}
+	first := 0
+	for first < len(stmt) && stmt[first].text == "(" {
+		first++
+	}
+	if first == len(stmt) || (stmt[first].text != "SELECT" && stmt[first].text != "WITH") {
+		return Ordering{}, false
+	}
+	// the last ORDER BY outside of subqueries
+	depth, at := 0, -1
+	for i, tok := range stmt {
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 && tok.word && tok.text == "ORDER" && i+1 < len(stmt) && stmt[i+1].text == "BY" {
+			at = i
+		}
+	}
+	if at < 0 {
+		return Ordering{}, false
+	}
+	ordering := Ordering{Start: stmt[at].start, With: stmt[first].text == "WITH"}
+	rest := stmt[at+2:]
+	for {
+		if len(rest) == 0 || !rest[0].word {
+			return Ordering{}, false
+		}
+		if _, ok := valueKeywords[rest[0].text]; ok {
+			return Ordering{}, false
+		}
+		key := OrderKey{Column: sql[rest[0].start:rest[0].end]}
+		rest = rest[1:]
+		if len(rest) > 0 && (rest[0].text == "ASC" || rest[0].text == "DESC") {
+			key.Desc = rest[0].text == "DESC"
+			rest = rest[1:]
+		}
+		ordering.Keys = append(ordering.Keys, key)
+		if len(rest) == 0 {
+			return ordering, true
+		}
+		if rest[0].text != "," {
+			return Ordering{}, false
+		}
+		rest = rest[1:]
+	}
+}
+
+// classifyStatements returns the tokens of the statements that indicate a
+// write operation.
+func classifyStatements(stmts [][]string) []string {
+	var writeTokens []string
+	for _, stmt := range stmts {
+		// parentheses around the statement, e.g. `(SELECT 1) UNION (SELECT 2)`
+		for len(stmt) > 0 && stmt[0] == "(" {
+			stmt = stmt[1:]
+		}
+		if len(stmt) == 0 {
+			continue
+		}
+		if _, ok := readKeywords[stmt[0]]; !ok {
+			writeTokens = append(writeTokens, stmt[0])
+		}
+		for _, tok := range stmt[1:] {
+			if _, ok := writeKeywords[tok]; ok {
+				writeTokens = append(writeTokens, tok)
+			}
+		}
+		if stmt[0] == "PRAGMA" && pragmaWrites(stmt) {
+			writeTokens = append(writeTokens, "PRAGMA")
+		}
+	}
+	return writeTokens
+}
+
+// readPragmas are the SQLite pragmas that only read, even with an argument,
+// such as `PRAGMA table_info(users)`.
+var readPragmas = map[string]struct{}{
+	"TABLE_INFO": {}, "TABLE_XINFO": {}, "TABLE_LIST": {}, "INDEX_INFO": {},
+	"INDEX_XINFO": {}, "INDEX_LIST": {}, "FOREIGN_KEY_LIST": {},
+	"FOREIGN_KEY_CHECK": {}, "INTEGRITY_CHECK": {}, "QUICK_CHECK": {},
+}
+
+// writePragmas are the SQLite pragmas that modify the database without an
+// argument.
+var writePragmas = map[string]struct{}{
+	"OPTIMIZE": {}, "INCREMENTAL_VACUUM": {}, "WAL_CHECKPOINT": {},
+	"SHRINK_MEMORY": {},
+}
+
+// pragmaWrites reports whether a `PRAGMA` statement may modify the database.
+// A pragma with a value, e.g. `PRAGMA user_version = 5` or
+// `PRAGMA journal_mode(DELETE)`, sets it, unless the pragma only reads.
+func pragmaWrites(stmt []string) bool {
+	// the name is the last keyword before the value, after the schema
+	name := ""
+	for _, tok := range stmt[1:] {
+		switch tok {
+		case "=":
+			return true
+		case "(":
+			_, ok := readPragmas[name]
+			return !ok
+		}
+		name = tok
+	}
+	_, ok := writePragmas[name]
+	return ok
+}
+
+// token is a token of a SQL statement.
+type token struct {
+	// text is the upper-cased keyword or unquoted identifier, or the
+	// punctuation character of the token. It is empty for literals, quoted
+	// identifiers and dollar-quoted strings.
+	text string
+	// word is set for keywords and unquoted identifiers.
+	word bool
+	// start and end are the byte offsets of the token in the SQL string.
+	start, end int
+}
+
+// tokenize splits a SQL string into statements of upper-cased keywords, `=`
+// and parenthesis tokens. Comments, literals and quoted identifiers are dropped. If mysql
+// is true, MySQL lexical rules are used instead of the standard ones.
+func tokenize(sql string, mysql bool) [][]string {
+	var stmts [][]string
+	for _, toks := range lex(sql, mysql) {
+		var stmt []string
+		for _, tok := range toks {
+			if tok.word || tok.text == "=" || tok.text == "(" || tok.text == ")" {
+				stmt = append(stmt, tok.text)
+			}
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+// lex splits a SQL string into statements of tokens, without comments. If
+// mysql is true, MySQL lexical rules are used instead of the standard ones.
+func lex(sql string, mysql bool) [][]token {
+	var stmts [][]token
+	var stmt []token
+	rs := []rune(sql)
+	// the byte offset of each rune, and of the end of the string
+	offsets := make([]int, 0, len(rs)+1)
+	for i := range sql {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(sql))
+	add := func(text string, word bool, start, end int) {
+		stmt = append(stmt, token{text: text, word: word, start: offsets[start], end: offsets[end]})
+	}
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		start := i
+		switch {
+		case r == ';':
+			stmts = append(stmts, stmt)
+			stmt = nil
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-' && (!mysql || i+2 >= len(rs) || unicode.IsSpace(rs[i+2]) || unicode.IsControl(rs[i+2])),
+			r == '#' && mysql:
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+2 < len(rs) && rs[i+1] == '*' && rs[i+2] == '!' && mysql:
+			// MySQL executes the content of `/*! ... */` comments.
+			i += 3
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			i = skipTo(rs, i+2, []rune("*/"))
+		case r == '\'' || r == '"' || r == '`':
+			i = skipQuoted(rs, i+1, r, mysql)
+			add("", false, start, i)
+		case r == '[':
+			i = skipTo(rs, i+1, []rune("]"))
+			add("", false, start, i)
+		case r == '$' && !mysql:
+			i = skipDollarQuoted(rs, i)
+			if i == start+1 {
+				// a positional parameter such as `$1`
+				add("$", false, start, i)
+			} else {
+				add("", false, start, i)
+			}
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_' || rs[i] == '$') {
+				i++
+			}
+			word := strings.ToUpper(string(rs[start:i]))
+			if word == "E" && i < len(rs) && rs[i] == '\'' {
+				// PostgreSQL escape strings, such as E'it\'s'.
+				i = skipQuoted(rs, i+1, '\'', true)
+				add("", false, start, i)
+				continue
+			}
+			add(word, true, start, i)
+		default:
+			i++
+			add(string(r), false, start, i)
+		}
+	}
+	return append(stmts, stmt)
+}
+
+// skipTo returns the index following the next occurrence of end, starting at
+// i, or the length of rs if there is none.
+func skipTo(rs []rune, i int, end []rune) int {
+	for ; i+len(end) <= len(rs); i++ {
+		if string(rs[i:i+len(end)]) == string(end) {
+			return i + len(end)
+		}
+	}
+	return len(rs)
+}
+
+// skipQuoted returns the index following the closing quote of a literal or
+// identifier starting at i. Doubled quotes are skipped, and so are backslash
+// escapes if backslash is true.
+func skipQuoted(rs []rune, i int, quote rune, backslash bool) int {
+	for ; i < len(rs); i++ {
+		switch {
+		case rs[i] == '\\' && backslash:
+			i++
+		case rs[i] == quote:
+			if i+1 < len(rs) && rs[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(rs)
+}
+
+// skipDollarQuoted returns the index following a PostgreSQL dollar-quoted
+// string starting at i, such as `$$...$$` or `$tag$...$tag$`. Positional
+// parameters such as `$1` are skipped on their own.
+func skipDollarQuoted(rs []rune, i int) int {
+	j := i + 1
+	for j < len(rs) && (unicode.IsLetter(rs[j]) || rs[j] == '_' || (j > i+1 && unicode.IsDigit(rs[j]))) {
+		j++
+	}
+	if j >= len(rs) || rs[j] != '$' {
+		return i + 1
+	}
+	return skipTo(rs, j+1, rs[i:j+1])
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlclassifier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlclassifier"
+)
+
+func TestClassify(t *testing.T) {
+	tcs := []struct {
+		desc  string
+		sql   string
+		want  sqlclassifier.QueryType
+		write []string
+	}{
+		// Read queries
+		{desc: "select", sql: "SELECT * FROM users", want: sqlclassifier.ReadQuery},
+		{desc: "lowercase select", sql: "select id from users where name = 'x'", want: sqlclassifier.ReadQuery},
+		{desc: "cte", sql: "WITH u AS (SELECT * FROM users) SELECT * FROM u", want: sqlclassifier.ReadQuery},
+		{desc: "explain", sql: "EXPLAIN SELECT 1", want: sqlclassifier.ReadQuery},
+		{desc: "show", sql: "SHOW TABLES", want: sqlclassifier.ReadQuery},
+		{desc: "multiple reads", sql: "SELECT 1; SELECT 2;", want: sqlclassifier.ReadQuery},
+		{desc: "keyword in string", sql: "SELECT * FROM t WHERE note = 'DELETE FROM users'", want: sqlclassifier.ReadQuery},
+		{desc: "keyword in quoted identifier", sql: `SELECT "update", ` + "`drop`" + `, [insert] FROM t`, want: sqlclassifier.ReadQuery},
+		{desc: "keyword in comment", sql: "SELECT 1 -- DROP TABLE users\n/* DELETE */", want: sqlclassifier.ReadQuery},
+		{desc: "keyword in dollar quote", sql: "SELECT $tag$DROP TABLE users$tag$", want: sqlclassifier.ReadQuery},
+		{desc: "keyword in column name", sql: "SELECT updated_at, deleted FROM users", want: sqlclassifier.ReadQuery},
+		{desc: "positional parameter", sql: "SELECT * FROM users WHERE id = $1", want: sqlclassifier.ReadQuery},
+		{desc: "read pragma", sql: "PRAGMA table_info(users)", want: sqlclassifier.ReadQuery},
+		{desc: "read pragma of schema", sql: "PRAGMA main.index_list(users)", want: sqlclassifier.ReadQuery},
+		{desc: "pragma query", sql: "PRAGMA journal_mode", want: sqlclassifier.ReadQuery},
+		{desc: "parenthesized select", sql: "(SELECT 1) UNION (SELECT 2)", want: sqlclassifier.ReadQuery},
+		{desc: "empty", sql: " ; ", want: sqlclassifier.ReadQuery},
+		// Write queries
+		{desc: "insert", sql: "INSERT INTO users VALUES (1)", want: sqlclassifier.WriteQuery, write: []string{"INSERT", "INTO"}},
+		{desc: "update", sql: "update users set name = 'x'", want: sqlclassifier.WriteQuery, write: []string{"UPDATE", "SET"}},
+		{desc: "ddl", sql: "DROP TABLE users", want: sqlclassifier.WriteQuery, write: []string{"DROP"}},
+		{desc: "read then write", sql: "SELECT 1; DELETE FROM users", want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+		{desc: "write without semicolon", sql: "SELECT 1 DELETE FROM users", want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+		{desc: "writable cte", sql: "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+		{desc: "select into", sql: "SELECT * INTO users_copy FROM users", want: sqlclassifier.WriteQuery, write: []string{"INTO"}},
+		{desc: "explain analyze", sql: "EXPLAIN ANALYZE DELETE FROM users", want: sqlclassifier.WriteQuery, write: []string{"ANALYZE", "DELETE"}},
+		{desc: "transaction", sql: "COMMIT; DROP TABLE users", want: sqlclassifier.WriteQuery, write: []string{"COMMIT", "DROP"}},
+		{desc: "set", sql: "SET TRANSACTION READ WRITE", want: sqlclassifier.WriteQuery, write: []string{"SET"}},
+		{desc: "write pragma", sql: "PRAGMA query_only = OFF", want: sqlclassifier.WriteQuery, write: []string{"PRAGMA"}},
+		{desc: "write pragma with argument", sql: "PRAGMA user_version(5)", want: sqlclassifier.WriteQuery, write: []string{"PRAGMA"}},
+		{desc: "write pragma of schema", sql: "PRAGMA main.journal_mode(WAL)", want: sqlclassifier.WriteQuery, write: []string{"PRAGMA"}},
+		{desc: "write pragma without argument", sql: "PRAGMA optimize", want: sqlclassifier.WriteQuery, write: []string{"PRAGMA"}},
+		{desc: "unknown statement", sql: "VACUUM", want: sqlclassifier.WriteQuery, write: []string{"VACUUM"}},
+		{desc: "mysql backslash escape", sql: `SELECT 'a\' '; DELETE FROM users; -- '`, want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+		{desc: "postgres backslash", sql: `SELECT '\'; DELETE FROM users; -- '`, want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+		{desc: "mysql dollar identifier", sql: "SELECT $a$; DELETE FROM users; -- $a$", want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+		{desc: "mysql double dash without space", sql: "SELECT 1--1; DELETE FROM users", want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+		{desc: "mysql executable comment", sql: "SELECT 1 /*! ; DELETE FROM users */", want: sqlclassifier.WriteQuery, write: []string{"DELETE"}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := sqlclassifier.Classify(tc.sql)
+			if got.Type != tc.want {
+				t.Fatalf("incorrect type: got %s, want %s", got.Type, tc.want)
+			}
+			if diff := cmp.Diff(tc.write, got.WriteTokens); diff != "" {
+				t.Fatalf("incorrect write tokens: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestCheckReadOnly(t *testing.T) {
+	if err := sqlclassifier.CheckReadOnly("SELECT 1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err := sqlclassifier.CheckReadOnly("DROP TABLE users")
+	if !errors.Is(err, sqlclassifier.ErrWriteQuery) {
+		t.Fatalf("expected ErrWriteQuery, got %v", err)
+	}
+	want := "this tool is read-only and cannot execute write queries: found DROP"
+	if err.Error() != want {
+		t.Fatalf("incorrect error: got %q, want %q", err, want)
+	}
+}
+
+func TestPageable(t *testing.T) {
+	tcs := []struct {
+		desc string
+		sql  string
+		want bool
+		err  error
+	}{
+		{desc: "ordered select", sql: "SELECT * FROM users ORDER BY id;", want: true},
+		{desc: "ordered cte", sql: "WITH u AS (SELECT * FROM users LIMIT 5) SELECT * FROM u ORDER BY id", want: true},
+		{desc: "ordered union", sql: "(SELECT id FROM a) UNION (SELECT id FROM b) ORDER BY id", want: true},
+		{desc: "order by in comment", sql: "SELECT * FROM users -- ORDER BY id", err: sqlclassifier.ErrNoOrderBy},
+		{desc: "order by in subquery", sql: "SELECT * FROM (SELECT * FROM users ORDER BY id) u", err: sqlclassifier.ErrNoOrderBy},
+		{desc: "unordered select", sql: "SELECT * FROM users", err: sqlclassifier.ErrNoOrderBy},
+		{desc: "limit", sql: "SELECT * FROM users ORDER BY id LIMIT 10"},
+		{desc: "fetch", sql: "SELECT * FROM users ORDER BY id FETCH FIRST 10 ROWS ONLY"},
+		{desc: "top", sql: "SELECT TOP 10 * FROM users ORDER BY id"},
+		{desc: "multiple statements", sql: "SELECT 1 ORDER BY 1; SELECT 2 ORDER BY 1"},
+		{desc: "show", sql: "SHOW TABLES"},
+		{desc: "pragma", sql: "PRAGMA table_info(users)"},
+		{desc: "write", sql: "DELETE FROM users RETURNING id ORDER BY id"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := sqlclassifier.Pageable(tc.sql)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("incorrect error: got %v, want %v", err, tc.err)
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	tcs := []struct {
+		desc string
+		sql  string
+		want sqlclassifier.Ordering
+		ok   bool
+	}{
+		{
+			desc: "single column",
+			sql:  "SELECT * FROM users ORDER BY id;",
+			want: sqlclassifier.Ordering{Keys: []sqlclassifier.OrderKey{{Column: "id"}}, Start: 20},
+			ok:   true,
+		},
+		{
+			desc: "directions",
+			sql:  "SELECT * FROM users ORDER BY Name DESC, id ASC -- the newest first",
+			want: sqlclassifier.Ordering{Keys: []sqlclassifier.OrderKey{{Column: "Name", Desc: true}, {Column: "id"}}, Start: 20},
+			ok:   true,
+		},
+		{
+			desc: "cte",
+			sql:  "WITH u AS (SELECT * FROM users ORDER BY name) SELECT * FROM u ORDER BY id",
+			want: sqlclassifier.Ordering{Keys: []sqlclassifier.OrderKey{{Column: "id"}}, Start: 62, With: true},
+			ok:   true,
+		},
+		{
+			desc: "multibyte characters",
+			sql:  "SELECT 'é' AS e, id FROM users ORDER BY id",
+			want: sqlclassifier.Ordering{Keys: []sqlclassifier.OrderKey{{Column: "id"}}, Start: 32},
+			ok:   true,
+		},
+		{desc: "position", sql: "SELECT id FROM users ORDER BY 1"},
+		{desc: "expression", sql: "SELECT id FROM users ORDER BY lower(name)"},
+		{desc: "qualified column", sql: "SELECT u.id FROM users u ORDER BY u.id"},
+		{desc: "quoted column", sql: `SELECT "id" FROM users ORDER BY "id"`},
+		{desc: "nulls last", sql: "SELECT id FROM users ORDER BY id NULLS LAST"},
+		{desc: "value", sql: "SELECT id FROM users ORDER BY NULL"},
+		{desc: "order by in subquery", sql: "SELECT * FROM (SELECT * FROM users ORDER BY id) u"},
+		{desc: "unordered", sql: "SELECT * FROM users"},
+		{desc: "multiple statements", sql: "SELECT id FROM a ORDER BY id; SELECT id FROM b ORDER BY id"},
+		{desc: "write", sql: "DELETE FROM users RETURNING id ORDER BY id"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, ok := sqlclassifier.OrderBy(tc.sql)
+			if ok != tc.ok {
+				t.Fatalf("incorrect result: got %t, want %t", ok, tc.ok)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect ordering (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,14 +17,18 @@ package sqliteexecutesql_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlclassifier"
 	"github.com/googleapis/genai-toolbox/internal/tools/sqlite/sqliteexecutesql"
 	_ "modernc.org/sqlite"
 )
@@ -61,6 +65,112 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "read-only example",
+			in: `
+			tools:
+				example_tool:
+					kind: sqlite-execute-sql
+					source: my-instance
+					description: some description
+					readOnly: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sqliteexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "sqlite-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					ReadOnly:     true,
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "result limits example",
+			in: `
+			tools:
+				example_tool:
+					kind: sqlite-execute-sql
+					source: my-instance
+					description: some description
+					maxRows: 100
+					maxResultBytes: 65536
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sqliteexecutesql.Config{
+					ResultLimits: sources.ResultLimits{MaxRows: 100, MaxResultBytes: 65536},
+					Name:         "example_tool",
+					Kind:         "sqlite-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "timeout example",
+			in: `
+			tools:
+				example_tool:
+					kind: sqlite-execute-sql
+					source: my-instance
+					description: some description
+					timeout: 30s
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sqliteexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "sqlite-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					Timeout:      "30s",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "pagination example",
+			in: `
+			tools:
+				example_tool:
+					kind: sqlite-execute-sql
+					source: my-instance
+					description: some description
+					pageSize: 100
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sqliteexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "sqlite-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					PageSize:     100,
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "result format example",
+			in: `
+			tools:
+				example_tool:
+					kind: sqlite-execute-sql
+					source: my-instance
+					description: some description
+					resultFormat: csv
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sqliteexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "sqlite-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					ResultFormat: tools.CSV,
+					AuthRequired: []string{},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -99,6 +209,8 @@ func TestTool_Invoke(t *testing.T) {
 		Kind         string
 		AuthRequired []string
 		Parameters   tools.Parameters
+		ReadOnly     bool
+		ResultLimits sources.ResultLimits
 		DB           *sql.DB
 	}
 	type args struct {
@@ -184,6 +296,77 @@ func TestTool_Invoke(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "read-only select data",
+			fields: fields{
+				ReadOnly: true,
+				DB: func() *sql.DB {
+					db := setupTestDB(t)
+					if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, age INTEGER); INSERT INTO users (id, name, age) VALUES (1, 'Alice', 30)"); err != nil {
+						t.Fatalf("Failed to set up database for select: %v", err)
+					}
+					return db
+				}(),
+			},
+			args: args{
+				ctx: ctx,
+				params: []tools.ParamValue{
+					{Name: "sql", Value: "SELECT * FROM users"},
+				},
+			},
+			want: []any{
+				map[string]any{"id": int64(1), "name": "Alice", "age": int64(30)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "read-only drop table",
+			fields: fields{
+				ReadOnly: true,
+				DB: func() *sql.DB {
+					db := setupTestDB(t)
+					if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, age INTEGER)"); err != nil {
+						t.Fatalf("Failed to set up database for drop: %v", err)
+					}
+					return db
+				}(),
+			},
+			args: args{
+				ctx: ctx,
+				params: []tools.ParamValue{
+					{Name: "sql", Value: "DROP TABLE users"},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "select data over max rows",
+			fields: fields{
+				ResultLimits: sources.ResultLimits{MaxRows: 1},
+				DB: func() *sql.DB {
+					db := setupTestDB(t)
+					if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, age INTEGER); INSERT INTO users (id, name, age) VALUES (1, 'Alice', 30), (2, 'Bob', 25)"); err != nil {
+						t.Fatalf("Failed to set up database for select: %v", err)
+					}
+					return db
+				}(),
+			},
+			args: args{
+				ctx: ctx,
+				params: []tools.ParamValue{
+					{Name: "sql", Value: "SELECT * FROM users ORDER BY id"},
+				},
+			},
+			want: tools.TruncatedRows{
+				Rows: []any{
+					map[string]any{"id": int64(1), "name": "Alice", "age": int64(30)},
+				},
+				Truncated: true,
+				RowCount:  1,
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid sql",
 			fields: fields{
@@ -277,6 +460,8 @@ func TestTool_Invoke(t *testing.T) {
 				Kind:         tt.fields.Kind,
 				AuthRequired: tt.fields.AuthRequired,
 				Parameters:   tt.fields.Parameters,
+				ReadOnly:     tt.fields.ReadOnly,
+				ResultLimits: tt.fields.ResultLimits,
 				DB:           tt.fields.DB,
 			}
 			got, err := tr.Invoke(tt.args.ctx, tt.args.params, tt.args.accessToken)
@@ -285,7 +470,7 @@ func TestTool_Invoke(t *testing.T) {
 				return
 			}
 			isEqual := false
-			if got != nil && len(got.([]any)) == 0 && len(tt.want.([]any)) == 0 {
+			if rows, ok := got.([]any); ok && len(rows) == 0 && len(tt.want.([]any)) == 0 {
 				isEqual = true // Special case for empty slices, since DeepEqual returns false
 			} else {
 				isEqual = reflect.DeepEqual(got, tt.want)
@@ -297,3 +482,200 @@ func TestTool_Invoke(t *testing.T) {
 		})
 	}
 }
+
+func TestTool_InvokeReadOnly(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	db := setupTestDB(t)
+	// keep a single connection, so that the in-memory database is shared
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("Failed to set up database: %v", err)
+	}
+
+	// read-only queries run with query_only enabled
+	tr := &sqliteexecutesql.Tool{DB: db, ReadOnly: true}
+	params := []tools.ParamValue{{Name: "sql", Value: "SELECT * FROM pragma_query_only"}}
+	got, err := tr.Invoke(ctx, params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []any{map[string]any{"query_only": int64(1)}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect result: diff %v", diff)
+	}
+
+	// query_only is reset once the query is done
+	tw := &sqliteexecutesql.Tool{DB: db}
+	params = []tools.ParamValue{{Name: "sql", Value: "INSERT INTO users (id, name) VALUES (1, 'Alice')"}}
+	if _, err := tw.Invoke(ctx, params, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestTool_InvokeTimeout(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tr := &sqliteexecutesql.Tool{DB: setupTestDB(t), Timeout: 50 * time.Millisecond}
+	// the query never ends, unless it is interrupted
+	params := []tools.ParamValue{
+		{Name: "sql", Value: "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT count(*) FROM c"},
+	}
+	_, err = tools.InvokeWithTimeout(ctx, tr, params, "")
+	if !errors.Is(err, tools.ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestTool_InvokePagination(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	signer, err := tools.NewPageTokenSigner(nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = tools.WithPageTokenSigner(ctx, signer)
+	tr := &sqliteexecutesql.Tool{Name: "execute_sql", DB: setupTestDB(t), PageSize: 2}
+	params := tools.ParamValues{
+		{Name: "sql", Value: "SELECT 1 AS id UNION ALL SELECT 2 UNION ALL SELECT 3 ORDER BY id"},
+	}
+
+	got, err := tr.Invoke(ctx, params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	first, ok := got.(tools.PagedRows)
+	if !ok {
+		t.Fatalf("unexpected result type %T", got)
+	}
+	want := []any{map[string]any{"id": int64(1)}, map[string]any{"id": int64(2)}}
+	if diff := cmp.Diff(want, first.Rows); diff != "" {
+		t.Fatalf("incorrect first page (-want +got):\n%s", diff)
+	}
+	if first.NextPageToken == "" {
+		t.Fatalf("expected the token of the next page")
+	}
+
+	params = append(params, tools.ParamValue{Name: tools.PageTokenParameter, Value: first.NextPageToken})
+	got, err = tr.Invoke(ctx, params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantLast := tools.PagedRows{Rows: []any{map[string]any{"id": int64(3)}}}
+	if diff := cmp.Diff(wantLast, got); diff != "" {
+		t.Fatalf("incorrect last page (-want +got):\n%s", diff)
+	}
+
+	params[0].Value = "SELECT 1 AS id ORDER BY id"
+	if _, err := tr.Invoke(ctx, params, ""); !errors.Is(err, tools.ErrInvalidPageToken) {
+		t.Fatalf("expected ErrInvalidPageToken for another statement, got %v", err)
+	}
+
+	params = params[:1]
+	params[0].Value = "SELECT 1 AS id"
+	if _, err := tr.Invoke(ctx, params, ""); !errors.Is(err, sqlclassifier.ErrNoOrderBy) {
+		t.Fatalf("expected ErrNoOrderBy for a query without ORDER BY, got %v", err)
+	}
+}
+
+func TestTool_InvokeKeysetPagination(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	signer, err := tools.NewPageTokenSigner(nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = tools.WithPageTokenSigner(ctx, signer)
+	db := setupTestDB(t)
+	// every connection has its own in-memory database
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE t (id INTEGER, name TEXT); INSERT INTO t VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, NULL)"); err != nil {
+		t.Fatalf("unable to create table: %s", err)
+	}
+	tr := &sqliteexecutesql.Tool{Name: "execute_sql", DB: db, PageSize: 2}
+	params := tools.ParamValues{{Name: "sql", Value: "SELECT id, name FROM t ORDER BY name DESC, id"}}
+
+	row := func(id int64, name any) any { return map[string]any{"id": id, "name": name} }
+	want := [][]any{
+		{row(4, "d"), row(3, "c")},
+		// the row added before the second page does not shift it
+		{row(2, "b"), row(1, "a")},
+		{row(5, nil)},
+	}
+	for i, wantRows := range want {
+		got, err := tr.Invoke(ctx, params, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		page, ok := got.(tools.PagedRows)
+		if !ok {
+			t.Fatalf("unexpected result type %T", got)
+		}
+		if diff := cmp.Diff(wantRows, page.Rows); diff != "" {
+			t.Fatalf("incorrect page %d (-want +got):\n%s", i+1, diff)
+		}
+		if (page.NextPageToken == "") != (i == len(want)-1) {
+			t.Fatalf("unexpected token of the next page of page %d: %q", i+1, page.NextPageToken)
+		}
+		if i == 0 {
+			if _, err := db.Exec("INSERT INTO t VALUES (6, 'cc')"); err != nil {
+				t.Fatalf("unable to insert row: %s", err)
+			}
+		}
+		params = tools.ParamValues{params[0], {Name: tools.PageTokenParameter, Value: page.NextPageToken}}
+	}
+}
+
+func TestTool_InvokeResultFormat(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tr := &sqliteexecutesql.Tool{DB: setupTestDB(t), ResultFormat: tools.CSV}
+	params := tools.ParamValues{
+		{Name: "sql", Value: "SELECT 2 AS z, 'x,y' AS a UNION ALL SELECT 1, NULL"},
+	}
+
+	tcs := []struct {
+		desc   string
+		format tools.ResultFormat
+		want   string
+	}{
+		{
+			desc: "format of the tool",
+			want: "z,a\n2,\"x,y\"\n1,\n",
+		},
+		{
+			desc:   "requested format",
+			format: tools.Markdown,
+			want:   "| z | a |\n| --- | --- |\n| 2 | x,y |\n| 1 |  |\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tr.Invoke(tools.WithResultFormat(ctx, tc.format), params, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			e, ok := got.(tools.EncodedRows)
+			if !ok {
+				t.Fatalf("unexpected result type %T", got)
+			}
+			text, err := e.Text()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, text); diff != "" {
+				t.Fatalf("incorrect text (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// ErrTimeout is returned when a tool invocation exceeds the timeout of the
+// tool.
+var ErrTimeout = errors.New("query timed out")
+
+// Timeouter is an optional interface for tools that cancel their invocations
+// once a timeout elapses.
+type Timeouter interface {
+	ToolTimeout() time.Duration
+}
+
+// GetTimeout parses the timeout of a tool, or the query timeout of its source
+// if the tool does not set one. A timeout of 0 means no timeout.
+func GetTimeout(timeout string, s sources.Source) (time.Duration, error) {
+	if timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timeout %q: %w", timeout, err)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("invalid timeout %q: must not be negative", timeout)
+		}
+		return d, nil
+	}
+	qt, ok := s.(sources.QueryTimeouter)
+	if !ok || qt.SourceQueryTimeout() == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(qt.SourceQueryTimeout())
+	if err != nil {
+		return 0, fmt.Errorf("invalid queryTimeout %q of source: %w", qt.SourceQueryTimeout(), err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid queryTimeout %q of source: must not be negative", qt.SourceQueryTimeout())
+	}
+	return d, nil
+}
+
+// InvokeWithTimeout invokes a tool with a context that is cancelled once the
+// timeout of the tool elapses, which makes the database drivers cancel the
+// running query. An invocation that fails because of the timeout returns an
+// error wrapping ErrTimeout.
+func InvokeWithTimeout(ctx context.Context, tool Tool, params ParamValues, accessToken AccessToken) (any, error) {
+	t, ok := tool.(Timeouter)
+	if !ok || t.ToolTimeout() <= 0 {
+		return tool.Invoke(ctx, params, accessToken)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, t.ToolTimeout(), ErrTimeout)
+	defer cancel()
+	res, err := tool.Invoke(ctx, params, accessToken)
+	if err != nil && errors.Is(context.Cause(ctx), ErrTimeout) {
+		return nil, fmt.Errorf("%w after %s", ErrTimeout, t.ToolTimeout())
+	}
+	return res, err
+}
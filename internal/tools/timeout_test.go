@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// timeoutSource is a source with a default query timeout.
+type timeoutSource struct {
+	queryTimeout string
+}
+
+func (s timeoutSource) SourceKind() string {
+	return "timeout"
+}
+
+func (s timeoutSource) SourceQueryTimeout() string {
+	return s.queryTimeout
+}
+
+func TestGetTimeout(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		timeout string
+		source  sources.Source
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			desc:   "no timeout",
+			source: timeoutSource{},
+			want:   0,
+		},
+		{
+			desc:   "source default",
+			source: timeoutSource{queryTimeout: "30s"},
+			want:   30 * time.Second,
+		},
+		{
+			desc:    "tool overrides",
+			timeout: "5s",
+			source:  timeoutSource{queryTimeout: "30s"},
+			want:    5 * time.Second,
+		},
+		{
+			desc:    "source without default",
+			timeout: "1m",
+			source:  limitedSource{},
+			want:    time.Minute,
+		},
+		{
+			desc:    "invalid timeout",
+			timeout: "5",
+			source:  timeoutSource{},
+			wantErr: true,
+		},
+		{
+			desc:    "negative timeout",
+			timeout: "-5s",
+			source:  timeoutSource{},
+			wantErr: true,
+		},
+		{
+			desc:    "invalid source default",
+			source:  timeoutSource{queryTimeout: "forever"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tools.GetTimeout(tc.timeout, tc.source)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, wantErr %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect timeout: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+// slowTool is a tool whose invocations take a while, unless cancelled.
+type slowTool struct {
+	tools.Tool
+	timeout time.Duration
+	delay   time.Duration
+}
+
+func (t slowTool) Invoke(ctx context.Context, _ tools.ParamValues, _ tools.AccessToken) (any, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(t.delay):
+		return "done", nil
+	}
+}
+
+func (t slowTool) ToolTimeout() time.Duration {
+	return t.timeout
+}
+
+func TestInvokeWithTimeout(t *testing.T) {
+	t.Run("timed out", func(t *testing.T) {
+		tool := slowTool{timeout: 10 * time.Millisecond, delay: time.Minute}
+		_, err := tools.InvokeWithTimeout(context.Background(), tool, nil, "")
+		if !errors.Is(err, tools.ErrTimeout) {
+			t.Fatalf("expected ErrTimeout, got %v", err)
+		}
+		if want := "query timed out after 10ms"; err.Error() != want {
+			t.Fatalf("incorrect error: got %q, want %q", err, want)
+		}
+	})
+
+	t.Run("timed out with policies", func(t *testing.T) {
+		policy, err := tools.ParsePolicy(`tool "slow"`, `claims.email endsWith "@corp.com"`)
+		if err != nil {
+			t.Fatalf("unable to parse policy: %s", err)
+		}
+		tool := tools.WithPolicies(slowTool{timeout: 10 * time.Millisecond, delay: time.Minute}, policy)
+		_, err = tools.InvokeWithTimeout(context.Background(), tool, nil, "")
+		if !errors.Is(err, tools.ErrTimeout) {
+			t.Fatalf("expected ErrTimeout, got %v", err)
+		}
+	})
+
+	t.Run("within timeout", func(t *testing.T) {
+		tool := slowTool{timeout: time.Minute, delay: time.Millisecond}
+		got, err := tools.InvokeWithTimeout(context.Background(), tool, nil, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != "done" {
+			t.Fatalf("incorrect result: got %v", got)
+		}
+	})
+
+	t.Run("cancelled by the caller", func(t *testing.T) {
+		tool := slowTool{timeout: time.Minute, delay: time.Minute}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := tools.InvokeWithTimeout(ctx, tool, nil, "")
+		if err == nil || errors.Is(err, tools.ErrTimeout) {
+			t.Fatalf("expected the error of the caller, got %v", err)
+		}
+	})
+}
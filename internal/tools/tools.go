@@ -65,6 +65,28 @@ type ToolConfig interface {
 	Initialize(map[string]sources.Source) (Tool, error)
 }
 
+// SourceToolConfig is implemented by the configs of the tools that use a
+// source. It describes the tool without initializing it, so that the tool can
+// be listed before its source is initialized.
+type SourceToolConfig interface {
+	ToolConfig
+	ToolConfigInfo() ToolConfigInfo
+}
+
+// ToolConfigInfo describes the tool of a SourceToolConfig.
+type ToolConfigInfo struct {
+	// Source is the name of the source used by the tool.
+	Source       string
+	Description  string
+	AuthRequired []string
+	// Parameters and TemplateParameters are the parameters declared by the
+	// config. Parameters that the tool adds when it is initialized are not
+	// included, except for the pageToken parameter added by PageSize.
+	Parameters         Parameters
+	TemplateParameters Parameters
+	PageSize           int
+}
+
 type AccessToken string
 
 func (token AccessToken) ParseBearerToken() (string, error) {
@@ -89,6 +111,8 @@ type Manifest struct {
 	Description  string              `json:"description"`
 	Parameters   []ParameterManifest `json:"parameters"`
 	AuthRequired []string            `json:"authRequired"`
+	// Unavailable is the reason the tool can't be used, if any.
+	Unavailable string `json:"unavailable,omitempty"`
 }
 
 // Definition for a tool the MCP client can call.
@@ -99,7 +123,58 @@ type McpManifest struct {
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema McpToolsSchema `json:"inputSchema,omitempty"`
-	Metadata    map[string]any `json:"_meta,omitempty"`
+	// An optional JSON Schema object defining the structure of the tool's
+	// output. Only served to clients that support structured content.
+	OutputSchema *McpToolsSchema `json:"outputSchema,omitempty"`
+	Metadata     map[string]any  `json:"_meta,omitempty"`
+}
+
+// GetRowsOutputSchema returns the output schema of a tool that returns a list
+// of rows with the given columns. Rows are served as structured content in the
+// form of `{"rows": [...]}`, with `truncated` and `rowCount` markers if the
+// rows exceeded the result limits of the tool, and a `nextPageToken` if the
+// tool is paginated and has more rows. It returns nil if no columns are
+// declared.
+func GetRowsOutputSchema(columns Parameters) (*McpToolsSchema, error) {
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	if err := CheckDuplicateParameters(columns); err != nil {
+		return nil, fmt.Errorf("invalid output schema: %w", err)
+	}
+	properties := make(map[string]ParameterMcpManifest, len(columns))
+	for _, c := range columns {
+		if len(c.GetAuthServices()) > 0 {
+			return nil, fmt.Errorf("invalid output schema: column %q cannot use authServices", c.GetName())
+		}
+		properties[c.GetName()], _ = c.McpManifest()
+	}
+	return &McpToolsSchema{
+		Type: "object",
+		Properties: map[string]ParameterMcpManifest{
+			"rows": {
+				Type:        "array",
+				Description: "Rows returned by the tool. Columns with a NULL value are omitted.",
+				Items: &ParameterMcpManifest{
+					Type:       "object",
+					Properties: properties,
+				},
+			},
+			"truncated": {
+				Type:        "boolean",
+				Description: "Whether rows were left out because they exceeded the result limits.",
+			},
+			"rowCount": {
+				Type:        "integer",
+				Description: "Number of rows returned, if rows were left out.",
+			},
+			"nextPageToken": {
+				Type:        "string",
+				Description: "Token to pass as the pageToken parameter to get the next page of rows, if there is one.",
+			},
+		},
+		Required: []string{"rows"},
+	}, nil
 }
 
 func GetMcpManifest(name, desc string, authInvoke []string, params Parameters) McpManifest {
@@ -126,6 +201,10 @@ func GetMcpManifest(name, desc string, authInvoke []string, params Parameters) M
 
 var ErrUnauthorized = errors.New("unauthorized")
 
+// ErrUnavailable is returned when a tool can't be used because its source
+// failed to initialize.
+var ErrUnavailable = errors.New("unavailable")
+
 // Helper function that returns if a tool invocation request is authorized
 func IsAuthorized(authRequiredSources []string, verifiedAuthServices []string) bool {
 	if len(authRequiredSources) == 0 {
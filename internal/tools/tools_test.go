@@ -91,3 +91,58 @@ func TestGetMcpManifestMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRowsOutputSchema(t *testing.T) {
+	got, err := tools.GetRowsOutputSchema(nil)
+	if err != nil || got != nil {
+		t.Fatalf("unexpected output schema without columns: got %+v, %v", got, err)
+	}
+
+	got, err = tools.GetRowsOutputSchema(tools.Parameters{
+		tools.NewIntParameter("id", "hotel id"),
+		tools.NewStringParameter("name", "hotel name"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := &tools.McpToolsSchema{
+		Type: "object",
+		Properties: map[string]tools.ParameterMcpManifest{
+			"rows": {
+				Type:        "array",
+				Description: "Rows returned by the tool. Columns with a NULL value are omitted.",
+				Items: &tools.ParameterMcpManifest{
+					Type: "object",
+					Properties: map[string]tools.ParameterMcpManifest{
+						"id":   {Type: "integer", Description: "hotel id"},
+						"name": {Type: "string", Description: "hotel name"},
+					},
+				},
+			},
+			"truncated": {
+				Type:        "boolean",
+				Description: "Whether rows were left out because they exceeded the result limits.",
+			},
+			"rowCount": {
+				Type:        "integer",
+				Description: "Number of rows returned, if rows were left out.",
+			},
+			"nextPageToken": {
+				Type:        "string",
+				Description: "Token to pass as the pageToken parameter to get the next page of rows, if there is one.",
+			},
+		},
+		Required: []string{"rows"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected output schema: diff %v", diff)
+	}
+
+	_, err = tools.GetRowsOutputSchema(tools.Parameters{
+		tools.NewIntParameter("id", "hotel id"),
+		tools.NewIntParameter("id", "other id"),
+	})
+	if err == nil {
+		t.Fatalf("expected error for duplicate columns")
+	}
+}
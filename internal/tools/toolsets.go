@@ -15,12 +15,20 @@
 package tools
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
 )
 
+// ErrInvalidCursor is returned when a pagination cursor is malformed or no
+// longer refers to a tool of the toolset.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type ToolsetConfig struct {
 	Name      string   `yaml:"name"`
 	ToolNames []string `yaml:",inline"`
+	// Allow is an optional Policy that applies to every tool of the toolset.
+	Allow string `yaml:"allow"`
 }
 
 type Toolset struct {
@@ -33,6 +41,8 @@ type Toolset struct {
 type ToolsetManifest struct {
 	ServerVersion string              `json:"serverVersion"`
 	ToolsManifest map[string]Manifest `json:"tools"`
+	// NextCursor is set if the manifest is paginated and there are more tools.
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool) (Toolset, error) {
@@ -60,3 +70,73 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 
 	return toolset, nil
 }
+
+// Filter returns a toolset that only contains the tools for which keep
+// returns true, in the same order.
+func (t Toolset) Filter(keep func(name string) bool) Toolset {
+	filtered := Toolset{
+		Name: t.Name,
+		Manifest: ToolsetManifest{
+			ServerVersion: t.Manifest.ServerVersion,
+			ToolsManifest: make(map[string]Manifest),
+		},
+	}
+	for _, m := range t.McpManifest {
+		if !keep(m.Name) {
+			continue
+		}
+		filtered.McpManifest = append(filtered.McpManifest, m)
+		filtered.Manifest.ToolsManifest[m.Name] = t.Manifest.ToolsManifest[m.Name]
+	}
+	return filtered
+}
+
+// Paginate returns a toolset that only contains the tools of the page starting
+// after the cursor, along with the cursor of the next page. The next cursor is
+// empty if there are no more tools. A page size of 0 or less returns all the
+// remaining tools.
+func (t Toolset) Paginate(cursor string, pageSize int) (Toolset, string, error) {
+	start := 0
+	if cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return Toolset{}, "", ErrInvalidCursor
+		}
+		start = -1
+		for i, m := range t.McpManifest {
+			if m.Name == string(after) {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return Toolset{}, "", ErrInvalidCursor
+		}
+	}
+	if start == 0 && (pageSize <= 0 || pageSize >= len(t.McpManifest)) {
+		return t, "", nil
+	}
+
+	end := len(t.McpManifest)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+	page := Toolset{
+		Name: t.Name,
+		Manifest: ToolsetManifest{
+			ServerVersion: t.Manifest.ServerVersion,
+			ToolsManifest: make(map[string]Manifest, end-start),
+		},
+		McpManifest: t.McpManifest[start:end],
+	}
+	for _, m := range page.McpManifest {
+		page.Manifest.ToolsManifest[m.Name] = t.Manifest.ToolsManifest[m.Name]
+	}
+
+	var next string
+	if end < len(t.McpManifest) {
+		next = base64.RawURLEncoding.EncodeToString([]byte(t.McpManifest[end-1].Name))
+		page.Manifest.NextCursor = next
+	}
+	return page, next, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestPaginate(t *testing.T) {
+	toolset := tools.Toolset{
+		Name: "my_toolset",
+		Manifest: tools.ToolsetManifest{
+			ServerVersion: "0.0.0",
+			ToolsManifest: map[string]tools.Manifest{},
+		},
+	}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		toolset.McpManifest = append(toolset.McpManifest, tools.McpManifest{Name: name})
+		toolset.Manifest.ToolsManifest[name] = tools.Manifest{Description: name}
+	}
+
+	// collect all pages
+	pageNames := func(pageSize int) [][]string {
+		var pages [][]string
+		cursor := ""
+		for {
+			page, next, err := toolset.Paginate(cursor, pageSize)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var names []string
+			for _, m := range page.McpManifest {
+				names = append(names, m.Name)
+				if _, ok := page.Manifest.ToolsManifest[m.Name]; !ok {
+					t.Fatalf("tool %q is missing from the page manifest", m.Name)
+				}
+			}
+			if len(page.Manifest.ToolsManifest) != len(names) {
+				t.Fatalf("unexpected number of tools in page manifest: got %d, want %d", len(page.Manifest.ToolsManifest), len(names))
+			}
+			if page.Manifest.NextCursor != next {
+				t.Fatalf("unexpected next cursor in page manifest: got %q, want %q", page.Manifest.NextCursor, next)
+			}
+			pages = append(pages, names)
+			if next == "" {
+				return pages
+			}
+			cursor = next
+		}
+	}
+
+	tcs := []struct {
+		desc     string
+		pageSize int
+		want     [][]string
+	}{
+		{
+			desc:     "pagination disabled",
+			pageSize: 0,
+			want:     [][]string{{"a", "b", "c", "d", "e"}},
+		},
+		{
+			desc:     "page size larger than toolset",
+			pageSize: 10,
+			want:     [][]string{{"a", "b", "c", "d", "e"}},
+		},
+		{
+			desc:     "page size of 2",
+			pageSize: 2,
+			want:     [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		{
+			desc:     "page size matching toolset",
+			pageSize: 5,
+			want:     [][]string{{"a", "b", "c", "d", "e"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := pageNames(tc.pageSize)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect pages: diff %v", diff)
+			}
+		})
+	}
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "eg"} {
+			_, _, err := toolset.Paginate(cursor, 2)
+			if !errors.Is(err, tools.ErrInvalidCursor) {
+				t.Fatalf("unexpected error for cursor %q: got %v, want %v", cursor, err, tools.ErrInvalidCursor)
+			}
+		}
+	})
+
+	t.Run("does not modify toolset", func(t *testing.T) {
+		_ = pageNames(2)
+		if len(toolset.McpManifest) != 5 || len(toolset.Manifest.ToolsManifest) != 5 {
+			t.Fatalf("toolset was modified by pagination")
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	toolset := tools.Toolset{
+		Name: "my_toolset",
+		Manifest: tools.ToolsetManifest{
+			ServerVersion: "0.0.0",
+			ToolsManifest: map[string]tools.Manifest{},
+		},
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		toolset.McpManifest = append(toolset.McpManifest, tools.McpManifest{Name: name})
+		toolset.Manifest.ToolsManifest[name] = tools.Manifest{Description: name}
+	}
+
+	got := toolset.Filter(func(name string) bool { return name != "b" })
+	want := tools.Toolset{
+		Name: "my_toolset",
+		Manifest: tools.ToolsetManifest{
+			ServerVersion: "0.0.0",
+			ToolsManifest: map[string]tools.Manifest{
+				"a": {Description: "a"},
+				"c": {Description: "c"},
+			},
+		},
+		McpManifest: []tools.McpManifest{{Name: "a"}, {Name: "c"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected filtered toolset: diff %v", diff)
+	}
+	if len(toolset.McpManifest) != 3 || len(toolset.Manifest.ToolsManifest) != 3 {
+		t.Fatalf("original toolset was modified: %+v", toolset)
+	}
+}
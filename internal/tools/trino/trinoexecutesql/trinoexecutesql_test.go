@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/trino/trinoexecutesql"
 )
 
@@ -56,6 +57,27 @@ func TestParseFromYamlTrinoExecuteSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "result format example",
+			in: `
+			tools:
+				example_tool:
+					kind: trino-execute-sql
+					source: my-trino-instance
+					description: some description
+					resultFormat: columnar-json
+			`,
+			want: server.ToolConfigs{
+				"example_tool": trinoexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "trino-execute-sql",
+					Source:       "my-trino-instance",
+					Description:  "some description",
+					ResultFormat: tools.ColumnarJSON,
+					AuthRequired: []string{},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
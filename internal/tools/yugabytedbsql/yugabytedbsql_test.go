@@ -20,6 +20,7 @@ import (
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/yugabytedbsql"
@@ -77,6 +78,53 @@ func TestParseFromYamlYugabyteDBSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with result limits",
+			in: `
+			tools:
+			  hotel_search:
+			    kind: yugabytedb-sql
+			    source: yb-source
+			    description: search hotels
+			    statement: SELECT * FROM hotels;
+			    maxRows: 100
+			    maxResultBytes: 65536
+			`,
+			want: server.ToolConfigs{
+				"hotel_search": yugabytedbsql.Config{
+					ResultLimits: sources.ResultLimits{MaxRows: 100, MaxResultBytes: 65536},
+					Name:         "hotel_search",
+					Kind:         "yugabytedb-sql",
+					Source:       "yb-source",
+					Description:  "search hotels",
+					Statement:    "SELECT * FROM hotels;",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with result format",
+			in: `
+			tools:
+			  hotel_search:
+			    kind: yugabytedb-sql
+			    source: yb-source
+			    description: search hotels
+			    statement: SELECT * FROM hotels;
+			    resultFormat: markdown
+			`,
+			want: server.ToolConfigs{
+				"hotel_search": yugabytedbsql.Config{
+					Name:         "hotel_search",
+					Kind:         "yugabytedb-sql",
+					Source:       "yb-source",
+					Description:  "search hotels",
+					Statement:    "SELECT * FROM hotels;",
+					ResultFormat: tools.Markdown,
+					AuthRequired: []string{},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -126,6 +174,18 @@ func TestFailParseFromYamlYugabyteDBSQL(t *testing.T) {
 			    foo: bar
 			`,
 		},
+		{
+			desc: "invalid result format",
+			in: `
+			tools:
+			  tool3:
+			    kind: yugabytedb-sql
+			    source: yb-source
+			    description: test
+			    statement: SELECT 1;
+			    resultFormat: xml
+			`,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
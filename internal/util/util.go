@@ -162,3 +162,22 @@ func InstrumentationFromContext(ctx context.Context) (*telemetry.Instrumentation
 	}
 	return nil, fmt.Errorf("unable to retrieve instrumentation")
 }
+
+// ProgressReporter reports the progress of a long-running request to the
+// client. Total is 0 if the total is unknown.
+type ProgressReporter func(ctx context.Context, progress, total float64, message string)
+
+const progressReporterKey contextKey = "progressReporter"
+
+// WithProgressReporter adds a progress reporter into the context as a value
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey, reporter)
+}
+
+// ReportProgress reports the progress of the current request if the client
+// asked to be notified. Otherwise, it is a no-op.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if reporter, ok := ctx.Value(progressReporterKey).(ProgressReporter); ok {
+		reporter(ctx, progress, total, message)
+	}
+}
@@ -162,6 +162,7 @@ func TestPostgres(t *testing.T) {
 	toolsFile = tests.AddTemplateParamConfig(t, toolsFile, PostgresToolKind, tmplSelectCombined, tmplSelectFilterCombined, "")
 
 	toolsFile = addPrebuiltToolConfig(t, toolsFile)
+	toolsFile = addTimeoutToolConfig(t, toolsFile)
 
 	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile, args...)
 	if err != nil {
@@ -192,6 +193,45 @@ func TestPostgres(t *testing.T) {
 	runPostgresListActiveQueriesTest(t, ctx, pool)
 	runPostgresListAvailableExtensionsTest(t)
 	runPostgresListInstalledExtensionsTest(t)
+	runPostgresTimeoutTest(t)
+}
+
+func addTimeoutToolConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-timeout-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool that runs longer than its timeout.",
+		"statement":   "SELECT pg_sleep(10);",
+		"timeout":     "500ms",
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresTimeoutTest(t *testing.T) {
+	api := "http://127.0.0.1:5000/api/tool/my-timeout-tool/invoke"
+	start := time.Now()
+	resp, err := http.Post(api, "application/json", bytes.NewBuffer([]byte(`{}`)))
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("response status code is not 504, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+	if !strings.Contains(string(bodyBytes), "query timed out after 500ms") {
+		t.Fatalf("unexpected error: %s", string(bodyBytes))
+	}
+	// the query is cancelled on the server instead of running to completion
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("the query was not cancelled, the invocation took %s", elapsed)
+	}
 }
 
 func runPostgresListTablesTest(t *testing.T, tableNameParam, tableNameAuth string) {